@@ -0,0 +1,18 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// cacheCmd executes 'cache' CLI commands
+var cacheCmd = &cobra.Command{
+	Use:                "cache",
+	Short:              "Manage the Atmos caches",
+	Long:               "This command manages the on-disk caches of Atmos, such as the cache of the component outputs used by the '!terraform.output' YAML function and the 'atmos.Component' template function.",
+	FParseErrWhitelist: struct{ UnknownFlags bool }{UnknownFlags: false},
+	Args:               cobra.NoArgs,
+}
+
+func init() {
+	RootCmd.AddCommand(cacheCmd)
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	e "github.com/cloudposse/atmos/internal/exec"
+	u "github.com/cloudposse/atmos/pkg/utils"
+)
+
+// cacheClearCmd executes 'cache clear' CLI commands
+var cacheClearCmd = &cobra.Command{
+	Use:                "clear",
+	Short:              "Remove the cached component outputs",
+	Long:               "This command removes the cached outputs of all components, or of the specified component or the components in the specified stack, from the outputs cache.",
+	FParseErrWhitelist: struct{ UnknownFlags bool }{UnknownFlags: false},
+	Args:               cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Check Atmos configuration
+		checkAtmosConfig()
+
+		err := e.ExecuteCacheClearCmd(cmd, args)
+		if err != nil {
+			u.PrintErrorMarkdownAndExit("", err, "")
+		}
+	},
+}
+
+func init() {
+	cacheClearCmd.PersistentFlags().StringP("stack", "s", "", "Only remove the cached outputs of the components in the stack")
+	cacheClearCmd.PersistentFlags().StringP("component", "c", "", "Only remove the cached outputs of the component")
+	cacheCmd.AddCommand(cacheClearCmd)
+}
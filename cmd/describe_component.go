@@ -33,6 +33,7 @@ func init() {
 	describeComponentCmd.PersistentFlags().Bool("process-templates", true, "Enable/disable Go template processing in Atmos stack manifests when executing the command")
 	describeComponentCmd.PersistentFlags().Bool("process-functions", true, "Enable/disable YAML functions processing in Atmos stack manifests when executing the command")
 	describeComponentCmd.PersistentFlags().StringSlice("skip", nil, "Skip executing a YAML function in the Atmos stack manifests when executing the command")
+	describeComponentCmd.PersistentFlags().Bool("provenance", false, "Annotate each value with the stack manifest file, line and column where it's defined, and the import or inheritance step that set it")
 
 	err := describeComponentCmd.MarkPersistentFlagRequired("stack")
 	if err != nil {
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	e "github.com/cloudposse/atmos/internal/exec"
+	u "github.com/cloudposse/atmos/pkg/utils"
+)
+
+// historyCmd executes 'history' CLI commands
+var historyCmd = &cobra.Command{
+	Use:                "history",
+	Short:              "List the past Terraform and Helmfile commands from the audit log",
+	Long:               "This command lists the Terraform and Helmfile commands recorded in the audit log, with options to filter them by stack, component, user, command and time.",
+	FParseErrWhitelist: struct{ UnknownFlags bool }{UnknownFlags: false},
+	Args:               cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Check Atmos configuration
+		checkAtmosConfig()
+
+		err := e.ExecuteHistoryCmd(cmd, args)
+		if err != nil {
+			u.PrintErrorMarkdownAndExit("", err, "")
+		}
+	},
+}
+
+func init() {
+	historyCmd.PersistentFlags().StringP("stack", "s", "", "Only list the commands executed in the stack")
+	historyCmd.PersistentFlags().StringP("component", "c", "", "Only list the commands executed for the component")
+	historyCmd.PersistentFlags().String("user", "", "Only list the commands executed by the user")
+	historyCmd.PersistentFlags().String("command", "", "Only list the commands of the tool: terraform or helmfile")
+	historyCmd.PersistentFlags().String("subcommand", "", "Only list the subcommand (e.g. 'apply')")
+	historyCmd.PersistentFlags().String("since", "", "Only list the commands executed since the duration (e.g. '12h', '7d'), date or RFC3339 timestamp")
+	historyCmd.PersistentFlags().Bool("failed", false, "Only list the failed commands")
+	historyCmd.PersistentFlags().Int("limit", 20, "The maximum number of the most recent commands to list (0 to list all)")
+	historyCmd.PersistentFlags().StringP("format", "f", "table", "The output format: table or json")
+
+	AddStackCompletion(historyCmd)
+	RootCmd.AddCommand(historyCmd)
+}
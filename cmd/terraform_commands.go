@@ -255,6 +255,7 @@ Arguments:
 func attachTerraformCommands(parentCmd *cobra.Command) {
 	parentCmd.PersistentFlags().String("append-user-agent", "", fmt.Sprintf("Sets the TF_APPEND_USER_AGENT environment variable to customize the User-Agent string in Terraform provider requests. Example: `Atmos/%s (Cloud Posse; +https://atmos.tools)`. This flag works with almost all commands.", version.Version))
 	parentCmd.PersistentFlags().Bool("skip-init", false, "Skip running `terraform init` before executing the command")
+	parentCmd.PersistentFlags().String("workspace-lock-timeout", "", "The duration to wait for the lock of the Terraform workspace in the component folder if it's held by another Atmos process (e.g. `5m`). By default, the command fails immediately")
 
 	commands := getTerraformCommands()
 
@@ -279,14 +280,25 @@ func attachTerraformCommands(parentCmd *cobra.Command) {
 }
 
 var commandMaps = map[string]func(cmd *cobra.Command){
+	"plan": func(cmd *cobra.Command) {
+		cmd.PersistentFlags().Bool("summary", false, "Summarize the changes in the planfile (resources to add, change, destroy and replace, and changed outputs)")
+		cmd.PersistentFlags().String("summary-format", "markdown", "The format of the plan summary file: markdown or json")
+		cmd.PersistentFlags().String("summary-file", "", "Write the plan summary to the file, e.g. to post it as a pull request comment")
+		addMultiComponentFlags(cmd)
+	},
 	"deploy": func(cmd *cobra.Command) {
 		cmd.PersistentFlags().Bool("deploy-run-init", false, "If set atmos will run `terraform init` before executing the command")
 		cmd.PersistentFlags().Bool("from-plan", false, "If set atmos will use the previously generated plan file")
 		cmd.PersistentFlags().String("planfile", "", "Set the plan file to use")
+		addMultiComponentFlags(cmd)
 	},
 	"apply": func(cmd *cobra.Command) {
 		cmd.PersistentFlags().Bool("from-plan", false, "If set atmos will use the previously generated plan file")
 		cmd.PersistentFlags().String("planfile", "", "Set the plan file to use")
+		addMultiComponentFlags(cmd)
+	},
+	"destroy": func(cmd *cobra.Command) {
+		addMultiComponentFlags(cmd)
 	},
 	"clean": func(cmd *cobra.Command) {
 		cmd.PersistentFlags().Bool("everything", false, "If set atmos will also delete the Terraform state files and directories for the component.")
@@ -294,3 +306,22 @@ var commandMaps = map[string]func(cmd *cobra.Command){
 		cmd.PersistentFlags().Bool("skip-lock-file", false, "Skip deleting the `.terraform.lock.hcl` file")
 	},
 }
+
+// addMultiComponentFlags adds the flags to execute the command for multiple components (all, affected, or selected with a query)
+// in the order of their dependencies
+func addMultiComponentFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().Bool("all", false, "Execute the command for all components in all stacks (or in the stack specified with `--stack`) in the order of the dependencies defined in `settings.depends_on`")
+	cmd.PersistentFlags().String("components", "", "Execute the command for the comma-separated list of components in the order of their dependencies")
+	cmd.PersistentFlags().String("query", "", "Execute the command for the components for which the `yq` expression evaluates to `true`, e.g. `.vars.tags.team == \"eks\"`")
+	cmd.PersistentFlags().Int("max-concurrency", 4, "The maximum number of components to execute in parallel when used with `--all`, `--affected`, `--components` or `--query`")
+
+	// The same flags as in `atmos describe affected`
+	cmd.PersistentFlags().Bool("affected", false, "Execute the command for the components affected by the changes in the current branch (compared to the target reference) in the order of their dependencies")
+	cmd.PersistentFlags().String("ref", "", "Git reference with which to compare the current branch. Used with `--affected`")
+	cmd.PersistentFlags().String("sha", "", "Git commit SHA with which to compare the current branch. Used with `--affected`")
+	cmd.PersistentFlags().String("repo-path", "", "Filesystem path to the already cloned target repository with which to compare the current branch. Used with `--affected`")
+	cmd.PersistentFlags().String("ssh-key", "", "Path to PEM-encoded private key to clone private repos using SSH. Used with `--affected`")
+	cmd.PersistentFlags().String("ssh-key-password", "", "Encryption password for the PEM-encoded private key if the key contains a password-encrypted PEM block. Used with `--affected`")
+	cmd.PersistentFlags().Bool("clone-target-ref", false, "Clone the target reference with which to compare the current branch instead of checking it out. Used with `--affected`")
+	cmd.PersistentFlags().Bool("include-dependents", false, "Also execute the command for the dependents of the affected components. Used with `--affected`")
+}
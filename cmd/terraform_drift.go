@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	e "github.com/cloudposse/atmos/internal/exec"
+	u "github.com/cloudposse/atmos/pkg/utils"
+)
+
+// terraformDriftCmd detects drift of all Terraform components in the stacks
+var terraformDriftCmd = &cobra.Command{
+	Use:   "drift",
+	Short: "Detect drift of Terraform components in the stacks",
+	Long: `The 'atmos terraform drift' command runs 'terraform plan -detailed-exitcode' in parallel for every enabled, non-abstract Terraform component in the selected stacks.
+
+Each component is classified as 'in-sync', 'drifted' or 'errored', and the report is printed as a table, JSON, Markdown or JUnit XML.
+The drift status of each component can also be written to a store configured in the 'stores' section in 'atmos.yaml'.`,
+	Example: "atmos terraform drift\n" +
+		"atmos terraform drift -s plat-ue2-prod --format markdown --file drift.md\n" +
+		"atmos terraform drift --components vpc,eks --format junit --file drift.xml --store prod/ssm",
+	FParseErrWhitelist: struct{ UnknownFlags bool }{UnknownFlags: false},
+	Args:               cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Check Atmos configuration
+		checkAtmosConfig()
+
+		err := e.ExecuteTerraformDriftCmd(cmd, args)
+		if err != nil {
+			u.PrintErrorMarkdownAndExit("", err, "")
+		}
+	},
+}
+
+func init() {
+	terraformDriftCmd.DisableFlagParsing = false
+
+	terraformDriftCmd.PersistentFlags().String("components", "", "Only check the drift of the specified components (comma-separated values)")
+	terraformDriftCmd.PersistentFlags().String("query", "", "Only check the drift of the components for which the `yq` expression evaluates to `true`, e.g. `.vars.tags.team == \"eks\"`")
+	terraformDriftCmd.PersistentFlags().Int("max-concurrency", 4, "The maximum number of components to check in parallel")
+	terraformDriftCmd.PersistentFlags().String("format", "table", "The format of the report: `table`, `json`, `markdown` or `junit`")
+	terraformDriftCmd.PersistentFlags().String("file", "", "Write the report to the file")
+	terraformDriftCmd.PersistentFlags().String("store", "", "Write the drift status of each component to the store configured in the `stores` section in `atmos.yaml`")
+	terraformDriftCmd.PersistentFlags().Bool("dry-run", false, "Print the Terraform commands without executing them")
+	terraformDriftCmd.PersistentFlags().Bool("fail-on-drift", false, "Exit with an error if drift is detected in any component")
+
+	terraformCmd.AddCommand(terraformDriftCmd)
+}
@@ -0,0 +1,18 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// vendorCacheCmd executes 'vendor cache' CLI commands
+var vendorCacheCmd = &cobra.Command{
+	Use:                "cache",
+	Short:              "Manage the vendor download cache",
+	Long:               "This command manages the cache of the downloaded vendor sources. The sources are cached by the source URI and the resolved Git commit SHA or OCI image digest, so repeated pulls and multiple targets of the same source are copied from the cache instead of downloaded.",
+	FParseErrWhitelist: struct{ UnknownFlags bool }{UnknownFlags: false},
+	Args:               cobra.NoArgs,
+}
+
+func init() {
+	vendorCmd.AddCommand(vendorCacheCmd)
+}
@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	e "github.com/cloudposse/atmos/internal/exec"
+	u "github.com/cloudposse/atmos/pkg/utils"
+)
+
+// vendorCacheListCmd executes 'vendor cache list' CLI commands
+var vendorCacheListCmd = &cobra.Command{
+	Use:                "list",
+	Short:              "List the sources in the vendor download cache",
+	Long:               "This command lists the sources in the vendor download cache with their resolved revisions, sizes and last used times.",
+	FParseErrWhitelist: struct{ UnknownFlags bool }{UnknownFlags: false},
+	Args:               cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := e.ExecuteVendorCacheListCmd(cmd, args)
+		if err != nil {
+			u.PrintErrorMarkdownAndExit("", err, "")
+		}
+	},
+}
+
+func init() {
+	vendorCacheCmd.AddCommand(vendorCacheListCmd)
+}
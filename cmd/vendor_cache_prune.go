@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	e "github.com/cloudposse/atmos/internal/exec"
+	u "github.com/cloudposse/atmos/pkg/utils"
+)
+
+// vendorCachePruneCmd executes 'vendor cache prune' CLI commands
+var vendorCachePruneCmd = &cobra.Command{
+	Use:                "prune",
+	Short:              "Remove sources from the vendor download cache",
+	Long:               "This command removes all sources, or the sources that were not used for the specified duration, from the vendor download cache.",
+	FParseErrWhitelist: struct{ UnknownFlags bool }{UnknownFlags: false},
+	Args:               cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := e.ExecuteVendorCachePruneCmd(cmd, args)
+		if err != nil {
+			u.PrintErrorMarkdownAndExit("", err, "")
+		}
+	},
+}
+
+func init() {
+	vendorCachePruneCmd.PersistentFlags().String("older-than", "", "Only remove the sources that were not used for the specified duration (e.g. '720h')")
+	vendorCacheCmd.AddCommand(vendorCachePruneCmd)
+}
@@ -10,12 +10,12 @@ import (
 // vendorDiffCmd executes 'vendor diff' CLI commands
 var vendorDiffCmd = &cobra.Command{
 	Use:                "diff",
-	Short:              "Show differences in vendor configurations or dependencies",
-	Long:               "This command compares and displays the differences in vendor-specific configurations or dependencies.",
+	Short:              "Show differences between vendored components and their upstream sources",
+	Long:               "This command downloads the upstream sources of the vendored components, applies the same 'included_paths' and 'excluded_paths' filtering as 'atmos vendor pull', and shows the differences with the local files. It exits with a non-zero code if the local files drifted from the upstream sources.",
 	FParseErrWhitelist: struct{ UnknownFlags bool }{UnknownFlags: false},
+	Args:               cobra.NoArgs,
 	Run: func(cmd *cobra.Command, args []string) {
 		handleHelpRequest(cmd, args)
-		// TODO: There was no documentation here:https://atmos.tools/cli/commands/vendor we need to know what this command requires to check if we should add usage help
 
 		// Check Atmos configuration
 		checkAtmosConfig()
@@ -28,11 +28,10 @@ var vendorDiffCmd = &cobra.Command{
 }
 
 func init() {
-	vendorDiffCmd.PersistentFlags().StringP("component", "c", "", "Compare the differences between the local and vendored versions of the specified component.")
-	AddStackCompletion(vendorDiffCmd)
-	vendorDiffCmd.PersistentFlags().StringP("type", "t", "terraform", "Compare the differences between the local and vendored versions of the specified component, filtering by type (terraform or helmfile).")
-	vendorDiffCmd.PersistentFlags().Bool("dry-run", false, "Simulate the comparison of differences between the local and vendored versions of the specified component without making any changes.")
-
-	// Since this command is not implemented yet, exclude it from `atmos help`
-	// vendorCmd.AddCommand(vendorDiffCmd)
+	vendorDiffCmd.PersistentFlags().StringP("component", "c", "", "Only compare the specified component")
+	vendorDiffCmd.RegisterFlagCompletionFunc("component", ComponentsArgCompletion)
+	vendorDiffCmd.PersistentFlags().StringP("type", "t", "terraform", "The type of the vendor (terraform or helmfile).")
+	vendorDiffCmd.PersistentFlags().String("tags", "", "Only compare the components that have the specified tags")
+	vendorDiffCmd.PersistentFlags().Bool("summary", false, "Only show the names and the status of the changed files")
+	vendorCmd.AddCommand(vendorDiffCmd)
 }
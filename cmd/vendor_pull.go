@@ -35,5 +35,6 @@ func init() {
 	vendorPullCmd.PersistentFlags().Bool("dry-run", false, "Simulate pulling the latest version of the specified component from the remote repository without making any changes.")
 	vendorPullCmd.PersistentFlags().String("tags", "", "Only vendor the components that have the specified tags")
 	vendorPullCmd.PersistentFlags().Bool("everything", false, "Vendor all components")
+	vendorPullCmd.PersistentFlags().Bool("frozen", false, "Fail if the upstream sources do not match the revisions and hashes recorded in the vendor lock file")
 	vendorCmd.AddCommand(vendorPullCmd)
 }
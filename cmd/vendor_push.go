@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	e "github.com/cloudposse/atmos/internal/exec"
+	u "github.com/cloudposse/atmos/pkg/utils"
+)
+
+// vendorPushCmd executes 'vendor push' CLI commands
+var vendorPushCmd = &cobra.Command{
+	Use:                "push <component>",
+	Short:              "Publish a component to an OCI registry",
+	Long:               "This command packages the component folder into an OCI image, honoring the 'included_paths' and 'excluded_paths' from the component's 'component.yaml', and pushes it to an OCI registry. The image can be vendored using the 'oci://' scheme in 'vendor.yaml' and 'component.yaml'.",
+	FParseErrWhitelist: struct{ UnknownFlags bool }{UnknownFlags: false},
+	Args:               cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		// Check Atmos configuration
+		checkAtmosConfig()
+
+		err := e.ExecuteVendorPushCmd(cmd, args)
+		if err != nil {
+			u.PrintErrorMarkdownAndExit("", err, "")
+		}
+	},
+	ValidArgsFunction: ComponentsArgCompletion,
+}
+
+func init() {
+	vendorPushCmd.PersistentFlags().String("ref", "", "The reference of the image to push: <registry>/<repository>:<tag>")
+	vendorPushCmd.PersistentFlags().StringP("type", "t", "terraform", "The type of the component (terraform or helmfile).")
+	vendorPushCmd.PersistentFlags().String("version", "", "The version annotation of the image (defaults to the tag of the reference)")
+
+	err := vendorPushCmd.MarkPersistentFlagRequired("ref")
+	if err != nil {
+		u.LogErrorAndExit(err)
+	}
+
+	vendorCmd.AddCommand(vendorPushCmd)
+}
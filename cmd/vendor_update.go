@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	e "github.com/cloudposse/atmos/internal/exec"
+	u "github.com/cloudposse/atmos/pkg/utils"
+)
+
+// vendorUpdateCmd executes 'vendor update' CLI commands
+var vendorUpdateCmd = &cobra.Command{
+	Use:                "update",
+	Short:              "Update the versions of the vendored components to the newest upstream versions",
+	Long:               "This command lists the Git or OCI tags of the vendor sources, selects the newest version that satisfies the 'constraints' of each source, and updates the 'version' fields in the vendor config files in place.",
+	FParseErrWhitelist: struct{ UnknownFlags bool }{UnknownFlags: false},
+	Args:               cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Check Atmos configuration
+		checkAtmosConfig()
+
+		err := e.ExecuteVendorUpdateCmd(cmd, args)
+		if err != nil {
+			u.PrintErrorMarkdownAndExit("", err, "")
+		}
+	},
+}
+
+func init() {
+	vendorUpdateCmd.PersistentFlags().StringP("component", "c", "", "Only update the specified component")
+	vendorUpdateCmd.RegisterFlagCompletionFunc("component", ComponentsArgCompletion)
+	vendorUpdateCmd.PersistentFlags().String("tags", "", "Only update the components that have the specified tags")
+	vendorUpdateCmd.PersistentFlags().Bool("dry-run", false, "Show the available updates without changing the vendor config files")
+	vendorUpdateCmd.PersistentFlags().String("format", "table", "The output format of the report: 'table' or 'markdown'")
+	vendorCmd.AddCommand(vendorUpdateCmd)
+}
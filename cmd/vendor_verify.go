@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	e "github.com/cloudposse/atmos/internal/exec"
+	u "github.com/cloudposse/atmos/pkg/utils"
+)
+
+// vendorVerifyCmd executes 'vendor verify' CLI commands
+var vendorVerifyCmd = &cobra.Command{
+	Use:                "verify",
+	Short:              "Verify the vendored files against the vendor lock file",
+	Long:               "This command checks the local vendored files against the hashes recorded in the vendor lock file 'vendor.lock.yaml' without accessing the network.",
+	FParseErrWhitelist: struct{ UnknownFlags bool }{UnknownFlags: false},
+	Args:               cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Check Atmos configuration
+		checkAtmosConfig()
+
+		err := e.ExecuteVendorVerifyCmd(cmd, args)
+		if err != nil {
+			u.PrintErrorMarkdownAndExit("", err, "")
+		}
+	},
+}
+
+func init() {
+	vendorVerifyCmd.PersistentFlags().StringP("component", "c", "", "Only verify the specified component")
+	vendorVerifyCmd.RegisterFlagCompletionFunc("component", ComponentsArgCompletion)
+	vendorCmd.AddCommand(vendorVerifyCmd)
+}
@@ -210,64 +210,11 @@ func ExecuteDescribeAffectedCmd(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	var affected []schema.Affected
-	var headHead, baseHead *plumbing.Reference
-	var repoUrl string
-
-	if a.RepoPath != "" {
-		affected, headHead, baseHead, repoUrl, err = ExecuteDescribeAffectedWithTargetRepoPath(
-			a.CLIConfig,
-			a.RepoPath,
-			a.Verbose,
-			a.IncludeSpaceliftAdminStacks,
-			a.IncludeSettings,
-			a.Stack,
-			a.ProcessTemplates,
-			a.ProcessYamlFunctions,
-			a.Skip,
-		)
-	} else if a.CloneTargetRef {
-		affected, headHead, baseHead, repoUrl, err = ExecuteDescribeAffectedWithTargetRefClone(
-			a.CLIConfig,
-			a.Ref,
-			a.SHA,
-			a.SSHKeyPath,
-			a.SSHKeyPassword,
-			a.Verbose,
-			a.IncludeSpaceliftAdminStacks,
-			a.IncludeSettings,
-			a.Stack,
-			a.ProcessTemplates,
-			a.ProcessYamlFunctions,
-			a.Skip,
-		)
-	} else {
-		affected, headHead, baseHead, repoUrl, err = ExecuteDescribeAffectedWithTargetRefCheckout(
-			a.CLIConfig,
-			a.Ref,
-			a.SHA,
-			a.Verbose,
-			a.IncludeSpaceliftAdminStacks,
-			a.IncludeSettings,
-			a.Stack,
-			a.ProcessTemplates,
-			a.ProcessYamlFunctions,
-			a.Skip,
-		)
-	}
-
+	affected, headHead, baseHead, repoUrl, err := getAffectedComponents(a)
 	if err != nil {
 		return err
 	}
 
-	// Add dependent components and stacks for each affected component
-	if len(affected) > 0 && a.IncludeDependents {
-		err = addDependentsToAffected(a.CLIConfig, &affected, a.IncludeSettings)
-		if err != nil {
-			return err
-		}
-	}
-
 	if a.Query == "" {
 		a.Logger.Trace("\nAffected components and stacks: \n")
 
@@ -322,3 +269,68 @@ func ExecuteDescribeAffectedCmd(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// getAffectedComponents returns the affected Atmos components and stacks (and their dependents if `IncludeDependents` is set),
+// comparing the current branch with the target reference, the cloned target reference, or the target repo path
+func getAffectedComponents(a DescribeAffectedCmdArgs) ([]schema.Affected, *plumbing.Reference, *plumbing.Reference, string, error) {
+	var affected []schema.Affected
+	var headHead, baseHead *plumbing.Reference
+	var repoUrl string
+	var err error
+
+	if a.RepoPath != "" {
+		affected, headHead, baseHead, repoUrl, err = ExecuteDescribeAffectedWithTargetRepoPath(
+			a.CLIConfig,
+			a.RepoPath,
+			a.Verbose,
+			a.IncludeSpaceliftAdminStacks,
+			a.IncludeSettings,
+			a.Stack,
+			a.ProcessTemplates,
+			a.ProcessYamlFunctions,
+			a.Skip,
+		)
+	} else if a.CloneTargetRef {
+		affected, headHead, baseHead, repoUrl, err = ExecuteDescribeAffectedWithTargetRefClone(
+			a.CLIConfig,
+			a.Ref,
+			a.SHA,
+			a.SSHKeyPath,
+			a.SSHKeyPassword,
+			a.Verbose,
+			a.IncludeSpaceliftAdminStacks,
+			a.IncludeSettings,
+			a.Stack,
+			a.ProcessTemplates,
+			a.ProcessYamlFunctions,
+			a.Skip,
+		)
+	} else {
+		affected, headHead, baseHead, repoUrl, err = ExecuteDescribeAffectedWithTargetRefCheckout(
+			a.CLIConfig,
+			a.Ref,
+			a.SHA,
+			a.Verbose,
+			a.IncludeSpaceliftAdminStacks,
+			a.IncludeSettings,
+			a.Stack,
+			a.ProcessTemplates,
+			a.ProcessYamlFunctions,
+			a.Skip,
+		)
+	}
+
+	if err != nil {
+		return nil, nil, nil, "", err
+	}
+
+	// Add dependent components and stacks for each affected component
+	if len(affected) > 0 && a.IncludeDependents {
+		err = addDependentsToAffected(a.CLIConfig, &affected, a.IncludeSettings)
+		if err != nil {
+			return nil, nil, nil, "", err
+		}
+	}
+
+	return affected, headHead, baseHead, repoUrl, nil
+}
@@ -58,6 +58,15 @@ func ExecuteDescribeComponentCmd(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	provenance, err := flags.GetBool("provenance")
+	if err != nil {
+		return err
+	}
+
+	if provenance && query != "" {
+		return errors.New("the '--provenance' flag can't be used with the '--query' flag")
+	}
+
 	component := args[0]
 
 	componentSection, err := ExecuteDescribeComponent(
@@ -71,6 +80,10 @@ func ExecuteDescribeComponentCmd(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if provenance {
+		return printComponentWithProvenance(component, componentSection, format, file)
+	}
+
 	var res any
 
 	if query != "" {
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -26,7 +28,7 @@ func ExecuteHelmfileCmd(cmd *cobra.Command, args []string, additionalArgsAndFlag
 }
 
 // ExecuteHelmfile executes helmfile commands
-func ExecuteHelmfile(info schema.ConfigAndStacksInfo) error {
+func ExecuteHelmfile(info schema.ConfigAndStacksInfo) (err error) {
 	atmosConfig, err := cfg.InitCliConfig(info, true)
 	if err != nil {
 		return err
@@ -36,6 +38,14 @@ func ExecuteHelmfile(info schema.ConfigAndStacksInfo) error {
 		return ExecuteShellCommand(atmosConfig, "helmfile", []string{info.SubCommand}, "", nil, false, info.RedirectStdErr)
 	}
 
+	// Record the command in the audit log
+	start := time.Now()
+	auditInfo := info
+	auditInfo.AdditionalArgsAndFlags = slices.Clone(info.AdditionalArgsAndFlags)
+	defer func() {
+		recordAuditEntry(atmosConfig, "helmfile", auditInfo, start, err)
+	}()
+
 	info, err = ProcessStacks(atmosConfig, info, true, true, true, nil)
 	if err != nil {
 		return err
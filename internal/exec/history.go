@@ -0,0 +1,283 @@
+package exec
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	l "github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+
+	cfg "github.com/cloudposse/atmos/pkg/config"
+	"github.com/cloudposse/atmos/pkg/schema"
+	u "github.com/cloudposse/atmos/pkg/utils"
+)
+
+// auditHistoryFilter selects the audit records shown by `atmos history`
+type auditHistoryFilter struct {
+	Stack      string
+	Component  string
+	User       string
+	Command    string
+	SubCommand string
+	Since      time.Time
+	Failed     bool
+}
+
+// ExecuteHistoryCmd executes `atmos history` commands
+func ExecuteHistoryCmd(cmd *cobra.Command, args []string) error {
+	flags := cmd.Flags()
+
+	var filter auditHistoryFilter
+	var err error
+
+	for flag, value := range map[string]*string{
+		"stack":      &filter.Stack,
+		"component":  &filter.Component,
+		"user":       &filter.User,
+		"command":    &filter.Command,
+		"subcommand": &filter.SubCommand,
+	} {
+		if *value, err = flags.GetString(flag); err != nil {
+			return err
+		}
+	}
+
+	if filter.Failed, err = flags.GetBool("failed"); err != nil {
+		return err
+	}
+
+	since, err := flags.GetString("since")
+	if err != nil {
+		return err
+	}
+	if since != "" {
+		if filter.Since, err = parseAuditHistorySince(since, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	limit, err := flags.GetInt("limit")
+	if err != nil {
+		return err
+	}
+
+	format, err := flags.GetString("format")
+	if err != nil {
+		return err
+	}
+	if format != "" && format != "table" && format != "json" {
+		return fmt.Errorf("invalid '--format' flag '%s'. Valid values are: table, json", format)
+	}
+
+	atmosConfig, err := cfg.InitCliConfig(schema.ConfigAndStacksInfo{}, false)
+	if err != nil {
+		return err
+	}
+
+	auditLogPath, err := getAuditLogPath(atmosConfig)
+	if err != nil {
+		return err
+	}
+
+	records, err := readAuditRecords(auditLogPath)
+	if err != nil {
+		return err
+	}
+
+	records = filterAuditRecords(records, filter)
+	if limit > 0 && len(records) > limit {
+		records = records[len(records)-limit:]
+	}
+
+	if format == "json" {
+		if records == nil {
+			records = []auditRecord{}
+		}
+		return u.PrintAsJSON(records)
+	}
+
+	if len(records) == 0 {
+		u.PrintMessage(fmt.Sprintf("No audit records found in '%s'.", auditLogPath))
+		if !atmosConfig.Audit.Enabled {
+			u.PrintMessage("The audit log is disabled. Set 'audit.enabled: true' in 'atmos.yaml' to record the Terraform and Helmfile commands.")
+		}
+		return nil
+	}
+
+	u.PrintMessage(formatAuditRecords(records))
+
+	return nil
+}
+
+// parseAuditHistorySince parses the `--since` flag. The flag is a duration (e.g. `12h`, `7d`), a date (`2006-01-02`)
+// or a timestamp in RFC3339 format
+func parseAuditHistorySince(since string, now time.Time) (time.Time, error) {
+	if days, ok := strings.CutSuffix(since, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+
+	if d, err := time.ParseDuration(since); err == nil {
+		return now.Add(-d), nil
+	}
+
+	if t, err := time.ParseInLocation(time.DateOnly, since, time.Local); err == nil {
+		return t, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, since); err == nil {
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid '--since' flag '%s'. Use a duration (e.g. '12h', '7d'), a date (e.g. '2025-01-31') or an RFC3339 timestamp", since)
+}
+
+// readAuditRecords reads the records from the audit log file and the rotated files, in chronological order
+func readAuditRecords(path string) ([]auditRecord, error) {
+	rotated, err := filepath.Glob(path + ".*")
+	if err != nil {
+		return nil, err
+	}
+
+	// The rotated files are named `<path>.1` (the most recent) to `<path>.<max_backups>` (the oldest)
+	rotatedIndexes := map[string]int{}
+	for _, f := range rotated {
+		if i, err := strconv.Atoi(strings.TrimPrefix(f, path+".")); err == nil {
+			rotatedIndexes[f] = i
+		}
+	}
+
+	files := make([]string, 0, len(rotatedIndexes)+1)
+	for f := range rotatedIndexes {
+		files = append(files, f)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return rotatedIndexes[files[i]] > rotatedIndexes[files[j]]
+	})
+	files = append(files, path)
+
+	var records []auditRecord
+
+	for _, f := range files {
+		fileRecords, err := readAuditRecordsFromFile(f)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		records = append(records, fileRecords...)
+	}
+
+	return records, nil
+}
+
+func readAuditRecordsFromFile(path string) ([]auditRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []auditRecord
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var record auditRecord
+		if err = json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			l.Debug(fmt.Sprintf("Ignoring the invalid audit record in '%s' at line %d", path, line), "error", err)
+			continue
+		}
+		records = append(records, record)
+	}
+
+	return records, scanner.Err()
+}
+
+// filterAuditRecords returns the records matching the filter
+func filterAuditRecords(records []auditRecord, filter auditHistoryFilter) []auditRecord {
+	var filtered []auditRecord
+
+	for _, record := range records {
+		switch {
+		case filter.Stack != "" && record.Stack != filter.Stack,
+			filter.Component != "" && record.Component != filter.Component,
+			filter.User != "" && record.User != filter.User,
+			filter.Command != "" && record.Command != filter.Command,
+			filter.SubCommand != "" && record.SubCommand != filter.SubCommand && !strings.HasPrefix(record.SubCommand, filter.SubCommand+" "),
+			!filter.Since.IsZero() && record.Timestamp.Before(filter.Since),
+			filter.Failed && record.ExitCode == 0:
+			continue
+		}
+		filtered = append(filtered, record)
+	}
+
+	return filtered
+}
+
+// formatAuditRecords formats the audit records as a table
+func formatAuditRecords(records []auditRecord) string {
+	header := []string{"TIME", "USER", "COMMAND", "COMPONENT", "STACK", "DURATION", "EXIT", "COMMIT"}
+
+	rows := make([][]string, 0, len(records))
+	for _, record := range records {
+		commit := shortenVendorCacheValue(record.GitSHA, 8)
+		if record.GitDirty && commit != "" {
+			commit += "-dirty"
+		}
+
+		command := fmt.Sprintf("%s %s", record.Command, record.SubCommand)
+		if record.DryRun {
+			command += " (dry run)"
+		}
+
+		rows = append(rows, []string{
+			record.Timestamp.Local().Format(time.DateTime),
+			record.User,
+			command,
+			record.Component,
+			record.Stack,
+			time.Duration(record.Duration * float64(time.Second)).Round(time.Millisecond).String(),
+			strconv.Itoa(record.ExitCode),
+			commit,
+		})
+	}
+
+	widths := make([]int, len(header))
+	for i, h := range header {
+		widths[i] = len(h)
+	}
+	for _, row := range rows {
+		for i, v := range row {
+			widths[i] = max(widths[i], len(v))
+		}
+	}
+
+	var sb strings.Builder
+	for _, row := range append([][]string{header}, rows...) {
+		for i, v := range row {
+			if i < len(row)-1 {
+				sb.WriteString(fmt.Sprintf("%-*s  ", widths[i], v))
+			} else {
+				sb.WriteString(v)
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	return strings.TrimSuffix(sb.String(), "\n")
+}
@@ -101,3 +101,33 @@ func processOciImage(atmosConfig schema.AtmosConfiguration, imageName string, de
 
 	return nil
 }
+
+// resolveOciImageDigest returns the digest of an Image in an OCI-compatible registry
+func resolveOciImageDigest(imageName string) (string, error) {
+	ref, err := name.ParseReference(imageName)
+	if err != nil {
+		return "", fmt.Errorf("cannot parse reference of the image '%s'. Error: %v", imageName, err)
+	}
+
+	descriptor, err := remote.Head(ref)
+	if err != nil {
+		return "", fmt.Errorf("cannot get image '%s'. Error: %v", imageName, err)
+	}
+
+	return descriptor.Digest.String(), nil
+}
+
+// listOciImageTags returns the tags of the repository of an Image in an OCI-compatible registry
+func listOciImageTags(imageName string) ([]string, error) {
+	ref, err := name.ParseReference(imageName)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse reference of the image '%s'. Error: %v", imageName, err)
+	}
+
+	tags, err := remote.List(ref.Context())
+	if err != nil {
+		return nil, fmt.Errorf("cannot list tags of the repository '%s'. Error: %v", ref.Context().Name(), err)
+	}
+
+	return tags, nil
+}
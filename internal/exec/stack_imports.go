@@ -0,0 +1,385 @@
+package exec
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/hashicorp/go-getter"
+	"github.com/samber/lo"
+
+	cfg "github.com/cloudposse/atmos/pkg/config"
+	"github.com/cloudposse/atmos/pkg/schema"
+	u "github.com/cloudposse/atmos/pkg/utils"
+)
+
+const stackImportsCacheDir = "imports"
+
+var (
+	// stacksLockFileLock serializes the updates of the stacks lock files by the stack manifests processed concurrently
+	stacksLockFileLock = &sync.Mutex{}
+
+	// remoteStackImportDirs memoizes the local directories of the downloaded remote imports
+	remoteStackImportDirs sync.Map
+)
+
+// remoteStackImport is an import of stack manifests from a remote source, e.g.
+// `git::https://github.com/acme/catalog.git//stacks/catalog/vpc.yaml?ref=v1.2.0` or `oci://ghcr.io/acme/catalog:v1.2.0//catalog/vpc.yaml`
+type remoteStackImport struct {
+	// source is the go-getter source or the OCI image without the path to the manifests
+	source     string
+	sourceType pkgType
+	// path is the path to the manifests in the source (can be a glob)
+	path string
+}
+
+// isRemoteStackImport returns `true` if the import is a remote source (an OCI image, a go-getter source with a forced getter,
+// or an HTTP(S) URL) and not a path on the local filesystem
+func isRemoteStackImport(imp string) bool {
+	if strings.HasPrefix(imp, "oci://") || strings.HasPrefix(imp, "http://") || strings.HasPrefix(imp, "https://") {
+		return true
+	}
+	i := strings.Index(imp, "::")
+	return i > 0 && !strings.ContainsAny(imp[:i], "/.")
+}
+
+// parseRemoteStackImport splits the remote import into the source and the path to the manifests in the source (after `//`)
+func parseRemoteStackImport(imp string) (remoteStackImport, error) {
+	var r remoteStackImport
+
+	if image, ok := strings.CutPrefix(imp, "oci://"); ok {
+		r.sourceType = pkgTypeOci
+		r.source, r.path, _ = strings.Cut(image, "//")
+	} else {
+		r.sourceType = pkgTypeRemote
+		r.source, r.path = getter.SourceDirSubdir(imp)
+	}
+
+	if r.source == "" || r.path == "" {
+		return r, fmt.Errorf("invalid remote import '%s'. Specify the path to the stack manifests in the source after '//', "+
+			"e.g. 'git::https://github.com/acme/catalog.git//stacks/catalog/vpc.yaml?ref=v1.2.0'", imp)
+	}
+
+	return r, nil
+}
+
+// getRemoteStackImportName returns the name of a manifest imported from the remote source, e.g.
+// `git::https://github.com/acme/catalog.git//stacks/catalog/vpc?ref=v1.2.0`. The credentials are removed from the source
+func getRemoteStackImportName(imp string, path string) string {
+	r, err := parseRemoteStackImport(imp)
+	if err != nil {
+		return imp
+	}
+
+	source, query, found := strings.Cut(stacksLockSourceURI(r), "?")
+	if r.sourceType == pkgTypeOci || !found {
+		return source + "//" + path
+	}
+	return source + "//" + path + "?" + query
+}
+
+// getRemoteStackImport downloads the source of the remote import into the local cache, and returns the local directory of the source
+// and the path to the manifests in it.
+// The resolved revision of the source is pinned in the stacks lock file, so the same revision is imported until the lock file is updated
+func getRemoteStackImport(atmosConfig schema.AtmosConfiguration, imp string) (string, string, error) {
+	r, err := parseRemoteStackImport(imp)
+	if err != nil {
+		return "", "", err
+	}
+
+	lockFile := getStacksLockFilePath(atmosConfig)
+	memoKey := lockFile + "|" + r.sourceType.String() + "|" + r.source
+
+	if dir, ok := remoteStackImportDirs.Load(memoKey); ok {
+		return dir.(string), r.path, nil
+	}
+
+	locked, err := getStacksLockImport(lockFile, r, atmosConfig.Stacks.Imports.AllowUnpinned)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid remote import '%s': %w", imp, err)
+	}
+
+	dir, err := downloadRemoteStackImport(atmosConfig, r, locked)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to download the remote import '%s': %w", imp, err)
+	}
+
+	remoteStackImportDirs.Store(memoKey, dir)
+	return dir, r.path, nil
+}
+
+// getStacksLockFilePath returns the path to the stacks lock file, which is placed next to the stacks base directory
+// (e.g. `stacks.lock.yaml` next to the `stacks` directory)
+func getStacksLockFilePath(atmosConfig schema.AtmosConfiguration) string {
+	return filepath.Join(filepath.Dir(atmosConfig.StacksBaseAbsolutePath), cfg.AtmosStacksLockFileName)
+}
+
+// ReadStacksLockFile reads the stacks lock file. It returns `false` if the file does not exist
+func ReadStacksLockFile(lockFile string) (schema.AtmosStacksLock, bool, error) {
+	var lock schema.AtmosStacksLock
+
+	if !u.FileExists(lockFile) {
+		return lock, false, nil
+	}
+
+	content, err := os.ReadFile(lockFile)
+	if err != nil {
+		return lock, false, err
+	}
+
+	lock, err = u.UnmarshalYAML[schema.AtmosStacksLock](string(content))
+	if err != nil {
+		return lock, false, fmt.Errorf("invalid stacks lock file '%s': %w", lockFile, err)
+	}
+
+	return lock, true, nil
+}
+
+// getStacksLockImport returns the source of the remote import from the stacks lock file.
+// If the source is not in the lock file, its revision is resolved and added to the lock file.
+// The sources that are not pinned are refused unless `allowUnpinned` is `true`
+func getStacksLockImport(lockFile string, r remoteStackImport, allowUnpinned bool) (schema.AtmosStacksLockImport, error) {
+	stacksLockFileLock.Lock()
+	defer stacksLockFileLock.Unlock()
+
+	source := stacksLockSourceURI(r)
+
+	lock, _, err := ReadStacksLockFile(lockFile)
+	if err != nil {
+		return schema.AtmosStacksLockImport{}, err
+	}
+
+	locked, found := lo.Find(lock.Imports, func(i schema.AtmosStacksLockImport) bool {
+		return i.Source == source
+	})
+
+	if !found {
+		resolved, pinned, err := resolveRemoteStackImportRevision(r)
+		if err != nil {
+			return locked, err
+		}
+		locked = schema.AtmosStacksLockImport{
+			Source:   source,
+			Resolved: resolved,
+			Pinned:   pinned,
+		}
+	}
+
+	if !locked.Pinned && !allowUnpinned {
+		return locked, fmt.Errorf("the source is not pinned. Pin the source to a tag or a commit SHA (Git), a tag or a digest (OCI), " +
+			"or a checksum (other sources), or set 'stacks.imports.allow_unpinned: true' in 'atmos.yaml' to allow the unpinned remote imports")
+	}
+
+	if found {
+		return locked, nil
+	}
+
+	lock.ApiVersion = "atmos/v1"
+	lock.Kind = "AtmosStacksLock"
+	lock.Imports = append(lock.Imports, locked)
+
+	sort.SliceStable(lock.Imports, func(i, j int) bool {
+		return lock.Imports[i].Source < lock.Imports[j].Source
+	})
+
+	u.LogDebug(fmt.Sprintf("Adding the remote import '%s' (%s) to the stacks lock file '%s'", source, locked.Resolved, lockFile))
+
+	if err = u.WriteToFileAsYAML(lockFile, lock, 0o644); err != nil {
+		return locked, err
+	}
+
+	return locked, nil
+}
+
+// stacksLockSourceURI returns the source of the remote import as recorded in the stacks lock file (without the credentials)
+func stacksLockSourceURI(r remoteStackImport) string {
+	if r.sourceType == pkgTypeOci {
+		return "oci://" + r.source
+	}
+	return normalizeVendorCacheURI(r.source)
+}
+
+// resolveRemoteStackImportRevision returns the commit SHA of a Git source or the digest of an OCI image,
+// and whether the source is pinned to an immutable revision:
+//   - a Git source is pinned to a tag or a commit SHA in the `ref` parameter (a branch or a missing `ref` is not pinned)
+//   - an OCI image is pinned to a tag (except `latest`) or a digest
+//   - other sources (e.g. HTTP archives) are pinned to the `checksum` parameter
+func resolveRemoteStackImportRevision(r remoteStackImport) (string, bool, error) {
+	if r.sourceType == pkgTypeOci {
+		ref, err := name.ParseReference(r.source)
+		if err != nil {
+			return "", false, err
+		}
+		if digest, ok := ref.(name.Digest); ok {
+			return digest.DigestStr(), true, nil
+		}
+
+		resolved, err := resolveOciImageDigest(r.source)
+		if err != nil {
+			return "", false, err
+		}
+
+		// The tag is optional in the image name, and it defaults to `latest`
+		repository := r.source[strings.LastIndex(r.source, "/")+1:]
+		pinned := strings.Contains(repository, ":") && ref.Identifier() != "latest"
+		return resolved, pinned, nil
+	}
+
+	repoURL, ref, isGit, err := parseGitSource(r.source)
+	if err != nil {
+		return "", false, err
+	}
+
+	if !isGit {
+		parsed, err := url.Parse(r.source)
+		if err != nil {
+			return "", false, err
+		}
+		return "", parsed.Query().Get("checksum") != "", nil
+	}
+
+	if gitCommitShaRegex.MatchString(ref) {
+		return ref, true, nil
+	}
+
+	if ref == "" {
+		resolved, err := resolveGitRevision(r.source)
+		return resolved, false, err
+	}
+
+	refs, err := gitLsRemote(repoURL, ref, ref+"^{}")
+	if err != nil {
+		return "", false, fmt.Errorf("failed to resolve the Git ref '%s': %w", ref, err)
+	}
+
+	resolved := ""
+	isTag := false
+	for _, r := range refs {
+		if !strings.HasPrefix(r[1], "refs/tags/") {
+			if resolved == "" {
+				resolved = r[0]
+			}
+			continue
+		}
+		// Prefer the commit that an annotated tag points to
+		if !isTag || strings.HasSuffix(r[1], "^{}") {
+			resolved = r[0]
+		}
+		isTag = true
+	}
+
+	if resolved == "" {
+		return "", false, fmt.Errorf("the Git ref '%s' is not found in the repository", ref)
+	}
+
+	return resolved, isTag, nil
+}
+
+// downloadRemoteStackImport downloads the locked revision of the remote import source into the local cache and returns the local directory.
+// The sources with a resolved revision (or a checksum) are downloaded only once and are shared by all Atmos projects.
+// The other sources are downloaded again into the cache in each run
+func downloadRemoteStackImport(atmosConfig schema.AtmosConfiguration, r remoteStackImport, locked schema.AtmosStacksLockImport) (string, error) {
+	src := r.source
+
+	// Download the locked revision, so the import does not change if a tag is moved
+	switch {
+	case r.sourceType == pkgTypeOci && locked.Resolved != "":
+		ref, err := name.ParseReference(r.source)
+		if err != nil {
+			return "", err
+		}
+		src = ref.Context().Digest(locked.Resolved).String()
+
+	case r.sourceType == pkgTypeRemote && locked.Resolved != "":
+		var err error
+		if src, err = setGitSourceRef(r.source, locked.Resolved); err != nil {
+			return "", err
+		}
+	}
+
+	download := func(dir string) error {
+		return retryVendorDownload(atmosConfig, locked.Source, func(attempt int) error {
+			if err := os.RemoveAll(dir); err != nil {
+				return err
+			}
+			if r.sourceType == pkgTypeOci {
+				if err := os.MkdirAll(dir, 0o755); err != nil {
+					return err
+				}
+				return processOciImage(atmosConfig, src, dir)
+			}
+			return GoGetterGet(atmosConfig, src, dir, getter.ClientModeAny, 10*time.Minute)
+		})
+	}
+
+	cacheDir, err := cfg.GetAtmosCacheDir(stackImportsCacheDir)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(cacheDir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create the imports cache directory: %w", err)
+	}
+
+	dir := filepath.Join(cacheDir, vendorCacheKey(r.sourceType, locked.Source, locked.Resolved))
+
+	// The sources without a resolved revision can change, so they are downloaded again in each run
+	refresh := locked.Resolved == "" && !locked.Pinned
+
+	err = withVendorCacheLock(dir+vendorCacheLockFileExt, func() error {
+		if !refresh && u.FileOrDirExists(dir) {
+			u.LogTrace(fmt.Sprintf("Using the imports cache for '%s' (%s)", locked.Source, locked.Resolved))
+			return nil
+		}
+
+		u.LogTrace(fmt.Sprintf("Downloading '%s' (%s) into the imports cache", locked.Source, locked.Resolved))
+
+		// Download into a staging directory, so a failed or interrupted download never leaves a partial cache entry
+		stagingDir, err := os.MkdirTemp(cacheDir, filepath.Base(dir)+".tmp-*")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(stagingDir)
+
+		content := filepath.Join(stagingDir, vendorCacheContentDir)
+		if err := download(content); err != nil {
+			return err
+		}
+		if refresh {
+			if err := os.RemoveAll(dir); err != nil {
+				return err
+			}
+		}
+		return os.Rename(content, dir)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return dir, nil
+}
+
+// setGitSourceRef sets the `ref` parameter of the Git source, and removes the `depth` parameter (a commit SHA can't be shallow-cloned)
+func setGitSourceRef(source string, ref string) (string, error) {
+	forced, src := "", source
+	if i := strings.Index(source, "::"); i > 0 {
+		forced, src = source[:i+2], source[i+2:]
+	}
+
+	parsed, err := url.Parse(src)
+	if err != nil {
+		return "", err
+	}
+
+	query := parsed.Query()
+	query.Set("ref", ref)
+	query.Del("depth")
+	parsed.RawQuery = query.Encode()
+
+	return forced + parsed.String(), nil
+}
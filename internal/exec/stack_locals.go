@@ -0,0 +1,377 @@
+package exec
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template/parse"
+
+	"github.com/samber/lo"
+
+	cfg "github.com/cloudposse/atmos/pkg/config"
+	"github.com/cloudposse/atmos/pkg/schema"
+	u "github.com/cloudposse/atmos/pkg/utils"
+)
+
+// localsSectionRegex matches the `locals` section at the top level of a stack manifest
+var localsSectionRegex = regexp.MustCompile(`(?m)^locals\s*:`)
+
+// stackManifestLocals holds the file-scoped `locals` of a stack manifest.
+// The locals are evaluated per stack manifest file, are available to the Go templates and YAML functions in the file,
+// and are not deep-merged into the components
+type stackManifestLocals struct {
+	filePath string
+	context  map[string]any
+	// defined is `true` if the stack manifest has the `locals` section
+	defined bool
+	// evaluated is `true` after the locals are evaluated
+	evaluated bool
+	values    map[string]any
+	vars      map[string]any
+}
+
+// newStackManifestLocals returns the locals of the stack manifest.
+// The locals are evaluated before the Go templates in the manifest are processed (if the manifest is a valid YAML before the templates
+// are processed), so the templates with the import `context` can use them
+func newStackManifestLocals(
+	atmosConfig schema.AtmosConfiguration,
+	relativeFilePath string,
+	filePath string,
+	stackYamlConfig string,
+	context map[string]any,
+) (*stackManifestLocals, error) {
+	locals := &stackManifestLocals{
+		filePath: relativeFilePath,
+		context:  context,
+		defined:  localsSectionRegex.MatchString(stackYamlConfig),
+	}
+
+	if !locals.defined {
+		return locals, nil
+	}
+
+	// If the manifest is not a valid YAML before the Go templates are processed (e.g. the templates generate YAML keys),
+	// the locals are evaluated after the templates are processed
+	stackConfigMap, err := u.UnmarshalYAMLFromFile[schema.AtmosSectionMapType](&atmosConfig, stackYamlConfig, filePath)
+	if err != nil {
+		return locals, nil
+	}
+
+	if err = locals.evaluate(stackConfigMap); err != nil {
+		return nil, err
+	}
+
+	return locals, nil
+}
+
+// templateData returns the data for the Go templates in the stack manifest: the import `context`, the `vars` section of the manifest
+// and the evaluated locals
+func (l *stackManifestLocals) templateData() map[string]any {
+	return lo.Assign(l.context, map[string]any{
+		cfg.VarsSectionName:   l.vars,
+		cfg.LocalsSectionName: l.values,
+	})
+}
+
+// evaluate evaluates the `locals` section of the stack manifest.
+// The locals can reference the `vars` of the current context and other locals. They are evaluated in the order of their dependencies
+func (l *stackManifestLocals) evaluate(stackConfigMap map[string]any) error {
+	if l.evaluated {
+		return nil
+	}
+	l.evaluated = true
+
+	l.vars = map[string]any{}
+	if contextVars, ok := l.context[cfg.VarsSectionName].(map[string]any); ok {
+		l.vars = lo.Assign(contextVars)
+	}
+	if i, ok := stackConfigMap[cfg.VarsSectionName]; ok && i != nil {
+		vars, ok := i.(map[string]any)
+		if !ok {
+			return fmt.Errorf("invalid 'vars' section in the stack manifest '%s'", l.filePath)
+		}
+		l.vars = lo.Assign(l.vars, vars)
+	}
+
+	l.values = map[string]any{}
+
+	i, ok := stackConfigMap[cfg.LocalsSectionName]
+	if !ok || i == nil {
+		return nil
+	}
+	localsSection, ok := i.(map[string]any)
+	if !ok {
+		return fmt.Errorf("invalid 'locals' section in the stack manifest '%s'", l.filePath)
+	}
+
+	dependencies := map[string][]string{}
+	for name, value := range localsSection {
+		refs, err := getLocalsReferences(value)
+		if err != nil {
+			return err
+		}
+		for _, ref := range refs {
+			if _, ok := localsSection[ref]; !ok {
+				return fmt.Errorf("undefined local '%s' referenced by the local '%s' in the stack manifest '%s'", ref, name, l.filePath)
+			}
+		}
+		dependencies[name] = refs
+	}
+
+	order, err := sortLocals(dependencies)
+	if err != nil {
+		return fmt.Errorf("%v in the stack manifest '%s'", err, l.filePath)
+	}
+
+	for _, name := range order {
+		value, err := transformStrings(localsSection[name], func(s string) (string, error) {
+			if !strings.Contains(s, "{{") {
+				return s, nil
+			}
+			return ProcessTmpl(fmt.Sprintf("%s:locals.%s", l.filePath, name), s, l.templateData(), false)
+		})
+		if err != nil {
+			return fmt.Errorf("invalid local '%s' in the stack manifest '%s'\n%v", name, l.filePath, err)
+		}
+		l.values[name] = value
+	}
+
+	return nil
+}
+
+// apply evaluates the locals (if they were not evaluated before the Go templates in the manifest were processed),
+// renders the values in the stack manifest that reference the locals, and removes the `locals` section from the manifest
+func (l *stackManifestLocals) apply(stackConfigMap map[string]any) (map[string]any, error) {
+	if !l.defined {
+		return stackConfigMap, nil
+	}
+
+	if err := l.evaluate(stackConfigMap); err != nil {
+		return nil, err
+	}
+	delete(stackConfigMap, cfg.LocalsSectionName)
+
+	refs, err := getLocalsReferences(stackConfigMap)
+	if err != nil {
+		return nil, err
+	}
+	if len(refs) == 0 {
+		return stackConfigMap, nil
+	}
+
+	for _, ref := range refs {
+		if _, ok := l.values[ref]; !ok {
+			return nil, fmt.Errorf("undefined local '%s' in the stack manifest '%s'", ref, l.filePath)
+		}
+	}
+
+	result, err := l.render(stackConfigMap, l.filePath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid stack manifest '%s'\n%v", l.filePath, err)
+	}
+
+	return result.(map[string]any), nil
+}
+
+// render processes the Go template actions that reference the locals in the string values.
+// The other actions in the same string (e.g. `{{ .atmos_component }}` in `{{ .locals.prefix }}-{{ .atmos_component }}`)
+// are kept, and processed later for the components
+func (l *stackManifestLocals) render(value any, tmplName string) (any, error) {
+	return transformStrings(value, func(s string) (string, error) {
+		tree := parseLocalsTemplate(tmplName, s)
+		if tree == nil {
+			return s, nil
+		}
+
+		refs := map[string]bool{}
+		collectLocalsReferences(tree.Root, refs)
+		if len(refs) == 0 {
+			return s, nil
+		}
+
+		var result strings.Builder
+		for _, node := range tree.Root.Nodes {
+			text := node.String()
+			nodeRefs := map[string]bool{}
+			collectLocalsReferences(node, nodeRefs)
+			if len(nodeRefs) == 0 {
+				result.WriteString(text)
+				continue
+			}
+
+			rendered, err := ProcessTmpl(tmplName, text, l.templateData(), false)
+			if err != nil {
+				return "", err
+			}
+			result.WriteString(rendered)
+		}
+
+		return result.String(), nil
+	})
+}
+
+// getLocalsReferences returns the sorted names of the locals referenced in the Go template actions in the string values
+func getLocalsReferences(value any) ([]string, error) {
+	refs := map[string]bool{}
+
+	_, err := transformStrings(value, func(s string) (string, error) {
+		if tree := parseLocalsTemplate("locals", s); tree != nil {
+			collectLocalsReferences(tree.Root, refs)
+		}
+		return s, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := lo.Keys(refs)
+	sort.Strings(result)
+	return result, nil
+}
+
+// parseLocalsTemplate parses the Go template in the string.
+// It returns `nil` if the string has no template actions or is not a valid template (the invalid templates are reported
+// when the templates in the manifest are processed for the components)
+func parseLocalsTemplate(name string, s string) *parse.Tree {
+	if !strings.Contains(s, "{{") {
+		return nil
+	}
+
+	tree := parse.New(name)
+	tree.Mode = parse.SkipFuncCheck
+	if _, err := tree.Parse(s, "", "", map[string]*parse.Tree{}); err != nil {
+		return nil
+	}
+
+	return tree
+}
+
+// collectLocalsReferences collects the names of the locals referenced in the Go template actions,
+// i.e. the fields `.locals.<name>` and `$.locals.<name>`. Other fields named `locals` (e.g. `.settings.locals.<name>`) are not references
+func collectLocalsReferences(node parse.Node, refs map[string]bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			collectLocalsReferences(child, refs)
+		}
+	case *parse.ActionNode:
+		collectLocalsReferences(n.Pipe, refs)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			collectLocalsReferences(cmd, refs)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			collectLocalsReferences(arg, refs)
+		}
+	case *parse.IfNode:
+		collectLocalsBranchReferences(&n.BranchNode, refs)
+	case *parse.RangeNode:
+		collectLocalsBranchReferences(&n.BranchNode, refs)
+	case *parse.WithNode:
+		collectLocalsBranchReferences(&n.BranchNode, refs)
+	case *parse.TemplateNode:
+		collectLocalsReferences(n.Pipe, refs)
+	case *parse.FieldNode:
+		if len(n.Ident) > 1 && n.Ident[0] == cfg.LocalsSectionName {
+			refs[n.Ident[1]] = true
+		}
+	case *parse.VariableNode:
+		if len(n.Ident) > 2 && n.Ident[0] == "$" && n.Ident[1] == cfg.LocalsSectionName {
+			refs[n.Ident[2]] = true
+		}
+	}
+}
+
+// collectLocalsBranchReferences collects the names of the locals referenced in the `if`, `range` and `with` actions
+func collectLocalsBranchReferences(n *parse.BranchNode, refs map[string]bool) {
+	collectLocalsReferences(n.Pipe, refs)
+	collectLocalsReferences(n.List, refs)
+	collectLocalsReferences(n.ElseList, refs)
+}
+
+// sortLocals returns the names of the locals sorted in the order of their dependencies
+func sortLocals(dependencies map[string][]string) ([]string, error) {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+
+	state := map[string]int{}
+	var order []string
+	var stack []string
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			cycle := append(stack[lo.IndexOf(stack, name):], name)
+			return fmt.Errorf("circular reference in the locals: %s", strings.Join(cycle, " -> "))
+		}
+
+		state[name] = visiting
+		stack = append(stack, name)
+
+		for _, ref := range dependencies[name] {
+			if err := visit(ref); err != nil {
+				return err
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[name] = visited
+		order = append(order, name)
+		return nil
+	}
+
+	names := lo.Keys(dependencies)
+	sort.Strings(names)
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
+
+// transformStrings returns a copy of the value with the function applied to all string values
+func transformStrings(value any, f func(string) (string, error)) (any, error) {
+	switch v := value.(type) {
+	case string:
+		return f(v)
+
+	case map[string]any:
+		result := make(map[string]any, len(v))
+		for k, item := range v {
+			transformed, err := transformStrings(item, f)
+			if err != nil {
+				return nil, err
+			}
+			result[k] = transformed
+		}
+		return result, nil
+
+	case []any:
+		result := make([]any, len(v))
+		for i, item := range v {
+			transformed, err := transformStrings(item, f)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = transformed
+		}
+		return result, nil
+	}
+
+	return value, nil
+}
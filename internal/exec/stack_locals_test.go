@@ -0,0 +1,202 @@
+package exec
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudposse/atmos/pkg/schema"
+	u "github.com/cloudposse/atmos/pkg/utils"
+)
+
+func TestStackManifestLocals(t *testing.T) {
+	startingDir, err := os.Getwd()
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.Chdir(startingDir))
+	}()
+
+	require.NoError(t, os.Chdir("../../tests/fixtures/scenarios/stack-locals"))
+
+	componentSection, err := ExecuteDescribeComponent("vpc", "dev", true, true, nil)
+	require.NoError(t, err)
+
+	vars := componentSection["vars"].(map[string]any)
+	assert.Equal(t, "acme-dev-vpc", vars["name"])
+	assert.Equal(t, "arn:aws:iam::123456789012:role/terraform", vars["role_arn"])
+	assert.Equal(t, map[string]any{"Namespace": "acme", "Account": "123456789012"}, vars["labels"])
+
+	// The locals in the imported manifest use the import `context`
+	assert.Equal(t, "10.1.0.0/16", vars["cidr"])
+
+	// The templates that don't reference the locals are processed for the component
+	assert.Equal(t, "vpc in dev", vars["description"])
+	assert.Equal(t, "acme-dev-vpc", vars["label"])
+
+	assert.Equal(t, "acme-dev-tfstate-123456789012", componentSection["backend"].(map[string]any)["bucket"])
+
+	// The locals are not deep-merged into the components
+	assert.NotContains(t, componentSection, "locals")
+	assert.NotContains(t, vars, "locals")
+}
+
+func TestStackManifestLocalsErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		context  map[string]any
+		expected string
+	}{
+		{
+			name: "circular reference",
+			manifest: `
+locals:
+  a: "{{ .locals.b }}"
+  b: "{{ .locals.c }}-x"
+  c: "{{ .locals.a }}"
+`,
+			expected: "circular reference in the locals: a -> b -> c -> a in the stack manifest 'deploy/dev.yaml'",
+		},
+		{
+			name: "self reference",
+			manifest: `
+locals:
+  a: "{{ .locals.a }}"
+`,
+			expected: "circular reference in the locals: a -> a",
+		},
+		{
+			name: "undefined local in locals",
+			manifest: `
+locals:
+  a: "{{ .locals.b }}"
+`,
+			expected: "undefined local 'b' referenced by the local 'a' in the stack manifest 'deploy/dev.yaml'",
+		},
+		{
+			name: "undefined local in manifest",
+			manifest: `
+locals:
+  a: x
+vars:
+  name: "{{ .locals.name }}"
+`,
+			expected: "undefined local 'name' in the stack manifest 'deploy/dev.yaml'",
+		},
+		{
+			name: "invalid locals section",
+			manifest: `
+locals:
+  - a
+`,
+			expected: "invalid 'locals' section in the stack manifest 'deploy/dev.yaml'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := evaluateTestStackManifestLocals(tt.manifest, tt.context)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
+
+func TestStackManifestLocalsEvaluation(t *testing.T) {
+	manifest := `
+vars:
+  stage: dev
+locals:
+  name: "{{ .locals.prefix }}-{{ .vars.stage }}"
+  prefix: "{{ .namespace }}"
+  tags:
+    Name: "{{ .locals.name }}"
+  zones:
+    - "{{ .locals.prefix }}-a"
+  count: 3
+components:
+  terraform:
+    vpc:
+      vars:
+        name: "{{ .locals.name }}"
+        zones: "{{ .locals.zones | toJson }}"
+        count: "{{ .locals.count }}"
+        workspace: "{{ .vars.stage }}-{{ .atmos_component }}"
+        label: "{{ .locals.name }}-{{ .atmos_component }}-{{ .atmos_stack | lower }}"
+`
+
+	stackConfigMap, err := evaluateTestStackManifestLocals(manifest, map[string]any{"namespace": "acme"})
+	require.NoError(t, err)
+
+	assert.NotContains(t, stackConfigMap, "locals")
+	assert.Equal(t, map[string]any{
+		"name":  "acme-dev",
+		"zones": `["acme-a"]`,
+		"count": "3",
+		// The templates that don't reference the locals are processed later for the components
+		"workspace": "{{ .vars.stage }}-{{ .atmos_component }}",
+		// Only the template actions that reference the locals are processed in the strings that mix them with the other templates
+		"label": "acme-dev-{{.atmos_component}}-{{.atmos_stack | lower}}",
+	}, stackConfigMap["components"].(map[string]any)["terraform"].(map[string]any)["vpc"].(map[string]any)["vars"])
+}
+
+func TestStackManifestLocalsReferences(t *testing.T) {
+	manifest := `
+locals:
+  name: acme
+  enabled: true
+vars:
+  endpoint: "https://api.locals.example.com"
+  settings_local: "{{ .settings.locals.x }}"
+  helm_value: "{{ .Values.locals.x }}"
+  root_name: "{{ $.locals.name }}-{{ .atmos_component }}"
+  enabled: "{{ if .locals.enabled }}{{ .locals.name }}{{ end }}"
+`
+
+	stackConfigMap, err := evaluateTestStackManifestLocals(manifest, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{
+		// The strings and the fields named `locals` outside of the `.locals` section are not references to the locals
+		"endpoint":       "https://api.locals.example.com",
+		"settings_local": "{{ .settings.locals.x }}",
+		"helm_value":     "{{ .Values.locals.x }}",
+		"root_name":      "acme-{{.atmos_component}}",
+		"enabled":        "acme",
+	}, stackConfigMap["vars"])
+
+	// The references are not processed in the manifests without the `locals` section
+	stackConfigMap, err = evaluateTestStackManifestLocals(`
+vars:
+  name: "{{ .locals.name }}"
+`, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "{{ .locals.name }}", stackConfigMap["vars"].(map[string]any)["name"])
+
+	refs, err := getLocalsReferences([]any{
+		"{{ .locals.a }}-{{ .locals.b.key }}",
+		"{{ range .locals.c }}{{ . }}{{ else }}{{ .locals.d }}{{ end }}",
+		"{{ .vars.locals.e }} locals.f .locals.g",
+		"{{ invalid",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c", "d"}, refs)
+}
+
+func evaluateTestStackManifestLocals(manifest string, context map[string]any) (map[string]any, error) {
+	atmosConfig := schema.AtmosConfiguration{}
+
+	locals, err := newStackManifestLocals(atmosConfig, "deploy/dev.yaml", "/stacks/deploy/dev.yaml", manifest, context)
+	if err != nil {
+		return nil, err
+	}
+
+	stackConfigMap, err := u.UnmarshalYAML[map[string]any](manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	return locals.apply(stackConfigMap)
+}
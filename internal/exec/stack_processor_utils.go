@@ -122,6 +122,11 @@ func ProcessYAMLConfigFiles(
 
 			finalConfig["imports"] = uniqueImports
 
+			m.RemoveMergeDirectives(stackConfig)
+			for _, importConfig := range importsConfig {
+				m.RemoveMergeDirectives(importConfig)
+			}
+
 			yamlConfig, err := u.ConvertToYAML(finalConfig)
 			if err != nil {
 				errorResult = err
@@ -172,6 +177,52 @@ func ProcessYAMLConfigFile(
 	map[string]any,
 	map[string]any,
 	error,
+) {
+	return processYAMLConfigFile(
+		atmosConfig,
+		basePath,
+		filePath,
+		importsConfig,
+		context,
+		ignoreMissingFiles,
+		skipTemplatesProcessingInImports,
+		ignoreMissingTemplateValues,
+		skipIfMissing,
+		parentTerraformOverrides,
+		parentHelmfileOverrides,
+		atmosManifestJsonSchemaFilePath,
+		nil,
+		"",
+	)
+}
+
+// processYAMLConfigFile implements ProcessYAMLConfigFile.
+// If `provenance` is not nil, the values in the stack manifests are replaced with the markers of their provenance
+// (file, line and column), and the returned stack config contains the provenance of the deep-merged values.
+// `remoteImportSource` is the remote import of the manifest (if the manifest was imported from a remote source).
+// The local imports in the remote manifests are imported from the same remote source, and are named after it
+func processYAMLConfigFile(
+	atmosConfig schema.AtmosConfiguration,
+	basePath string,
+	filePath string,
+	importsConfig map[string]map[string]any,
+	context map[string]any,
+	ignoreMissingFiles bool,
+	skipTemplatesProcessingInImports bool,
+	ignoreMissingTemplateValues bool,
+	skipIfMissing bool,
+	parentTerraformOverrides map[string]any,
+	parentHelmfileOverrides map[string]any,
+	atmosManifestJsonSchemaFilePath string,
+	provenance *manifestProvenance,
+	remoteImportSource string,
+) (
+	map[string]any,
+	map[string]map[string]any,
+	map[string]any,
+	map[string]any,
+	map[string]any,
+	error,
 ) {
 	var stackConfigs []map[string]any
 	relativeFilePath := u.TrimBasePathFromPath(basePath+"/", filePath)
@@ -208,10 +259,21 @@ func ProcessYAMLConfigFile(
 	stackManifestTemplatesProcessed := stackYamlConfig
 	stackManifestTemplatesErrorMessage := ""
 
+	// Evaluate the file-scoped `locals` in the stack manifest
+	locals, err := newStackManifestLocals(atmosConfig, relativeFilePath, filePath, stackYamlConfig, context)
+	if err != nil {
+		return nil, nil, nil, nil, nil, err
+	}
+
 	// Process `Go` templates in the imported stack manifest using the provided `context`
 	// https://atmos.tools/core-concepts/stacks/imports#go-templates-in-imports
 	if !skipTemplatesProcessingInImports && len(context) > 0 {
-		stackManifestTemplatesProcessed, err = ProcessTmpl(relativeFilePath, stackYamlConfig, context, ignoreMissingTemplateValues)
+		tmplData := context
+		if locals.evaluated {
+			tmplData = locals.templateData()
+		}
+
+		stackManifestTemplatesProcessed, err = ProcessTmpl(relativeFilePath, stackYamlConfig, tmplData, ignoreMissingTemplateValues)
 		if err != nil {
 			if atmosConfig.Logs.Level == u.LogLevelTrace || atmosConfig.Logs.Level == u.LogLevelDebug {
 				stackManifestTemplatesErrorMessage = fmt.Sprintf("\n\n%s", stackYamlConfig)
@@ -243,6 +305,7 @@ func ProcessYAMLConfigFile(
 		if err != nil {
 			return nil, nil, nil, nil, nil, err
 		}
+		dataFromJson = m.RemoveMergeDirectives(dataFromJson)
 
 		compiler := jsonschema.NewCompiler()
 
@@ -278,23 +341,39 @@ func ProcessYAMLConfigFile(
 		}
 	}
 
+	// Render the values that reference the `locals`, and remove the `locals` section, so it's not deep-merged into the components
+	stackConfigMap, err = locals.apply(stackConfigMap)
+	if err != nil {
+		return nil, nil, nil, nil, nil, err
+	}
+
+	// Replace the values in the stack manifest with the markers of their provenance.
+	// The markers are deep-merged in the same way as the values, so the deep-merged markers point to the values that won
+	if provenance != nil {
+		provenance = provenance.withManifest(relativeFilePath)
+		stackConfigMap, err = provenance.shadowManifest(stackManifestTemplatesProcessed, stackConfigMap)
+		if err != nil {
+			return nil, nil, nil, nil, nil, err
+		}
+	}
+
 	// Check if the `overrides` sections exist and if we need to process overrides for the components in this stack manifest and its imports
 
 	// Global overrides in this stack manifest
 	if i, ok := stackConfigMap[cfg.OverridesSectionName]; ok {
-		if globalOverrides, ok = i.(map[string]any); !ok {
+		if globalOverrides, ok = getStackSectionMap(i); !ok {
 			return nil, nil, nil, nil, nil, fmt.Errorf("invalid 'overrides' section in the stack manifest '%s'", relativeFilePath)
 		}
 	}
 
 	// Terraform overrides in this stack manifest
 	if o, ok := stackConfigMap[cfg.TerraformSectionName]; ok {
-		if globalTerraformSection, ok = o.(map[string]any); !ok {
+		if globalTerraformSection, ok = getStackSectionMap(o); !ok {
 			return nil, nil, nil, nil, nil, fmt.Errorf("invalid 'terraform' section in the stack manifest '%s'", relativeFilePath)
 		}
 
 		if i, ok := globalTerraformSection[cfg.OverridesSectionName]; ok {
-			if terraformOverrides, ok = i.(map[string]any); !ok {
+			if terraformOverrides, ok = getStackSectionMap(i); !ok {
 				return nil, nil, nil, nil, nil, fmt.Errorf("invalid 'terraform.overrides' section in the stack manifest '%s'", relativeFilePath)
 			}
 		}
@@ -302,12 +381,12 @@ func ProcessYAMLConfigFile(
 
 	// Helmfile overrides in this stack manifest
 	if o, ok := stackConfigMap[cfg.HelmfileSectionName]; ok {
-		if globalHelmfileSection, ok = o.(map[string]any); !ok {
+		if globalHelmfileSection, ok = getStackSectionMap(o); !ok {
 			return nil, nil, nil, nil, nil, fmt.Errorf("invalid 'helmfile' section in the stack manifest '%s'", relativeFilePath)
 		}
 
 		if i, ok := globalHelmfileSection[cfg.OverridesSectionName]; ok {
-			if helmfileOverrides, ok = i.(map[string]any); !ok {
+			if helmfileOverrides, ok = getStackSectionMap(i); !ok {
 				return nil, nil, nil, nil, nil, fmt.Errorf("invalid 'terraform.overrides' section in the stack manifest '%s'", relativeFilePath)
 			}
 		}
@@ -344,6 +423,18 @@ func ProcessYAMLConfigFile(
 			return nil, nil, nil, nil, nil, fmt.Errorf("invalid empty import in the manifest '%s'", relativeFilePath)
 		}
 
+		// The remote imports are downloaded into the local cache, and the manifests are imported from the downloaded source.
+		// The imports in the remote manifests are resolved relative to the root of the source
+		importBasePath := basePath
+		importSource := remoteImportSource
+		if isRemoteStackImport(imp) {
+			importSource = imp
+			importBasePath, imp, err = getRemoteStackImport(atmosConfig, imp)
+			if err != nil {
+				return nil, nil, nil, nil, nil, fmt.Errorf("invalid import in the manifest '%s'\n%v", relativeFilePath, err)
+			}
+		}
+
 		// If the import file is specified without extension, use `.yaml` as default
 		impWithExt := imp
 		ext := filepath.Ext(imp)
@@ -357,7 +448,7 @@ func ProcessYAMLConfigFile(
 
 			found := false
 			for _, extension := range extensions {
-				testPath := filepath.Join(basePath, imp+extension)
+				testPath := filepath.Join(importBasePath, imp+extension)
 				if _, err := os.Stat(testPath); err == nil {
 					impWithExt = imp + extension
 					found = true
@@ -372,12 +463,12 @@ func ProcessYAMLConfigFile(
 		} else if ext == u.YamlFileExtension || ext == u.YmlFileExtension {
 			// Check if there's a template version of this file
 			templatePath := impWithExt + u.TemplateExtension
-			if _, err := os.Stat(filepath.Join(basePath, templatePath)); err == nil {
+			if _, err := os.Stat(filepath.Join(importBasePath, templatePath)); err == nil {
 				impWithExt = templatePath
 			}
 		}
 
-		impWithExtPath := filepath.Join(basePath, impWithExt)
+		impWithExtPath := filepath.Join(importBasePath, impWithExt)
 
 		if impWithExtPath == filePath {
 			errorMessage := fmt.Sprintf("invalid import in the manifest '%s'\nThe file imports itself in '%s'",
@@ -432,9 +523,9 @@ func ProcessYAMLConfigFile(
 
 		// Process the imports in the current manifest
 		for _, importFile := range importMatches {
-			yamlConfig, _, yamlConfigRaw, importTerraformOverrides, importHelmfileOverrides, err2 := ProcessYAMLConfigFile(
+			yamlConfig, _, yamlConfigRaw, importTerraformOverrides, importHelmfileOverrides, err2 := processYAMLConfigFile(
 				atmosConfig,
-				basePath,
+				importBasePath,
 				importFile,
 				importsConfig,
 				mergedContext,
@@ -445,6 +536,8 @@ func ProcessYAMLConfigFile(
 				finalTerraformOverrides,
 				finalHelmfileOverrides,
 				"",
+				provenance,
+				importSource,
 			)
 			if err2 != nil {
 				return nil, nil, nil, nil, nil, err2
@@ -470,13 +563,16 @@ func ProcessYAMLConfigFile(
 				return nil, nil, nil, nil, nil, err
 			}
 
-			importRelativePathWithExt := strings.Replace(filepath.ToSlash(importFile), filepath.ToSlash(basePath)+"/", "", 1)
+			importRelativePathWithExt := strings.Replace(filepath.ToSlash(importFile), filepath.ToSlash(importBasePath)+"/", "", 1)
 			ext2 := filepath.Ext(importRelativePathWithExt)
 			if ext2 == "" {
 				ext2 = u.DefaultStackConfigFileExtension
 			}
 
 			importRelativePathWithoutExt := strings.TrimSuffix(importRelativePathWithExt, ext2)
+			if importSource != "" {
+				importRelativePathWithoutExt = getRemoteStackImportName(importSource, importRelativePathWithoutExt)
+			}
 			importsConfig[importRelativePathWithoutExt] = yamlConfigRaw
 		}
 	}
@@ -571,42 +667,42 @@ func ProcessStackConfig(
 
 	// Global sections
 	if i, ok := config["vars"]; ok {
-		globalVarsSection, ok = i.(map[string]any)
+		globalVarsSection, ok = getStackSectionMap(i)
 		if !ok {
 			return nil, fmt.Errorf("invalid 'vars' section in the file '%s'", stackName)
 		}
 	}
 
 	if i, ok := config["hooks"]; ok {
-		globalHooksSection, ok = i.(map[string]any)
+		globalHooksSection, ok = getStackSectionMap(i)
 		if !ok {
 			return nil, errors.Wrapf(ErrInvalidHooksSection, " '%s'", stackName)
 		}
 	}
 
 	if i, ok := config["settings"]; ok {
-		globalSettingsSection, ok = i.(map[string]any)
+		globalSettingsSection, ok = getStackSectionMap(i)
 		if !ok {
 			return nil, fmt.Errorf("invalid 'settings' section in the file '%s'", stackName)
 		}
 	}
 
 	if i, ok := config["env"]; ok {
-		globalEnvSection, ok = i.(map[string]any)
+		globalEnvSection, ok = getStackSectionMap(i)
 		if !ok {
 			return nil, fmt.Errorf("invalid 'env' section in the file '%s'", stackName)
 		}
 	}
 
 	if i, ok := config["terraform"]; ok {
-		globalTerraformSection, ok = i.(map[string]any)
+		globalTerraformSection, ok = getStackSectionMap(i)
 		if !ok {
 			return nil, fmt.Errorf("invalid 'terraform' section in the file '%s'", stackName)
 		}
 	}
 
 	if i, ok := config["helmfile"]; ok {
-		globalHelmfileSection, ok = i.(map[string]any)
+		globalHelmfileSection, ok = getStackSectionMap(i)
 		if !ok {
 			return nil, fmt.Errorf("invalid 'helmfile' section in the file '%s'", stackName)
 		}
@@ -628,14 +724,14 @@ func ProcessStackConfig(
 	}
 
 	if i, ok := globalTerraformSection["vars"]; ok {
-		terraformVars, ok = i.(map[string]any)
+		terraformVars, ok = getStackSectionMap(i)
 		if !ok {
 			return nil, fmt.Errorf("invalid 'terraform.vars' section in the file '%s'", stackName)
 		}
 	}
 
 	if i, ok := globalTerraformSection["hooks"]; ok {
-		terraformHooks, ok = i.(map[string]any)
+		terraformHooks, ok = getStackSectionMap(i)
 		if !ok {
 			return nil, errors.Wrapf(ErrInvalidTerraformHooksSection, "in file '%s'", stackName)
 		}
@@ -652,7 +748,7 @@ func ProcessStackConfig(
 	}
 
 	if i, ok := globalTerraformSection["settings"]; ok {
-		terraformSettings, ok = i.(map[string]any)
+		terraformSettings, ok = getStackSectionMap(i)
 		if !ok {
 			return nil, fmt.Errorf("invalid 'terraform.settings' section in the file '%s'", stackName)
 		}
@@ -664,7 +760,7 @@ func ProcessStackConfig(
 	}
 
 	if i, ok := globalTerraformSection["env"]; ok {
-		terraformEnv, ok = i.(map[string]any)
+		terraformEnv, ok = getStackSectionMap(i)
 		if !ok {
 			return nil, fmt.Errorf("invalid 'terraform.env' section in the file '%s'", stackName)
 		}
@@ -676,14 +772,14 @@ func ProcessStackConfig(
 	}
 
 	if i, ok := globalTerraformSection[cfg.ProvidersSectionName]; ok {
-		terraformProviders, ok = i.(map[string]any)
+		terraformProviders, ok = getStackSectionMap(i)
 		if !ok {
 			return nil, fmt.Errorf("invalid 'terraform.providers' section in the file '%s'", stackName)
 		}
 	}
 
 	if i, ok := globalTerraformSection[cfg.HooksSectionName]; ok {
-		terraformHooks, ok = i.(map[string]any)
+		terraformHooks, ok = getStackSectionMap(i)
 		if !ok {
 			return nil, fmt.Errorf("invalid 'terraform.hooks' section in the file '%s'", stackName)
 		}
@@ -701,7 +797,7 @@ func ProcessStackConfig(
 	}
 
 	if i, ok := globalTerraformSection["backend"]; ok {
-		globalBackendSection, ok = i.(map[string]any)
+		globalBackendSection, ok = getStackSectionMap(i)
 		if !ok {
 			return nil, fmt.Errorf("invalid 'terraform.backend' section in the file '%s'", stackName)
 		}
@@ -719,7 +815,7 @@ func ProcessStackConfig(
 	}
 
 	if i, ok := globalTerraformSection["remote_state_backend"]; ok {
-		globalRemoteStateBackendSection, ok = i.(map[string]any)
+		globalRemoteStateBackendSection, ok = getStackSectionMap(i)
 		if !ok {
 			return nil, fmt.Errorf("invalid 'terraform.remote_state_backend' section in the file '%s'", stackName)
 		}
@@ -734,7 +830,7 @@ func ProcessStackConfig(
 	}
 
 	if i, ok := globalHelmfileSection["vars"]; ok {
-		helmfileVars, ok = i.(map[string]any)
+		helmfileVars, ok = getStackSectionMap(i)
 		if !ok {
 			return nil, fmt.Errorf("invalid 'helmfile.vars' section in the file '%s'", stackName)
 		}
@@ -746,7 +842,7 @@ func ProcessStackConfig(
 	}
 
 	if i, ok := globalHelmfileSection["settings"]; ok {
-		helmfileSettings, ok = i.(map[string]any)
+		helmfileSettings, ok = getStackSectionMap(i)
 		if !ok {
 			return nil, fmt.Errorf("invalid 'helmfile.settings' section in the file '%s'", stackName)
 		}
@@ -758,7 +854,7 @@ func ProcessStackConfig(
 	}
 
 	if i, ok := globalHelmfileSection["env"]; ok {
-		helmfileEnv, ok = i.(map[string]any)
+		helmfileEnv, ok = getStackSectionMap(i)
 		if !ok {
 			return nil, fmt.Errorf("invalid 'helmfile.env' section in the file '%s'", stackName)
 		}
@@ -788,7 +884,7 @@ func ProcessStackConfig(
 
 				componentVars := map[string]any{}
 				if i, ok := componentMap[cfg.VarsSectionName]; ok {
-					componentVars, ok = i.(map[string]any)
+					componentVars, ok = getStackSectionMap(i)
 					if !ok {
 						return nil, fmt.Errorf("invalid 'components.terraform.%s.vars' section in the file '%s'", component, stackName)
 					}
@@ -796,12 +892,12 @@ func ProcessStackConfig(
 
 				componentSettings := map[string]any{}
 				if i, ok := componentMap[cfg.SettingsSectionName]; ok {
-					componentSettings, ok = i.(map[string]any)
+					componentSettings, ok = getStackSectionMap(i)
 					if !ok {
 						return nil, fmt.Errorf("invalid 'components.terraform.%s.settings' section in the file '%s'", component, stackName)
 					}
 
-					if i, ok := componentSettings["spacelift"]; ok {
+					if i, ok := componentSettings["spacelift"]; ok && !m.IsUnset(i) {
 						_, ok = i.(map[string]any)
 						if !ok {
 							return nil, fmt.Errorf("invalid 'components.terraform.%s.settings.spacelift' section in the file '%s'", component, stackName)
@@ -811,7 +907,7 @@ func ProcessStackConfig(
 
 				componentEnv := map[string]any{}
 				if i, ok := componentMap[cfg.EnvSectionName]; ok {
-					componentEnv, ok = i.(map[string]any)
+					componentEnv, ok = getStackSectionMap(i)
 					if !ok {
 						return nil, fmt.Errorf("invalid 'components.terraform.%s.env' section in the file '%s'", component, stackName)
 					}
@@ -819,7 +915,7 @@ func ProcessStackConfig(
 
 				componentProviders := map[string]any{}
 				if i, ok := componentMap[cfg.ProvidersSectionName]; ok {
-					componentProviders, ok = i.(map[string]any)
+					componentProviders, ok = getStackSectionMap(i)
 					if !ok {
 						return nil, fmt.Errorf("invalid 'components.terraform.%s.providers' section in the file '%s'", component, stackName)
 					}
@@ -827,7 +923,7 @@ func ProcessStackConfig(
 
 				componentHooks := map[string]any{}
 				if i, ok := componentMap[cfg.HooksSectionName]; ok {
-					componentHooks, ok = i.(map[string]any)
+					componentHooks, ok = getStackSectionMap(i)
 					if !ok {
 						return nil, fmt.Errorf("invalid 'components.terraform.%s.hooks' section in the file '%s'", component, stackName)
 					}
@@ -837,7 +933,7 @@ func ProcessStackConfig(
 				// This is per component, not deep-merged and not inherited from base components and globals.
 				componentMetadata := map[string]any{}
 				if i, ok := componentMap[cfg.MetadataSectionName]; ok {
-					componentMetadata, ok = i.(map[string]any)
+					componentMetadata, ok = getStackSectionMap(i)
 					if !ok {
 						return nil, fmt.Errorf("invalid 'components.terraform.%s.metadata' section in the file '%s'", component, stackName)
 					}
@@ -855,7 +951,7 @@ func ProcessStackConfig(
 				}
 
 				if i, ok := componentMap[cfg.BackendSectionName]; ok {
-					componentBackendSection, ok = i.(map[string]any)
+					componentBackendSection, ok = getStackSectionMap(i)
 					if !ok {
 						return nil, fmt.Errorf("invalid 'components.terraform.%s.backend' section in the file '%s'", component, stackName)
 					}
@@ -873,7 +969,7 @@ func ProcessStackConfig(
 				}
 
 				if i, ok := componentMap["remote_state_backend"]; ok {
-					componentRemoteStateBackendSection, ok = i.(map[string]any)
+					componentRemoteStateBackendSection, ok = getStackSectionMap(i)
 					if !ok {
 						return nil, fmt.Errorf("invalid 'components.terraform.%s.remote_state_backend' section in the file '%s'", component, stackName)
 					}
@@ -897,24 +993,24 @@ func ProcessStackConfig(
 				componentOverridesTerraformCommand := ""
 
 				if i, ok := componentMap[cfg.OverridesSectionName]; ok {
-					if componentOverrides, ok = i.(map[string]any); !ok {
+					if componentOverrides, ok = getStackSectionMap(i); !ok {
 						return nil, fmt.Errorf("invalid 'components.terraform.%s.overrides' in the manifest '%s'", component, stackName)
 					}
 
 					if i, ok = componentOverrides[cfg.VarsSectionName]; ok {
-						if componentOverridesVars, ok = i.(map[string]any); !ok {
+						if componentOverridesVars, ok = getStackSectionMap(i); !ok {
 							return nil, fmt.Errorf("invalid 'components.terraform.%s.overrides.vars' in the manifest '%s'", component, stackName)
 						}
 					}
 
 					if i, ok = componentOverrides[cfg.SettingsSectionName]; ok {
-						if componentOverridesSettings, ok = i.(map[string]any); !ok {
+						if componentOverridesSettings, ok = getStackSectionMap(i); !ok {
 							return nil, fmt.Errorf("invalid 'components.terraform.%s.overrides.settings' in the manifest '%s'", component, stackName)
 						}
 					}
 
 					if i, ok = componentOverrides[cfg.EnvSectionName]; ok {
-						if componentOverridesEnv, ok = i.(map[string]any); !ok {
+						if componentOverridesEnv, ok = getStackSectionMap(i); !ok {
 							return nil, fmt.Errorf("invalid 'components.terraform.%s.overrides.env' in the manifest '%s'", component, stackName)
 						}
 					}
@@ -926,13 +1022,13 @@ func ProcessStackConfig(
 					}
 
 					if i, ok = componentOverrides[cfg.ProvidersSectionName]; ok {
-						if componentOverridesProviders, ok = i.(map[string]any); !ok {
+						if componentOverridesProviders, ok = getStackSectionMap(i); !ok {
 							return nil, fmt.Errorf("invalid 'components.terraform.%s.overrides.providers' in the manifest '%s'", component, stackName)
 						}
 					}
 
 					if i, ok = componentOverrides[cfg.HooksSectionName]; ok {
-						if componentOverridesHooks, ok = i.(map[string]any); !ok {
+						if componentOverridesHooks, ok = getStackSectionMap(i); !ok {
 							return nil, fmt.Errorf("invalid 'components.terraform.%s.overrides.hooks' in the manifest '%s'", component, stackName)
 						}
 					}
@@ -1011,7 +1107,7 @@ func ProcessStackConfig(
 
 				baseComponents = append(baseComponents, baseComponentName)
 
-				if inheritList, inheritListExist := componentMetadata["inherits"].([]any); inheritListExist {
+				if inheritList, inheritListExist := getMetadataInheritsList(componentMetadata); inheritListExist {
 					for _, v := range inheritList {
 						baseComponentFromInheritList, ok := v.(string)
 						if !ok {
@@ -1144,7 +1240,7 @@ func ProcessStackConfig(
 
 				finalComponentBackend := map[string]any{}
 				if i, ok := finalComponentBackendSection[finalComponentBackendType]; ok {
-					finalComponentBackend, ok = i.(map[string]any)
+					finalComponentBackend, ok = getStackSectionMap(i)
 					if !ok {
 						return nil, fmt.Errorf("invalid 'terraform.backend' section for the component '%s'", component)
 					}
@@ -1240,7 +1336,7 @@ func ProcessStackConfig(
 
 				finalComponentRemoteStateBackend := map[string]any{}
 				if i, ok := finalComponentRemoteStateBackendSectionMerged[finalComponentRemoteStateBackendType]; ok {
-					finalComponentRemoteStateBackend, ok = i.(map[string]any)
+					finalComponentRemoteStateBackend, ok = getStackSectionMap(i)
 					if !ok {
 						return nil, fmt.Errorf("invalid 'terraform.remote_state_backend' section for the component '%s'", component)
 					}
@@ -1283,7 +1379,7 @@ func ProcessStackConfig(
 					}
 				}
 				if componentIsAbstract {
-					if i, ok := finalComponentSettings["spacelift"]; ok {
+					if i, ok := finalComponentSettings["spacelift"]; ok && !m.IsUnset(i) {
 						spaceliftSettings, ok := i.(map[string]any)
 						if !ok {
 							return nil, fmt.Errorf("invalid 'components.terraform.%s.settings.spacelift' section in the file '%s'", component, stackName)
@@ -1340,7 +1436,7 @@ func ProcessStackConfig(
 
 				componentVars := map[string]any{}
 				if i2, ok := componentMap[cfg.VarsSectionName]; ok {
-					componentVars, ok = i2.(map[string]any)
+					componentVars, ok = getStackSectionMap(i2)
 					if !ok {
 						return nil, fmt.Errorf("invalid 'components.helmfile.%s.vars' section in the file '%s'", component, stackName)
 					}
@@ -1348,7 +1444,7 @@ func ProcessStackConfig(
 
 				componentSettings := map[string]any{}
 				if i, ok := componentMap[cfg.SettingsSectionName]; ok {
-					componentSettings, ok = i.(map[string]any)
+					componentSettings, ok = getStackSectionMap(i)
 					if !ok {
 						return nil, fmt.Errorf("invalid 'components.helmfile.%s.settings' section in the file '%s'", component, stackName)
 					}
@@ -1356,7 +1452,7 @@ func ProcessStackConfig(
 
 				componentEnv := map[string]any{}
 				if i, ok := componentMap[cfg.EnvSectionName]; ok {
-					componentEnv, ok = i.(map[string]any)
+					componentEnv, ok = getStackSectionMap(i)
 					if !ok {
 						return nil, fmt.Errorf("invalid 'components.helmfile.%s.env' section in the file '%s'", component, stackName)
 					}
@@ -1366,7 +1462,7 @@ func ProcessStackConfig(
 				// This is per component, not deep-merged and not inherited from base components and globals.
 				componentMetadata := map[string]any{}
 				if i, ok := componentMap[cfg.MetadataSectionName]; ok {
-					componentMetadata, ok = i.(map[string]any)
+					componentMetadata, ok = getStackSectionMap(i)
 					if !ok {
 						return nil, fmt.Errorf("invalid 'components.helmfile.%s.metadata' section in the file '%s'", component, stackName)
 					}
@@ -1388,24 +1484,24 @@ func ProcessStackConfig(
 				componentOverridesHelmfileCommand := ""
 
 				if i, ok := componentMap[cfg.OverridesSectionName]; ok {
-					if componentOverrides, ok = i.(map[string]any); !ok {
+					if componentOverrides, ok = getStackSectionMap(i); !ok {
 						return nil, fmt.Errorf("invalid 'components.helmfile.%s.overrides' in the manifest '%s'", component, stackName)
 					}
 
 					if i, ok = componentOverrides[cfg.VarsSectionName]; ok {
-						if componentOverridesVars, ok = i.(map[string]any); !ok {
+						if componentOverridesVars, ok = getStackSectionMap(i); !ok {
 							return nil, fmt.Errorf("invalid 'components.helmfile.%s.overrides.vars' in the manifest '%s'", component, stackName)
 						}
 					}
 
 					if i, ok = componentOverrides[cfg.SettingsSectionName]; ok {
-						if componentOverridesSettings, ok = i.(map[string]any); !ok {
+						if componentOverridesSettings, ok = getStackSectionMap(i); !ok {
 							return nil, fmt.Errorf("invalid 'components.helmfile.%s.overrides.settings' in the manifest '%s'", component, stackName)
 						}
 					}
 
 					if i, ok = componentOverrides[cfg.EnvSectionName]; ok {
-						if componentOverridesEnv, ok = i.(map[string]any); !ok {
+						if componentOverridesEnv, ok = getStackSectionMap(i); !ok {
 							return nil, fmt.Errorf("invalid 'components.helmfile.%s.overrides.env' in the manifest '%s'", component, stackName)
 						}
 					}
@@ -1478,7 +1574,7 @@ func ProcessStackConfig(
 
 				baseComponents = append(baseComponents, baseComponentName)
 
-				if inheritList, inheritListExist := componentMetadata["inherits"].([]any); inheritListExist {
+				if inheritList, inheritListExist := getMetadataInheritsList(componentMetadata); inheritListExist {
 					for _, v := range inheritList {
 						baseComponentFromInheritList, ok := v.(string)
 						if !ok {
@@ -1612,6 +1708,9 @@ func ProcessStackConfig(
 	allComponents["terraform"] = terraformComponents
 	allComponents["helmfile"] = helmfileComponents
 
+	// The merge directives (e.g. `!append`) are applied when deep-merging the component sections, remove them from the final config
+	m.RemoveMergeDirectives(allComponents)
+
 	result := map[string]any{
 		"components": allComponents,
 	}
@@ -1815,6 +1914,10 @@ func ProcessImportSection(stackMap map[string]any, filePath string) ([]schema.St
 
 	// Check if the `import` section is a list of objects
 	importsList, ok := stackImports.([]any)
+	if ok {
+		// The imports are not deep-merged, so the merge directives (e.g. `!append`) do not change the list of imports
+		_, importsList = m.GetListMergeDirective(importsList)
+	}
 	if !ok || len(importsList) == 0 {
 		return nil, fmt.Errorf("invalid 'import' section in the file '%s'", filePath)
 	}
@@ -1851,6 +1954,30 @@ func ProcessImportSection(stackMap map[string]any, filePath string) ([]schema.St
 	return result, nil
 }
 
+// getStackSectionMap returns the section of the stack manifest as a map.
+// The section tagged with `!unset` is returned as an empty map with the `replace` merge directive, so the section does not inherit
+// the values deep-merged before it (e.g. from the global sections and the base components).
+// The directive is removed from the final config by `RemoveMergeDirectives`
+func getStackSectionMap(section any) (map[string]any, bool) {
+	if m.IsUnset(section) {
+		return map[string]any{u.AtmosMergeDirectiveMarker: m.MergeDirectiveReplace}, true
+	}
+	result, ok := section.(map[string]any)
+	return result, ok
+}
+
+// getMetadataInheritsList returns the `metadata.inherits` list of the component without the merge directive marker.
+// The list is deep-merged from the imported manifests, and can be tagged with `!append` or `!prepend` to add the base components
+// to the list defined in the imports
+func getMetadataInheritsList(componentMetadata map[string]any) ([]any, bool) {
+	inheritList, ok := componentMetadata["inherits"].([]any)
+	if !ok {
+		return nil, false
+	}
+	_, inheritList = m.GetListMergeDirective(inheritList)
+	return inheritList, true
+}
+
 // sectionContainsAnyNotEmptySections checks if a section contains any of the provided low-level sections, and it's not empty
 func sectionContainsAnyNotEmptySections(section map[string]any, sectionsToCheck []string) bool {
 	for _, s := range sectionsToCheck {
@@ -2067,12 +2194,12 @@ func ProcessBaseComponentConfig(
 		// This is per component, not deep-merged and not inherited from base components and globals.
 		componentMetadata := map[string]any{}
 		if i, ok := baseComponentMap["metadata"]; ok {
-			componentMetadata, ok = i.(map[string]any)
+			componentMetadata, ok = getStackSectionMap(i)
 			if !ok {
 				return fmt.Errorf("invalid '%s.metadata' section in the stack '%s'", component, stack)
 			}
 
-			if inheritList, inheritListExist := componentMetadata["inherits"].([]any); inheritListExist {
+			if inheritList, inheritListExist := getMetadataInheritsList(componentMetadata); inheritListExist {
 				for _, v := range inheritList {
 					baseComponentFromInheritList, ok := v.(string)
 					if !ok {
@@ -2111,35 +2238,35 @@ func ProcessBaseComponentConfig(
 		}
 
 		if baseComponentVarsSection, baseComponentVarsSectionExist := baseComponentMap["vars"]; baseComponentVarsSectionExist {
-			baseComponentVars, ok = baseComponentVarsSection.(map[string]any)
+			baseComponentVars, ok = getStackSectionMap(baseComponentVarsSection)
 			if !ok {
 				return fmt.Errorf("invalid '%s.vars' section in the stack '%s'", baseComponent, stack)
 			}
 		}
 
 		if baseComponentSettingsSection, baseComponentSettingsSectionExist := baseComponentMap["settings"]; baseComponentSettingsSectionExist {
-			baseComponentSettings, ok = baseComponentSettingsSection.(map[string]any)
+			baseComponentSettings, ok = getStackSectionMap(baseComponentSettingsSection)
 			if !ok {
 				return fmt.Errorf("invalid '%s.settings' section in the stack '%s'", baseComponent, stack)
 			}
 		}
 
 		if baseComponentEnvSection, baseComponentEnvSectionExist := baseComponentMap["env"]; baseComponentEnvSectionExist {
-			baseComponentEnv, ok = baseComponentEnvSection.(map[string]any)
+			baseComponentEnv, ok = getStackSectionMap(baseComponentEnvSection)
 			if !ok {
 				return fmt.Errorf("invalid '%s.env' section in the stack '%s'", baseComponent, stack)
 			}
 		}
 
 		if baseComponentProvidersSection, baseComponentProvidersSectionExist := baseComponentMap[cfg.ProvidersSectionName]; baseComponentProvidersSectionExist {
-			baseComponentProviders, ok = baseComponentProvidersSection.(map[string]any)
+			baseComponentProviders, ok = getStackSectionMap(baseComponentProvidersSection)
 			if !ok {
 				return fmt.Errorf("invalid '%s.providers' section in the stack '%s'", baseComponent, stack)
 			}
 		}
 
 		if baseComponentHooksSection, baseComponentHooksSectionExist := baseComponentMap[cfg.HooksSectionName]; baseComponentHooksSectionExist {
-			baseComponentHooks, ok = baseComponentHooksSection.(map[string]any)
+			baseComponentHooks, ok = getStackSectionMap(baseComponentHooksSection)
 			if !ok {
 				return fmt.Errorf("invalid '%s.hooks' section in the stack '%s'", baseComponent, stack)
 			}
@@ -2154,7 +2281,7 @@ func ProcessBaseComponentConfig(
 		}
 
 		if i, ok2 := baseComponentMap["backend"]; ok2 {
-			baseComponentBackendSection, ok = i.(map[string]any)
+			baseComponentBackendSection, ok = getStackSectionMap(i)
 			if !ok {
 				return fmt.Errorf("invalid '%s.backend' section in the stack '%s'", baseComponent, stack)
 			}
@@ -2169,7 +2296,7 @@ func ProcessBaseComponentConfig(
 		}
 
 		if i, ok2 := baseComponentMap["remote_state_backend"]; ok2 {
-			baseComponentRemoteStateBackendSection, ok = i.(map[string]any)
+			baseComponentRemoteStateBackendSection, ok = getStackSectionMap(i)
 			if !ok {
 				return fmt.Errorf("invalid '%s.remote_state_backend' section in the stack '%s'", baseComponent, stack)
 			}
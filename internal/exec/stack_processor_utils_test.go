@@ -0,0 +1,88 @@
+package exec
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudposse/atmos/pkg/schema"
+	u "github.com/cloudposse/atmos/pkg/utils"
+)
+
+func TestProcessStackConfigUnset(t *testing.T) {
+	startingDir, err := os.Getwd()
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.Chdir(startingDir))
+	}()
+
+	require.NoError(t, os.Chdir("../../tests/fixtures/scenarios/provenance"))
+
+	componentSection, err := ExecuteDescribeComponent("vpc", "dev", true, true, nil)
+	require.NoError(t, err)
+
+	// `flow_logs_enabled` and `settings.spacelift` are inherited from the base component `vpc/defaults`,
+	// and unset in the component with `!unset`
+	vars, ok := componentSection["vars"].(map[string]any)
+	require.True(t, ok)
+	assert.NotContains(t, vars, "flow_logs_enabled")
+	assert.Equal(t, "10.0.0.0/16", vars["cidr"])
+
+	settings, ok := componentSection["settings"].(map[string]any)
+	require.True(t, ok)
+	assert.NotContains(t, settings, "spacelift")
+
+	baseComponentSection, err := ExecuteDescribeComponent("vpc/defaults", "dev", true, true, nil)
+	require.NoError(t, err)
+	assert.Equal(t, true, baseComponentSection["vars"].(map[string]any)["flow_logs_enabled"])
+
+	// The whole `settings` section inherited from the base component and the global `env` section are unset with `!unset`.
+	// The overrides are deep-merged after the section is unset
+	componentSection, err = ExecuteDescribeComponent("vpc", "staging", true, true, nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"owner": "platform"}, componentSection["settings"])
+	assert.Empty(t, componentSection["env"])
+	assert.Equal(t, "10.0.0.0/16", componentSection["vars"].(map[string]any)["cidr"])
+}
+
+func TestProcessStackConfigMergeDirectivesOnImportAndInherits(t *testing.T) {
+	startingDir, err := os.Getwd()
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.Chdir(startingDir))
+	}()
+
+	require.NoError(t, os.Chdir("../../tests/fixtures/scenarios/merge-directives"))
+
+	// The `import` section is tagged with `!append`, and `metadata.inherits` of the component is tagged with `!append`
+	// to add a base component to the list defined in the imported manifest
+	componentSection, err := ExecuteDescribeComponent("vpc", "dev", true, true, nil)
+	require.NoError(t, err)
+
+	vars, ok := componentSection["vars"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "10.0.0.0/16", vars["cidr"])
+	assert.Equal(t, map[string]any{"Team": "network"}, vars["tags"])
+
+	metadata, ok := componentSection["metadata"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, []any{"vpc/defaults", "vpc/tags"}, metadata["inherits"])
+}
+
+func TestProcessImportSectionMergeDirective(t *testing.T) {
+	stackMap := map[string]any{
+		"import": []any{u.AtmosMergeDirectiveMarker + ":append", "catalog/vpc/defaults", "./_defaults"},
+	}
+
+	imports, err := ProcessImportSection(stackMap, "stacks/deploy/dev.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, []schema.StackImport{
+		{Path: "catalog/vpc/defaults"},
+		{Path: "stacks/deploy/_defaults"},
+	}, imports)
+
+	_, err = ProcessImportSection(map[string]any{"import": []any{u.AtmosMergeDirectiveMarker + ":append"}}, "stacks/deploy/dev.yaml")
+	assert.ErrorContains(t, err, "invalid 'import' section in the file 'stacks/deploy/dev.yaml'")
+}
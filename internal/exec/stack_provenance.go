@@ -0,0 +1,603 @@
+package exec
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	cfg "github.com/cloudposse/atmos/pkg/config"
+	m "github.com/cloudposse/atmos/pkg/merge"
+	"github.com/cloudposse/atmos/pkg/schema"
+	u "github.com/cloudposse/atmos/pkg/utils"
+)
+
+// provenanceMarkerPrefix is the prefix of the markers that replace the values in the stack manifests when tracking provenance
+const provenanceMarkerPrefix = "__atmos_provenance__:"
+
+// componentValueProvenance describes where a value in the component config is defined
+type componentValueProvenance struct {
+	File        string   `yaml:"file" json:"file"`
+	Line        int      `yaml:"line" json:"line"`
+	Column      int      `yaml:"column" json:"column"`
+	Step        string   `yaml:"step" json:"step"`
+	ImportChain []string `yaml:"import_chain" json:"import_chain"`
+	Directive   string   `yaml:"directive,omitempty" json:"directive,omitempty"`
+	// path is the path to the value in the stack manifest
+	path []string
+}
+
+// String returns the provenance as a YAML comment, e.g. `catalog/vpc.yaml:12:7 (component, imported by deploy/dev.yaml)`
+func (p componentValueProvenance) String() string {
+	s := fmt.Sprintf("%s:%d:%d (%s", p.File, p.Line, p.Column, p.Step)
+	if p.Directive != "" {
+		s += ", " + p.Directive
+	}
+	if len(p.ImportChain) > 1 {
+		s += ", imported by " + strings.Join(p.ImportChain[:len(p.ImportChain)-1], " > ")
+	}
+	return s + ")"
+}
+
+// provenanceTracker records the provenance of the values in the stack manifests, and creates the markers that replace the values
+type provenanceTracker struct {
+	mu      sync.Mutex
+	entries []componentValueProvenance
+}
+
+// marker records the provenance and returns the marker that replaces the value
+func (t *provenanceTracker) marker(entry componentValueProvenance) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.entries = append(t.entries, entry)
+	return provenanceMarkerPrefix + strconv.Itoa(len(t.entries)-1)
+}
+
+// lookup returns the provenance of the marker
+func (t *provenanceTracker) lookup(value any) (componentValueProvenance, bool) {
+	s, ok := value.(string)
+	if !ok {
+		return componentValueProvenance{}, false
+	}
+
+	id, ok := strings.CutPrefix(s, provenanceMarkerPrefix)
+	if !ok {
+		return componentValueProvenance{}, false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	i, err := strconv.Atoi(id)
+	if err != nil || i < 0 || i >= len(t.entries) {
+		return componentValueProvenance{}, false
+	}
+	return t.entries[i], true
+}
+
+// manifestProvenance tracks the provenance of the values in a stack manifest imported through `importChain`
+type manifestProvenance struct {
+	tracker     *provenanceTracker
+	importChain []string
+}
+
+// withManifest returns the provenance of the stack manifest imported by the current manifest
+func (p *manifestProvenance) withManifest(file string) *manifestProvenance {
+	return &manifestProvenance{
+		tracker:     p.tracker,
+		importChain: append(slices.Clone(p.importChain), file),
+	}
+}
+
+// shadowManifest returns the stack manifest config with all the values (including nested map values and list items)
+// replaced with the markers of their positions in the manifest. The `import` section is processed by Atmos and is not replaced
+func (p *manifestProvenance) shadowManifest(manifest string, stackConfigMap map[string]any) (map[string]any, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(manifest), &node); err != nil {
+		return nil, err
+	}
+
+	root := &node
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+
+	shadow := make(map[string]any, len(stackConfigMap))
+	for k, v := range stackConfigMap {
+		if k == cfg.ImportSectionName {
+			shadow[k] = v
+			continue
+		}
+		shadow[k] = p.shadowValue(v, findYAMLMappingValue(root, k), []string{k}, "")
+	}
+
+	return shadow, nil
+}
+
+// shadowValue replaces the value with the markers of the positions of the YAML nodes.
+// The values not found in the YAML node (e.g. the values included with the `!include` function)
+// get the position of the closest parent node.
+// The merge directives (e.g. `!append`) are kept, so the markers are deep-merged in the same way as the values,
+// and the provenance records the directive of the closest parent list or map
+func (p *manifestProvenance) shadowValue(value any, node *yaml.Node, path []string, directive string) any {
+	switch v := value.(type) {
+	case map[string]any:
+		if d := m.GetMapMergeDirective(v); d != "" {
+			directive = "!" + d
+		}
+		shadow := make(map[string]any, len(v))
+		for k, item := range v {
+			if k == u.AtmosMergeDirectiveMarker {
+				shadow[k] = item
+				continue
+			}
+			shadow[k] = p.shadowValue(item, yamlChildNode(node, findYAMLMappingValue(node, k)), append(slices.Clone(path), k), directive)
+		}
+		return shadow
+
+	case []any:
+		d, items := m.GetListMergeDirective(v)
+		if d != "" {
+			directive = "!" + d
+		}
+		shadow := make([]any, 0, len(v))
+		if len(items) < len(v) {
+			shadow = append(shadow, v[0])
+		}
+		for i, item := range items {
+			var itemNode *yaml.Node
+			if n := resolveYAMLAlias(node); n != nil && n.Kind == yaml.SequenceNode && i < len(n.Content) {
+				itemNode = resolveYAMLAlias(n.Content[i])
+			}
+			shadow = append(shadow, p.shadowValue(item, yamlChildNode(node, itemNode), append(slices.Clone(path), strconv.Itoa(i)), directive))
+		}
+		return shadow
+	}
+
+	if m.IsUnset(value) {
+		return value
+	}
+
+	entry := componentValueProvenance{
+		File:        p.importChain[len(p.importChain)-1],
+		ImportChain: p.importChain,
+		Directive:   directive,
+		path:        path,
+	}
+	if node != nil {
+		entry.Line = node.Line
+		entry.Column = node.Column
+	}
+
+	return p.tracker.marker(entry)
+}
+
+// yamlChildNode returns the child node if it's found, or a node with the position of the parent node
+func yamlChildNode(parent *yaml.Node, child *yaml.Node) *yaml.Node {
+	if child != nil || parent == nil {
+		return child
+	}
+	return &yaml.Node{Line: parent.Line, Column: parent.Column}
+}
+
+// resolveYAMLAlias returns the node referenced by the YAML alias
+func resolveYAMLAlias(node *yaml.Node) *yaml.Node {
+	for node != nil && node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	return node
+}
+
+// findYAMLMappingValue returns the value node of the key in the YAML mapping node, including the keys merged with `<<`
+func findYAMLMappingValue(node *yaml.Node, key string) *yaml.Node {
+	node = resolveYAMLAlias(node)
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	var value *yaml.Node
+	var merged []*yaml.Node
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		k := node.Content[i]
+		switch {
+		case k.Tag == "!!merge":
+			merged = append(merged, resolveYAMLAlias(node.Content[i+1]))
+		case k.Value == key:
+			value = node.Content[i+1]
+		}
+	}
+
+	if value != nil {
+		return resolveYAMLAlias(value)
+	}
+
+	for _, m := range merged {
+		if m != nil && m.Kind == yaml.SequenceNode {
+			for _, item := range m.Content {
+				if v := findYAMLMappingValue(item, key); v != nil {
+					return v
+				}
+			}
+			continue
+		}
+		if v := findYAMLMappingValue(m, key); v != nil {
+			return v
+		}
+	}
+
+	return nil
+}
+
+// getComponentProvenance returns the provenance of the values in the component config, keyed by the path to the value
+// (e.g. `vars.tags.Team`, `vars.subnets[0]`).
+// The stack manifest is processed again with the values replaced by the markers of their positions. The markers are deep-merged
+// by the same imports, inheritance and overrides as the values, so the markers in the final component config point to the values that won
+func getComponentProvenance(
+	atmosConfig schema.AtmosConfiguration,
+	component string,
+	componentSection map[string]any,
+) (map[string]componentValueProvenance, error) {
+	stackFile, ok := componentSection["atmos_stack_file"].(string)
+	if !ok || stackFile == "" {
+		return nil, fmt.Errorf("the stack manifest of the component '%s' is not found", component)
+	}
+
+	stackFilePath := ""
+	for _, p := range atmosConfig.StackConfigFilesAbsolutePaths {
+		stackFileName := strings.TrimSuffix(
+			strings.TrimSuffix(
+				u.TrimBasePathFromPath(atmosConfig.StacksBaseAbsolutePath+"/", p),
+				u.DefaultStackConfigFileExtension),
+			".yml",
+		)
+		if stackFileName == stackFile {
+			stackFilePath = p
+			break
+		}
+	}
+	if stackFilePath == "" {
+		return nil, fmt.Errorf("the stack manifest '%s' of the component '%s' is not found", stackFile, component)
+	}
+
+	stackConfig, _, _, _, _, err := ProcessYAMLConfigFile(
+		atmosConfig,
+		atmosConfig.StacksBaseAbsolutePath,
+		stackFilePath,
+		map[string]map[string]any{},
+		nil,
+		false,
+		false,
+		false,
+		false,
+		map[string]any{},
+		map[string]any{},
+		"",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	tracker := &provenanceTracker{}
+
+	shadowStackConfig, _, _, _, _, err := processYAMLConfigFile(
+		atmosConfig,
+		atmosConfig.StacksBaseAbsolutePath,
+		stackFilePath,
+		map[string]map[string]any{},
+		nil,
+		false,
+		false,
+		false,
+		false,
+		map[string]any{},
+		map[string]any{},
+		"",
+		&manifestProvenance{tracker: tracker},
+		"",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// The base components, the metadata and the backend types determine how the component config is deep-merged,
+	// so they are restored to their values before processing the components
+	finalShadowConfig, err := ProcessStackConfig(
+		atmosConfig,
+		atmosConfig.StacksBaseAbsolutePath,
+		atmosConfig.TerraformDirAbsolutePath,
+		atmosConfig.HelmfileDirAbsolutePath,
+		stackFilePath,
+		restoreProvenanceStructuralValues(shadowStackConfig, stackConfig),
+		false,
+		false,
+		"",
+		map[string]map[string][]string{},
+		map[string]map[string]any{},
+		false,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	componentType := ""
+	var componentShadow map[string]any
+
+	for _, t := range []string{cfg.TerraformSectionName, cfg.HelmfileSectionName} {
+		if c, ok := getNestedMap(finalShadowConfig, cfg.ComponentsSectionName, t)[component].(map[string]any); ok {
+			componentType = t
+			componentShadow = c
+			break
+		}
+	}
+	if componentShadow == nil {
+		return nil, fmt.Errorf("the component '%s' is not found in the stack manifest '%s'", component, stackFile)
+	}
+
+	// The metadata, the base component and the backend types are not deep-merged, their provenance is found in the stack config
+	componentConfigShadow := getNestedMap(shadowStackConfig, cfg.ComponentsSectionName, componentType, component)
+
+	if metadata, ok := componentConfigShadow[cfg.MetadataSectionName]; ok {
+		componentShadow[cfg.MetadataSectionName] = metadata
+	}
+
+	if baseComponent, ok := componentConfigShadow[cfg.ComponentSectionName]; ok {
+		componentShadow[cfg.ComponentSectionName] = baseComponent
+	} else if baseComponent, ok = getNestedMap(componentConfigShadow, cfg.MetadataSectionName)[cfg.ComponentSectionName]; ok {
+		componentShadow[cfg.ComponentSectionName] = baseComponent
+	}
+
+	var inheritance []string
+	switch v := componentSection[cfg.InheritanceSectionName].(type) {
+	case []string:
+		inheritance = v
+	case []any:
+		for _, baseComponent := range v {
+			inheritance = append(inheritance, fmt.Sprintf("%v", baseComponent))
+		}
+	}
+
+	for _, section := range []string{cfg.BackendTypeSectionName, cfg.RemoteStateBackendTypeSectionName} {
+		sources := []map[string]any{componentConfigShadow}
+		for _, baseComponent := range inheritance {
+			sources = append(sources, getNestedMap(shadowStackConfig, cfg.ComponentsSectionName, componentType, baseComponent))
+		}
+		sources = append(sources, getNestedMap(shadowStackConfig, componentType))
+
+		for _, source := range sources {
+			if v, ok := source[section]; ok {
+				componentShadow[section] = v
+				break
+			}
+		}
+	}
+
+	result := map[string]componentValueProvenance{}
+	collectComponentProvenance(tracker, component, componentType, componentSection, componentShadow, "", result)
+
+	return result, nil
+}
+
+// restoreProvenanceStructuralValues returns a copy of the stack config with the provenance markers, where the values that determine
+// how the components are processed (the base components, the metadata and the backend types) are restored from the stack config
+func restoreProvenanceStructuralValues(shadowStackConfig map[string]any, stackConfig map[string]any) map[string]any {
+	restore := func(shadow map[string]any, config map[string]any, sections ...string) map[string]any {
+		result := make(map[string]any, len(shadow))
+		for k, v := range shadow {
+			result[k] = v
+		}
+		for _, section := range sections {
+			if v, ok := config[section]; ok {
+				result[section] = v
+			}
+		}
+		return result
+	}
+
+	result := restore(shadowStackConfig, stackConfig)
+
+	components := restore(getNestedMap(shadowStackConfig, cfg.ComponentsSectionName), nil)
+
+	for _, componentType := range []string{cfg.TerraformSectionName, cfg.HelmfileSectionName} {
+		if _, ok := result[componentType].(map[string]any); ok {
+			result[componentType] = restore(
+				getNestedMap(shadowStackConfig, componentType),
+				getNestedMap(stackConfig, componentType),
+				cfg.BackendTypeSectionName,
+				cfg.RemoteStateBackendTypeSectionName,
+			)
+		}
+
+		shadowComponents, ok := components[componentType].(map[string]any)
+		if !ok {
+			continue
+		}
+
+		typeComponents := make(map[string]any, len(shadowComponents))
+		for name, shadowComponent := range shadowComponents {
+			shadowComponentMap, ok := shadowComponent.(map[string]any)
+			if !ok {
+				typeComponents[name] = shadowComponent
+				continue
+			}
+			typeComponents[name] = restore(
+				shadowComponentMap,
+				getNestedMap(stackConfig, cfg.ComponentsSectionName, componentType, name),
+				cfg.MetadataSectionName,
+				cfg.ComponentSectionName,
+				cfg.BackendTypeSectionName,
+				cfg.RemoteStateBackendTypeSectionName,
+			)
+		}
+		components[componentType] = typeComponents
+	}
+
+	if _, ok := result[cfg.ComponentsSectionName]; ok {
+		result[cfg.ComponentsSectionName] = components
+	}
+
+	return result
+}
+
+// getNestedMap returns the nested map at the path, or an empty map if it's not found
+func getNestedMap(m map[string]any, path ...string) map[string]any {
+	for _, k := range path {
+		v, ok := m[k].(map[string]any)
+		if !ok {
+			return map[string]any{}
+		}
+		m = v
+	}
+	return m
+}
+
+// collectComponentProvenance walks the component config and the markers in parallel,
+// and adds the provenance of each leaf value to the result
+func collectComponentProvenance(
+	tracker *provenanceTracker,
+	component string,
+	componentType string,
+	value any,
+	shadow any,
+	path string,
+	result map[string]componentValueProvenance,
+) {
+	switch v := value.(type) {
+	case map[string]any:
+		shadowMap, ok := shadow.(map[string]any)
+		if !ok {
+			return
+		}
+		for k, item := range v {
+			if shadowItem, ok := shadowMap[k]; ok {
+				collectComponentProvenance(tracker, component, componentType, item, shadowItem, joinProvenancePath(path, k), result)
+			}
+		}
+
+	case []any:
+		shadowList, ok := shadow.([]any)
+		if !ok {
+			return
+		}
+		for i := 0; i < len(v) && i < len(shadowList); i++ {
+			collectComponentProvenance(tracker, component, componentType, v[i], shadowList[i], fmt.Sprintf("%s[%d]", path, i), result)
+		}
+
+	default:
+		if entry, ok := tracker.lookup(shadow); ok {
+			entry.Step = getProvenanceStep(entry.path, component, componentType)
+			result[path] = entry
+		}
+	}
+}
+
+// joinProvenancePath appends the key to the path of the value in the component config
+func joinProvenancePath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// getProvenanceStep describes the import, inheritance or overrides step that set the value from its path in the stack manifest
+func getProvenanceStep(path []string, component string, componentType string) string {
+	switch {
+	case len(path) > 0 && path[0] == cfg.OverridesSectionName,
+		len(path) > 1 && path[0] == componentType && path[1] == cfg.OverridesSectionName:
+		return "overrides"
+
+	case len(path) > 3 && path[0] == cfg.ComponentsSectionName && path[2] != component:
+		return fmt.Sprintf("inherited from the base component '%s'", path[2])
+
+	case len(path) > 3 && path[0] == cfg.ComponentsSectionName && path[3] == cfg.OverridesSectionName:
+		return "component overrides"
+
+	case len(path) > 2 && path[0] == cfg.ComponentsSectionName:
+		return "component"
+
+	case len(path) > 0 && path[0] == componentType:
+		return fmt.Sprintf("global '%s' section", componentType)
+	}
+
+	return "global section"
+}
+
+// printComponentWithProvenance prints the component config with the provenance of the values,
+// or writes it to the file. In YAML, the provenance is rendered as comments. In JSON, it's added to the `provenance` section
+func printComponentWithProvenance(component string, componentSection map[string]any, format string, file string) error {
+	atmosConfig, err := cfg.InitCliConfig(schema.ConfigAndStacksInfo{}, true)
+	if err != nil {
+		return err
+	}
+
+	provenance, err := getComponentProvenance(atmosConfig, component, componentSection)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case "yaml":
+		y, err := convertComponentWithProvenanceToYAML(componentSection, provenance)
+		if err != nil {
+			return err
+		}
+		if file != "" {
+			return os.WriteFile(file, []byte(y), 0o644)
+		}
+		highlighted, err := u.HighlightCodeWithConfig(y, atmosConfig)
+		if err != nil {
+			highlighted = y
+		}
+		u.PrintMessage(highlighted)
+		return nil
+
+	case "json":
+		componentSection["provenance"] = provenance
+		return printOrWriteToFile(format, file, componentSection)
+	}
+
+	return errors.Errorf("invalid 'format': %s", format)
+}
+
+// convertComponentWithProvenanceToYAML converts the component config to YAML with the provenance of the values as line comments
+func convertComponentWithProvenanceToYAML(componentSection map[string]any, provenance map[string]componentValueProvenance) (string, error) {
+	var node yaml.Node
+	if err := node.Encode(componentSection); err != nil {
+		return "", err
+	}
+
+	addProvenanceComments(&node, "", provenance)
+
+	return u.ConvertToYAML(&node)
+}
+
+// addProvenanceComments adds the provenance of the scalar values as line comments to the YAML nodes
+func addProvenanceComments(node *yaml.Node, path string, provenance map[string]componentValueProvenance) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, n := range node.Content {
+			addProvenanceComments(n, path, provenance)
+		}
+
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			addProvenanceComments(node.Content[i+1], joinProvenancePath(path, node.Content[i].Value), provenance)
+		}
+
+	case yaml.SequenceNode:
+		for i, n := range node.Content {
+			addProvenanceComments(n, fmt.Sprintf("%s[%d]", path, i), provenance)
+		}
+
+	case yaml.ScalarNode:
+		if entry, ok := provenance[path]; ok {
+			node.LineComment = entry.String()
+		}
+	}
+}
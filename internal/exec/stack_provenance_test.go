@@ -0,0 +1,115 @@
+package exec
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cfg "github.com/cloudposse/atmos/pkg/config"
+	"github.com/cloudposse/atmos/pkg/schema"
+	u "github.com/cloudposse/atmos/pkg/utils"
+)
+
+func TestGetComponentProvenance(t *testing.T) {
+	startingDir, err := os.Getwd()
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.Chdir(startingDir))
+	}()
+
+	require.NoError(t, os.Chdir("../../tests/fixtures/scenarios/provenance"))
+
+	componentSection, err := ExecuteDescribeComponent("vpc", "dev", true, true, nil)
+	require.NoError(t, err)
+
+	atmosConfig, err := cfg.InitCliConfig(schema.ConfigAndStacksInfo{}, true)
+	require.NoError(t, err)
+
+	provenance, err := getComponentProvenance(atmosConfig, "vpc", componentSection)
+	require.NoError(t, err)
+
+	assert.Equal(t, componentValueProvenance{
+		File:        "catalog/vpc/defaults.yaml",
+		Line:        7,
+		Column:      15,
+		Step:        "inherited from the base component 'vpc/defaults'",
+		ImportChain: []string{"deploy/dev.yaml", "catalog/vpc/defaults.yaml"},
+		path:        []string{"components", "terraform", "vpc/defaults", "vars", "cidr"},
+	}, provenance["vars.cidr"])
+
+	// The component overrides the value inherited from the base component
+	assert.Equal(t, "deploy/dev.yaml", provenance["vars.tags.Team"].File)
+	assert.Equal(t, 19, provenance["vars.tags.Team"].Line)
+	assert.Equal(t, "component", provenance["vars.tags.Team"].Step)
+
+	assert.Equal(t, "mixins/dev.yaml", provenance["vars.tags.Environment"].File)
+	assert.Equal(t, "global section", provenance["vars.tags.Environment"].Step)
+
+	assert.Equal(t, 12, provenance["vars.subnets[1]"].Line)
+
+	// The items appended with the `!append` merge directive
+	assert.Equal(t, "deploy/dev.yaml", provenance["vars.subnets[2]"].File)
+	assert.Equal(t, "!append", provenance["vars.subnets[2]"].Directive)
+
+	assert.Equal(t, "overrides", provenance["vars.owner"].Step)
+	assert.Equal(t, "deploy/dev.yaml", provenance["metadata.component"].File)
+
+	// The values computed by Atmos don't have provenance
+	assert.NotContains(t, provenance, "workspace")
+
+	y, err := convertComponentWithProvenanceToYAML(componentSection, provenance)
+	require.NoError(t, err)
+	assert.Contains(t, y, "cidr: 10.0.0.0/16 # catalog/vpc/defaults.yaml:7:15 (inherited from the base component 'vpc/defaults', imported by deploy/dev.yaml)")
+	assert.Contains(t, y, "- public # catalog/vpc/defaults.yaml:12:13")
+}
+
+func TestShadowManifest(t *testing.T) {
+	manifest := `
+defaults: &defaults
+  region: us-east-2
+  tags:
+    Team: network
+vars:
+  <<: *defaults
+  stage: dev
+  subnets:
+    - private
+`
+	stackConfigMap, err := u.UnmarshalYAML[map[string]any](manifest)
+	require.NoError(t, err)
+
+	tracker := &provenanceTracker{}
+	provenance := (&manifestProvenance{tracker: tracker}).withManifest("deploy/dev.yaml")
+
+	shadow, err := provenance.shadowManifest(manifest, stackConfigMap)
+	require.NoError(t, err)
+
+	position := func(path ...any) (int, int) {
+		var v any = shadow
+		for _, p := range path {
+			switch k := p.(type) {
+			case string:
+				v = v.(map[string]any)[k]
+			case int:
+				v = v.([]any)[k]
+			}
+		}
+		entry, ok := tracker.lookup(v)
+		require.True(t, ok)
+		return entry.Line, entry.Column
+	}
+
+	line, column := position("vars", "stage")
+	assert.Equal(t, []int{8, 10}, []int{line, column})
+
+	// The values merged with `<<` point to the anchor
+	line, _ = position("vars", "region")
+	assert.Equal(t, 3, line)
+	line, _ = position("vars", "tags", "Team")
+	assert.Equal(t, 5, line)
+
+	line, _ = position("vars", "subnets", 0)
+	assert.Equal(t, 10, line)
+}
@@ -5,7 +5,9 @@ import (
 	"os"
 	osexec "os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -36,7 +38,7 @@ func ExecuteTerraformCmd(cmd *cobra.Command, args []string, additionalArgsAndFla
 }
 
 // ExecuteTerraform executes terraform commands
-func ExecuteTerraform(info schema.ConfigAndStacksInfo) error {
+func ExecuteTerraform(info schema.ConfigAndStacksInfo) (err error) {
 	atmosConfig, err := cfg.InitCliConfig(info, true)
 	if err != nil {
 		return err
@@ -46,6 +48,11 @@ func ExecuteTerraform(info schema.ConfigAndStacksInfo) error {
 		return nil
 	}
 
+	// Execute the command for multiple components selected with `--all`, `--components` or `--query`
+	if isTerraformAllCommand(info) {
+		return ExecuteTerraformAll(atmosConfig, info)
+	}
+
 	if info.SubCommand == "version" {
 		return ExecuteShellCommand(atmosConfig,
 			"terraform",
@@ -56,6 +63,14 @@ func ExecuteTerraform(info schema.ConfigAndStacksInfo) error {
 			info.RedirectStdErr)
 	}
 
+	// Record the command in the audit log
+	start := time.Now()
+	auditInfo := info
+	auditInfo.AdditionalArgsAndFlags = slices.Clone(info.AdditionalArgsAndFlags)
+	defer func() {
+		recordAuditEntry(atmosConfig, "terraform", auditInfo, start, err)
+	}()
+
 	// Skip stack processing when cleaning with --force flag to allow cleaning without requiring stack configuration
 	shouldProcessStacks, shouldCheckStack := shouldProcessStacks(&info)
 
@@ -113,6 +128,25 @@ func ExecuteTerraform(info schema.ConfigAndStacksInfo) error {
 		return ErrHTTPBackendWorkspaces
 	}
 
+	// Lock the Terraform workspace in the component folder, so multiple Atmos processes don't execute Terraform commands
+	// in the same workspace from the same checkout at the same time
+	if info.SubCommand != "shell" && !info.DryRun {
+		var lockTimeout time.Duration
+		if info.WorkspaceLockTimeout != "" {
+			lockTimeout, err = time.ParseDuration(info.WorkspaceLockTimeout)
+			if err != nil {
+				return fmt.Errorf("invalid '%s' flag '%s': %w", cfg.WorkspaceLockTimeoutFlag, info.WorkspaceLockTimeout, err)
+			}
+		}
+
+		lockCommand := fmt.Sprintf("atmos terraform %s %s -s %s", info.SubCommand, info.ComponentFromArg, info.Stack)
+		unlock, err := acquireTerraformWorkspaceLock(componentPath, info.TerraformWorkspace, lockTimeout, lockCommand)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+
 	if info.SubCommand == "clean" {
 		err := handleCleanSubCommand(info, componentPath, atmosConfig)
 		if err != nil {
@@ -343,6 +377,10 @@ func ExecuteTerraform(info schema.ConfigAndStacksInfo) error {
 
 	switch info.SubCommand {
 	case "plan":
+		if info.PlanSummaryFormat != "" && !u.SliceContainsString(terraformPlanSummaryFormats, info.PlanSummaryFormat) {
+			return fmt.Errorf("invalid '%s' flag '%s'. Valid values are: %s",
+				cfg.PlanSummaryFormatFlag, info.PlanSummaryFormat, strings.Join(terraformPlanSummaryFormats, ", "))
+		}
 		// Add varfile
 		allArgsAndFlags = append(allArgsAndFlags, []string{varFileFlag, varFile}...)
 		// Add planfile
@@ -475,6 +513,22 @@ func ExecuteTerraform(info schema.ConfigAndStacksInfo) error {
 		return nil
 	}
 
+	// Block `terraform apply` if the plan is denied by the `opa-plan` policies
+	if info.SubCommand == "apply" && !info.DryRun {
+		planFileToApply := planFile
+		if info.PlanFile != "" {
+			planFileToApply = info.PlanFile
+		}
+		err = checkTerraformPlanPolicies(atmosConfig, info, componentPath, planFileToApply)
+		if err != nil {
+			return err
+		}
+	}
+
+	// The exit code 2 of `terraform plan -detailed-exitcode` (the plan has changes).
+	// It's returned after the `opa-plan` policies and the plan summary are processed
+	var planChangesErr error
+
 	// Execute the provided command (except for `terraform workspace` which was executed above)
 	if !(info.SubCommand == "workspace" && info.SubCommand2 == "") {
 		err = ExecuteShellCommand(
@@ -486,6 +540,30 @@ func ExecuteTerraform(info schema.ConfigAndStacksInfo) error {
 			info.DryRun,
 			info.RedirectStdErr,
 		)
+
+		// The command could have changed the state of the component even if it failed
+		if !info.DryRun && isTerraformStateChangingCommand(info.SubCommand) {
+			invalidateTerraformOutputsCache(atmosConfig, info.ComponentFromArg, info.Stack)
+		}
+
+		if info.SubCommand == "plan" && isTerraformPlanChangesExitCode(err) {
+			planChangesErr = err
+		} else if err != nil {
+			return err
+		}
+	}
+
+	// Evaluate the `opa-plan` policies against the planfile
+	if info.SubCommand == "plan" && !info.DryRun {
+		err = processTerraformPlanPolicies(atmosConfig, info, componentPath, getTerraformPlanFileFromArgs(info.AdditionalArgsAndFlags, planFile))
+		if err != nil {
+			return err
+		}
+	}
+
+	// Summarize the changes in the planfile (`terraform plan --summary`)
+	if info.SubCommand == "plan" && (info.PlanSummary || info.PlanSummaryFile != "") && !info.DryRun {
+		err = processTerraformPlanSummary(info, componentPath, getTerraformPlanFileFromArgs(info.AdditionalArgsAndFlags, planFile))
 		if err != nil {
 			return err
 		}
@@ -502,5 +580,5 @@ func ExecuteTerraform(info schema.ConfigAndStacksInfo) error {
 		_ = os.Remove(varFilePath)
 	}
 
-	return nil
+	return planChangesErr
 }
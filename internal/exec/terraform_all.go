@@ -0,0 +1,537 @@
+package exec
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/mitchellh/mapstructure"
+	"github.com/samber/lo"
+
+	cfg "github.com/cloudposse/atmos/pkg/config"
+	l "github.com/cloudposse/atmos/pkg/logger"
+	"github.com/cloudposse/atmos/pkg/schema"
+	"github.com/cloudposse/atmos/pkg/ui/theme"
+	u "github.com/cloudposse/atmos/pkg/utils"
+)
+
+// terraformAllCommands are the Terraform commands that can be executed for multiple components with `--all`, `--affected`, `--components` and `--query`
+var terraformAllCommands = []string{"plan", "apply", "deploy", "destroy"}
+
+const (
+	terraformNodeSucceeded = "succeeded"
+	terraformNodeFailed    = "failed"
+	terraformNodeSkipped   = "skipped"
+)
+
+// terraformNode is an Atmos component in a stack in the dependency graph
+type terraformNode struct {
+	Component     string
+	Stack         string
+	ComponentPath string
+	Context       schema.Context
+	DependsOn     schema.DependsOn
+}
+
+// terraformNodeResult is the result of executing the Terraform command for a node in the dependency graph
+type terraformNodeResult struct {
+	Status   string
+	Error    error
+	Duration time.Duration
+}
+
+// isTerraformAllCommand returns 'true' if the components are selected with the `--all`, `--affected`, `--components` or `--query` flags
+// instead of the component argument
+func isTerraformAllCommand(info schema.ConfigAndStacksInfo) bool {
+	return info.All || info.Affected || len(info.Components) > 0 || (info.Query != "" && info.ComponentFromArg == "")
+}
+
+// ExecuteTerraformAll executes the Terraform command for all the selected components in the stacks.
+// The components are executed in the order of the dependencies defined in `settings.depends_on`,
+// independent components are executed in parallel.
+// For `destroy`, the order is reversed (the dependents are destroyed before their dependencies)
+func ExecuteTerraformAll(atmosConfig schema.AtmosConfiguration, info schema.ConfigAndStacksInfo) error {
+	if !u.SliceContainsString(terraformAllCommands, info.SubCommand) {
+		return fmt.Errorf("the '%s', '%s', '%s' and '%s' flags are supported only for the 'terraform %s' commands",
+			cfg.AllFlag, cfg.AffectedFlag, cfg.ComponentsFlag, cfg.QueryFlag, strings.Join(terraformAllCommands, "|"))
+	}
+
+	if info.ComponentFromArg != "" {
+		return fmt.Errorf("the component argument '%s' can't be used with the '%s', '%s', '%s' and '%s' flags",
+			info.ComponentFromArg, cfg.AllFlag, cfg.AffectedFlag, cfg.ComponentsFlag, cfg.QueryFlag)
+	}
+
+	// Terraform can't prompt for approval when multiple components are executed in parallel
+	if (info.SubCommand == "apply" || info.SubCommand == "destroy") && !info.DryRun &&
+		!u.SliceContainsString(info.AdditionalArgsAndFlags, autoApproveFlag) {
+		return fmt.Errorf("'terraform %s' for multiple components requires the '%s' flag", info.SubCommand, autoApproveFlag)
+	}
+
+	var selected func(stack string, component string) bool
+	var err error
+	if info.Affected {
+		if selected, err = getTerraformAffectedSelector(atmosConfig, info); err != nil {
+			return err
+		}
+	}
+
+	nodes, err := getTerraformAllNodes(atmosConfig, info, selected)
+	if err != nil {
+		return err
+	}
+
+	if len(nodes) == 0 {
+		u.LogInfo("No components found for the provided selectors")
+		return nil
+	}
+
+	dependencies, err := buildTerraformDependencyGraph(nodes)
+	if err != nil {
+		return err
+	}
+
+	if info.SubCommand == "destroy" {
+		dependencies = reverseTerraformDependencyGraph(dependencies)
+	}
+
+	maxConcurrency := info.MaxConcurrency
+	if maxConcurrency < 1 {
+		maxConcurrency = cfg.DefaultTerraformConcurrency
+	}
+
+	u.LogInfo(fmt.Sprintf("Executing 'terraform %s' for %d components with max concurrency %d", info.SubCommand, len(nodes), maxConcurrency))
+
+	// When planning multiple components, the plan summaries are written to a temporary directory and then combined into one `--summary-file`
+	var summaryDir string
+	if info.SubCommand == "plan" && info.PlanSummaryFile != "" && !info.DryRun {
+		if summaryDir, err = os.MkdirTemp("", "atmos-plan-summary"); err != nil {
+			return err
+		}
+		defer os.RemoveAll(summaryDir)
+	}
+
+	results := runTerraformDependencyGraph(nodes, dependencies, maxConcurrency, func(node terraformNode) error {
+		nodeInfo := info
+		nodeInfo.All = false
+		nodeInfo.Affected = false
+		nodeInfo.Components = nil
+		nodeInfo.Query = ""
+		nodeInfo.ComponentFromArg = node.Component
+		nodeInfo.Stack = node.Stack
+		nodeInfo.AdditionalArgsAndFlags = slices.Clone(info.AdditionalArgsAndFlags)
+		if summaryDir != "" {
+			nodeInfo.PlanSummaryFormat = "json"
+			nodeInfo.PlanSummaryFile = terraformPlanSummaryTempFile(summaryDir, node)
+		}
+
+		u.LogInfo(fmt.Sprintf("Executing 'terraform %s' for the component '%s' in the stack '%s'", info.SubCommand, node.Component, node.Stack))
+		return ExecuteTerraform(nodeInfo)
+	})
+
+	u.PrintMessage(formatTerraformAllResults(nodes, results))
+
+	if summaryDir != "" {
+		if err = combineTerraformPlanSummaries(summaryDir, nodes, info.PlanSummaryFile, info.PlanSummaryFormat); err != nil {
+			return err
+		}
+	}
+
+	failed := lo.CountBy(results, func(r terraformNodeResult) bool { return r.Status != terraformNodeSucceeded })
+	if failed > 0 {
+		return fmt.Errorf("'terraform %s' failed or was skipped for %d of %d components", info.SubCommand, failed, len(nodes))
+	}
+
+	return nil
+}
+
+// getTerraformAllNodes returns the Terraform components in the stacks selected with the `--stack`, `--components` and `--query` flags,
+// and the `selected` function if it's provided.
+// Abstract and disabled components are not included
+func getTerraformAllNodes(
+	atmosConfig schema.AtmosConfiguration,
+	info schema.ConfigAndStacksInfo,
+	selected func(stack string, component string) bool,
+) ([]terraformNode, error) {
+	// YAML functions are not processed since they can execute Terraform (e.g. `!terraform.output`) for every component in the stacks
+	stacks, err := ExecuteDescribeStacks(atmosConfig, info.Stack, info.Components, []string{cfg.TerraformSectionName}, nil, false, true, false, false, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes []terraformNode
+
+	for _, stackName := range u.StringKeysFromMap(stacks) {
+		stackSection, ok := stacks[stackName].(map[string]any)
+		if !ok {
+			continue
+		}
+
+		componentsSection, ok := stackSection[cfg.ComponentsSectionName].(map[string]any)
+		if !ok {
+			continue
+		}
+
+		terraformSection, ok := componentsSection[cfg.TerraformSectionName].(map[string]any)
+		if !ok {
+			continue
+		}
+
+		for _, componentName := range u.StringKeysFromMap(terraformSection) {
+			componentSection, ok := terraformSection[componentName].(map[string]any)
+			if !ok {
+				continue
+			}
+
+			// `ExecuteDescribeStacks` also returns the base components of the provided components
+			if len(info.Components) > 0 && !u.SliceContainsString(info.Components, componentName) {
+				continue
+			}
+
+			if selected != nil && !selected(stackName, componentName) {
+				continue
+			}
+
+			metadataSection, _ := componentSection[cfg.MetadataSectionName].(map[string]any)
+			if IsComponentAbstract(metadataSection) || !isComponentEnabled(metadataSection, componentName, atmosConfig) {
+				continue
+			}
+
+			if info.Query != "" {
+				res, err := u.EvaluateYqExpression(&atmosConfig, componentSection, info.Query)
+				if err != nil {
+					return nil, err
+				}
+				if selected, ok := res.(bool); !ok || !selected {
+					continue
+				}
+			}
+
+			varsSection, _ := componentSection[cfg.VarsSectionName].(map[string]any)
+
+			var settings schema.Settings
+			if settingsSection, ok := componentSection[cfg.SettingsSectionName].(map[string]any); ok {
+				if err = mapstructure.Decode(settingsSection, &settings); err != nil {
+					return nil, err
+				}
+			}
+
+			nodes = append(nodes, terraformNode{
+				Component:     componentName,
+				Stack:         stackName,
+				ComponentPath: BuildComponentPath(atmosConfig, componentSection, cfg.TerraformSectionName),
+				Context:       cfg.GetContextFromVars(varsSection),
+				DependsOn:     settings.DependsOn,
+			})
+		}
+	}
+
+	return nodes, nil
+}
+
+// getTerraformAffectedSelector returns a function that selects the Terraform components affected by the changes
+// between the current branch and the target Git reference (the same components as `atmos describe affected` returns).
+// The dependents of the affected components are also selected if `--include-dependents` is specified
+func getTerraformAffectedSelector(atmosConfig schema.AtmosConfiguration, info schema.ConfigAndStacksInfo) (func(stack string, component string) bool, error) {
+	if info.RepoPath != "" && (info.Ref != "" || info.SHA != "" || info.SSHKeyPath != "" || info.SSHKeyPassword != "") {
+		return nil, errors.New("if the '--repo-path' flag is specified, the '--ref', '--sha', '--ssh-key' and '--ssh-key-password' flags can't be used")
+	}
+
+	logger, err := l.NewLoggerFromCliConfig(atmosConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	affected, _, _, _, err := getAffectedComponents(DescribeAffectedCmdArgs{
+		CLIConfig:         atmosConfig,
+		CloneTargetRef:    info.CloneTargetRef,
+		IncludeDependents: info.IncludeDependents,
+		Logger:            logger,
+		Ref:               info.Ref,
+		RepoPath:          info.RepoPath,
+		SHA:               info.SHA,
+		SSHKeyPath:        info.SSHKeyPath,
+		SSHKeyPassword:    info.SSHKeyPassword,
+		Stack:             info.Stack,
+		ProcessTemplates:  true,
+		// YAML functions are not processed since they can execute Terraform (e.g. `!terraform.output`) for every component in the stacks
+		ProcessYamlFunctions: false,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	type stackComponent struct {
+		stack     string
+		component string
+	}
+	affectedComponents := map[stackComponent]bool{}
+
+	var addDependents func(dependents []schema.Dependent)
+	addDependents = func(dependents []schema.Dependent) {
+		for _, d := range dependents {
+			if d.ComponentType == cfg.TerraformSectionName {
+				affectedComponents[stackComponent{d.Stack, d.Component}] = true
+			}
+			addDependents(d.Dependents)
+		}
+	}
+
+	for _, a := range affected {
+		if a.ComponentType == cfg.TerraformSectionName {
+			affectedComponents[stackComponent{a.Stack, a.Component}] = true
+		}
+		addDependents(a.Dependents)
+	}
+
+	u.LogInfo(fmt.Sprintf("Found %d affected Terraform components", len(affectedComponents)))
+
+	return func(stack string, component string) bool {
+		return affectedComponents[stackComponent{stack, component}]
+	}, nil
+}
+
+// buildTerraformDependencyGraph returns the indexes of the dependencies of each node.
+// The dependencies are resolved from `settings.depends_on` the same way as in `atmos describe dependents`:
+// if `namespace`, `tenant`, `environment` or `stage` is not specified in `depends_on`, the dependency must be in the same context as the node.
+// Dependencies that are not selected are ignored
+func buildTerraformDependencyGraph(nodes []terraformNode) ([][]int, error) {
+	dependencies := make([][]int, len(nodes))
+
+	for i, node := range nodes {
+		for _, dependsOn := range node.DependsOn {
+			// `depends_on` can also contain `file` and `folder` dependencies
+			if dependsOn.Component == "" {
+				continue
+			}
+
+			for j, dependency := range nodes {
+				if i == j || dependency.Component != dependsOn.Component {
+					continue
+				}
+
+				if !matchesDependsOnContext(dependsOn.Namespace, node.Context.Namespace, dependency.Context.Namespace) ||
+					!matchesDependsOnContext(dependsOn.Tenant, node.Context.Tenant, dependency.Context.Tenant) ||
+					!matchesDependsOnContext(dependsOn.Environment, node.Context.Environment, dependency.Context.Environment) ||
+					!matchesDependsOnContext(dependsOn.Stage, node.Context.Stage, dependency.Context.Stage) {
+					continue
+				}
+
+				if !slices.Contains(dependencies[i], j) {
+					dependencies[i] = append(dependencies[i], j)
+				}
+			}
+		}
+
+		sort.Ints(dependencies[i])
+	}
+
+	if cycle := findTerraformDependencyCycle(nodes, dependencies); cycle != "" {
+		return nil, fmt.Errorf("circular dependency in 'settings.depends_on': %s", cycle)
+	}
+
+	return dependencies, nil
+}
+
+// matchesDependsOnContext checks if the dependency's context value matches the value specified in `depends_on`,
+// or the node's context value if it's not specified
+func matchesDependsOnContext(dependsOnValue string, nodeValue string, dependencyValue string) bool {
+	if dependsOnValue != "" {
+		return dependencyValue == dependsOnValue
+	}
+	return dependencyValue == nodeValue
+}
+
+// findTerraformDependencyCycle returns the description of a circular dependency in the graph, or an empty string if there are no cycles
+func findTerraformDependencyCycle(nodes []terraformNode, dependencies [][]int) string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make([]int, len(nodes))
+	var path []int
+
+	var visit func(i int) []int
+	visit = func(i int) []int {
+		state[i] = visiting
+		path = append(path, i)
+
+		for _, j := range dependencies[i] {
+			if state[j] == visiting {
+				return append(path[slices.Index(path, j):], j)
+			}
+			if state[j] == unvisited {
+				if cycle := visit(j); cycle != nil {
+					return cycle
+				}
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[i] = visited
+		return nil
+	}
+
+	for i := range nodes {
+		if state[i] != unvisited {
+			continue
+		}
+		if cycle := visit(i); cycle != nil {
+			names := lo.Map(cycle, func(j int, _ int) string {
+				return fmt.Sprintf("'%s' in '%s'", nodes[j].Component, nodes[j].Stack)
+			})
+			return strings.Join(names, " -> ")
+		}
+	}
+
+	return ""
+}
+
+// reverseTerraformDependencyGraph reverses the edges of the dependency graph, so the dependents are executed before their dependencies
+func reverseTerraformDependencyGraph(dependencies [][]int) [][]int {
+	reversed := make([][]int, len(dependencies))
+	for i, deps := range dependencies {
+		for _, j := range deps {
+			reversed[j] = append(reversed[j], i)
+		}
+	}
+	return reversed
+}
+
+// runTerraformDependencyGraph executes the nodes of the dependency graph.
+// A node is executed after all its dependencies have succeeded, and up to `maxConcurrency` nodes are executed in parallel.
+// Nodes with the same component folder are never executed in parallel since Terraform writes the backend config,
+// the varfiles and the `.terraform` folder into the component folder.
+// If a node fails, all nodes that depend on it (directly or transitively) are skipped
+func runTerraformDependencyGraph(
+	nodes []terraformNode,
+	dependencies [][]int,
+	maxConcurrency int,
+	run func(node terraformNode) error,
+) []terraformNodeResult {
+	results := make([]terraformNodeResult, len(nodes))
+	pending := make([]int, len(nodes))
+	dependents := make([][]int, len(nodes))
+	folderLocks := map[string]*sync.Mutex{}
+
+	var ready []int
+	for i, deps := range dependencies {
+		pending[i] = len(deps)
+		for _, j := range deps {
+			dependents[j] = append(dependents[j], i)
+		}
+		if len(deps) == 0 {
+			ready = append(ready, i)
+		}
+		if _, ok := folderLocks[nodes[i].ComponentPath]; !ok {
+			folderLocks[nodes[i].ComponentPath] = &sync.Mutex{}
+		}
+	}
+
+	var skip func(i int)
+	skip = func(i int) {
+		for _, k := range dependents[i] {
+			if results[k].Status != "" {
+				continue
+			}
+			results[k] = terraformNodeResult{
+				Status: terraformNodeSkipped,
+				Error:  fmt.Errorf("dependency '%s' in the stack '%s' did not succeed", nodes[i].Component, nodes[i].Stack),
+			}
+			skip(k)
+		}
+	}
+
+	done := make(chan int)
+	running := 0
+
+	for running > 0 || len(ready) > 0 {
+		for len(ready) > 0 && running < maxConcurrency {
+			i := ready[0]
+			ready = ready[1:]
+			running++
+
+			go func(i int) {
+				lock := folderLocks[nodes[i].ComponentPath]
+				lock.Lock()
+				start := time.Now()
+				err := run(nodes[i])
+				duration := time.Since(start)
+				lock.Unlock()
+
+				result := terraformNodeResult{Status: terraformNodeSucceeded, Duration: duration}
+				if err != nil {
+					result = terraformNodeResult{Status: terraformNodeFailed, Error: err, Duration: duration}
+				}
+				results[i] = result
+				done <- i
+			}(i)
+		}
+
+		i := <-done
+		running--
+
+		if results[i].Status != terraformNodeSucceeded {
+			skip(i)
+			continue
+		}
+
+		for _, k := range dependents[i] {
+			pending[k]--
+			if pending[k] == 0 && results[k].Status == "" {
+				ready = append(ready, k)
+			}
+		}
+	}
+
+	return results
+}
+
+// formatTerraformAllResults returns a table with the status of each component
+func formatTerraformAllResults(nodes []terraformNode, results []terraformNodeResult) string {
+	rows := make([][]string, 0, len(nodes))
+
+	for i, node := range nodes {
+		result := results[i]
+
+		status := result.Status
+		switch result.Status {
+		case terraformNodeSucceeded:
+			status = theme.Styles.Checkmark.String() + " " + status
+		case terraformNodeFailed:
+			status = theme.Styles.XMark.String() + " " + status
+		}
+
+		details := ""
+		if result.Error != nil {
+			// Show only the first line of multi-line errors (e.g. validation errors) in the table
+			details, _, _ = strings.Cut(strings.TrimSpace(result.Error.Error()), "\n")
+		}
+
+		duration := ""
+		if result.Status != terraformNodeSkipped {
+			duration = result.Duration.Round(time.Second).String()
+		}
+
+		rows = append(rows, []string{node.Stack, node.Component, status, duration, details})
+	}
+
+	t := table.New().
+		Border(lipgloss.RoundedBorder()).
+		BorderStyle(lipgloss.NewStyle().Foreground(lipgloss.Color(theme.ColorBorder))).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			return lipgloss.NewStyle().PaddingLeft(1).PaddingRight(1)
+		}).
+		Headers("Stack", "Component", "Status", "Duration", "Details").
+		Rows(rows...)
+
+	return t.String()
+}
@@ -0,0 +1,148 @@
+package exec
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudposse/atmos/pkg/schema"
+)
+
+func TestBuildTerraformDependencyGraph(t *testing.T) {
+	dev := schema.Context{Tenant: "plat", Environment: "ue2", Stage: "dev"}
+	prod := schema.Context{Tenant: "plat", Environment: "ue2", Stage: "prod"}
+
+	nodes := []terraformNode{
+		{Component: "vpc", Stack: "plat-ue2-dev", Context: dev},
+		{Component: "vpc", Stack: "plat-ue2-prod", Context: prod},
+		{
+			Component: "eks", Stack: "plat-ue2-dev", Context: dev,
+			DependsOn: schema.DependsOn{
+				1: {Component: "vpc"},
+				2: {File: "configs/eks.json"},
+			},
+		},
+		{
+			Component: "dns", Stack: "plat-ue2-dev", Context: dev,
+			// Depends on the component in another stage
+			DependsOn: schema.DependsOn{1: {Component: "vpc", Stage: "prod"}},
+		},
+	}
+
+	dependencies, err := buildTerraformDependencyGraph(nodes)
+	require.NoError(t, err)
+	assert.Equal(t, [][]int{nil, nil, {0}, {1}}, dependencies)
+
+	assert.Equal(t, [][]int{{2}, {3}, nil, nil}, reverseTerraformDependencyGraph(dependencies))
+
+	// Circular dependencies
+	nodes[0].DependsOn = schema.DependsOn{1: {Component: "eks"}}
+	_, err = buildTerraformDependencyGraph(nodes)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "circular dependency")
+}
+
+func TestRunTerraformDependencyGraph(t *testing.T) {
+	nodes := []terraformNode{
+		{Component: "vpc", Stack: "dev", ComponentPath: "vpc"},
+		{Component: "eks", Stack: "dev", ComponentPath: "eks"},
+		{Component: "app", Stack: "dev", ComponentPath: "app"},
+		{Component: "dns", Stack: "dev", ComponentPath: "dns"},
+		{Component: "vpc", Stack: "prod", ComponentPath: "vpc"},
+	}
+	// eks -> vpc, app -> eks, dns has no dependencies
+	dependencies := [][]int{nil, {0}, {1}, nil, nil}
+
+	var mu sync.Mutex
+	var order []string
+
+	run := func(failed string) func(node terraformNode) error {
+		return func(node terraformNode) error {
+			mu.Lock()
+			order = append(order, node.Component+"/"+node.Stack)
+			mu.Unlock()
+			if node.Component == failed {
+				return errors.New("exit status 1")
+			}
+			return nil
+		}
+	}
+
+	results := runTerraformDependencyGraph(nodes, dependencies, 2, run(""))
+	for _, r := range results {
+		assert.Equal(t, terraformNodeSucceeded, r.Status)
+	}
+	assert.Less(t, slices.Index(order, "vpc/dev"), slices.Index(order, "eks/dev"))
+	assert.Less(t, slices.Index(order, "eks/dev"), slices.Index(order, "app/dev"))
+
+	// Dependents of the failed component are skipped, independent components are executed
+	order = nil
+	results = runTerraformDependencyGraph(nodes, dependencies, 2, run("eks"))
+	assert.Equal(t, terraformNodeSucceeded, results[0].Status)
+	assert.Equal(t, terraformNodeFailed, results[1].Status)
+	assert.Equal(t, terraformNodeSkipped, results[2].Status)
+	assert.Equal(t, terraformNodeSucceeded, results[3].Status)
+	assert.Equal(t, terraformNodeSucceeded, results[4].Status)
+	assert.NotContains(t, order, "app/dev")
+
+	// Destroy in the reverse order
+	order = nil
+	results = runTerraformDependencyGraph(nodes, reverseTerraformDependencyGraph(dependencies), 4, run(""))
+	for _, r := range results {
+		assert.Equal(t, terraformNodeSucceeded, r.Status)
+	}
+	assert.Less(t, slices.Index(order, "app/dev"), slices.Index(order, "eks/dev"))
+	assert.Less(t, slices.Index(order, "eks/dev"), slices.Index(order, "vpc/dev"))
+}
+
+func TestProcessArgsAndFlagsMultiComponent(t *testing.T) {
+	info, err := processArgsAndFlags("terraform", []string{
+		"plan", "--all", "--dry-run", "--components", "vpc, eks", "--max-concurrency=8", "-refresh=false",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "plan", info.SubCommand)
+	assert.Equal(t, "", info.ComponentFromArg)
+	assert.True(t, info.All)
+	assert.Equal(t, []string{"vpc", "eks"}, info.Components)
+	assert.Equal(t, 8, info.MaxConcurrency)
+	assert.Equal(t, []string{"-refresh=false"}, info.AdditionalArgsAndFlags)
+
+	info, err = processArgsAndFlags("terraform", []string{
+		"apply", "--affected", "--ref", "refs/heads/main", "--ssh-key-password=secret", "--include-dependents", "-auto-approve",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "apply", info.SubCommand)
+	assert.True(t, info.Affected)
+	assert.True(t, info.IncludeDependents)
+	assert.False(t, info.CloneTargetRef)
+	assert.Equal(t, "refs/heads/main", info.Ref)
+	assert.Equal(t, "secret", info.SSHKeyPassword)
+	assert.Equal(t, "", info.SSHKeyPath)
+	assert.Equal(t, []string{"-auto-approve"}, info.AdditionalArgsAndFlags)
+
+	_, err = processArgsAndFlags("terraform", []string{"plan", "--all", "--max-concurrency", "0"})
+	assert.Error(t, err)
+
+	// A component argument can't be combined with the flags that select the components
+	_, err = processArgsAndFlags("terraform", []string{"plan", "vpc", "--query", ".vars.enabled", "-s", "dev"})
+	assert.ErrorContains(t, err, "the component argument 'vpc' can't be used")
+
+	// The other commands pass the flags to the underlying tool
+	info, err = processArgsAndFlags("terraform", []string{"output", "vpc", "-s", "dev", "--ref", "main", "--summary", "--all"})
+	require.NoError(t, err)
+	assert.Equal(t, "output", info.SubCommand)
+	assert.Equal(t, "vpc", info.ComponentFromArg)
+	assert.False(t, info.All)
+	assert.Equal(t, "", info.Ref)
+	assert.False(t, info.PlanSummary)
+	assert.Equal(t, []string{"--ref", "main", "--summary", "--all"}, info.AdditionalArgsAndFlags)
+
+	info, err = processArgsAndFlags("helmfile", []string{"diff", "echo-server", "-s", "dev", "--components", "a"})
+	require.NoError(t, err)
+	assert.Nil(t, info.Components)
+	assert.Equal(t, []string{"--components", "a"}, info.AdditionalArgsAndFlags)
+}
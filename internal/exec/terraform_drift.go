@@ -0,0 +1,401 @@
+package exec
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	osexec "os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/spf13/cobra"
+
+	cfg "github.com/cloudposse/atmos/pkg/config"
+	"github.com/cloudposse/atmos/pkg/schema"
+	"github.com/cloudposse/atmos/pkg/ui/theme"
+	u "github.com/cloudposse/atmos/pkg/utils"
+)
+
+const (
+	TerraformDriftStatusInSync  = "in-sync"
+	TerraformDriftStatusDrifted = "drifted"
+	TerraformDriftStatusErrored = "errored"
+
+	// terraformDriftStoreKey is the key under which the drift status of each component is written to the store
+	terraformDriftStoreKey = "drift"
+
+	// terraformPlanDriftExitCode is the exit code of `terraform plan -detailed-exitcode` when the plan has changes
+	terraformPlanDriftExitCode = 2
+)
+
+// terraformDriftFormats are the supported formats of the drift report
+var terraformDriftFormats = []string{"table", "json", "markdown", "junit"}
+
+// TerraformDriftResult is the drift status of a component in a stack
+type TerraformDriftResult struct {
+	Stack     string  `yaml:"stack" json:"stack"`
+	Component string  `yaml:"component" json:"component"`
+	Status    string  `yaml:"status" json:"status"`
+	Error     string  `yaml:"error,omitempty" json:"error,omitempty"`
+	Duration  float64 `yaml:"duration_seconds" json:"duration_seconds"`
+}
+
+// TerraformDriftReport is the result of `atmos terraform drift`
+type TerraformDriftReport struct {
+	CheckedAt time.Time              `yaml:"checked_at" json:"checked_at"`
+	InSync    int                    `yaml:"in_sync" json:"in_sync"`
+	Drifted   int                    `yaml:"drifted" json:"drifted"`
+	Errored   int                    `yaml:"errored" json:"errored"`
+	Results   []TerraformDriftResult `yaml:"results" json:"results"`
+}
+
+// terraformDriftStoreValue is the value written to the store for each component
+type terraformDriftStoreValue struct {
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// ExecuteTerraformDriftCmd executes `atmos terraform drift` command
+func ExecuteTerraformDriftCmd(cmd *cobra.Command, args []string) error {
+	info, err := ProcessCommandLineArgs("terraform", cmd, args, nil)
+	if err != nil {
+		return err
+	}
+
+	atmosConfig, err := cfg.InitCliConfig(info, true)
+	if err != nil {
+		return err
+	}
+
+	flags := cmd.Flags()
+
+	componentsCsv, err := flags.GetString("components")
+	if err != nil {
+		return err
+	}
+	info.Components = splitComponentsFlag(componentsCsv)
+
+	if info.Query, err = flags.GetString("query"); err != nil {
+		return err
+	}
+
+	if info.MaxConcurrency, err = flags.GetInt("max-concurrency"); err != nil {
+		return err
+	}
+
+	if info.DryRun, err = flags.GetBool("dry-run"); err != nil {
+		return err
+	}
+
+	format, err := flags.GetString("format")
+	if err != nil {
+		return err
+	}
+	if format == "" {
+		format = "table"
+	}
+	if !u.SliceContainsString(terraformDriftFormats, format) {
+		return fmt.Errorf("invalid '--format' flag '%s'. Valid values are %s", format, strings.Join(terraformDriftFormats, ", "))
+	}
+
+	file, err := flags.GetString("file")
+	if err != nil {
+		return err
+	}
+
+	storeName, err := flags.GetString("store")
+	if err != nil {
+		return err
+	}
+	if storeName != "" && atmosConfig.Stores[storeName] == nil {
+		return fmt.Errorf("store '%s' is not configured in the 'stores' section in 'atmos.yaml'", storeName)
+	}
+
+	failOnDrift, err := flags.GetBool("fail-on-drift")
+	if err != nil {
+		return err
+	}
+
+	report, err := ExecuteTerraformDrift(atmosConfig, info)
+	if err != nil {
+		return err
+	}
+
+	output, err := formatTerraformDriftReport(report, format)
+	if err != nil {
+		return err
+	}
+
+	if file == "" {
+		u.PrintMessage(output)
+	} else if err = os.WriteFile(file, []byte(output), 0o644); err != nil {
+		return err
+	}
+
+	if storeName != "" {
+		if err = writeTerraformDriftReportToStore(atmosConfig, storeName, report); err != nil {
+			return err
+		}
+	}
+
+	if report.Errored > 0 {
+		return fmt.Errorf("drift detection failed for %d of %d components", report.Errored, len(report.Results))
+	}
+
+	if failOnDrift && report.Drifted > 0 {
+		return fmt.Errorf("drift detected in %d of %d components", report.Drifted, len(report.Results))
+	}
+
+	return nil
+}
+
+// ExecuteTerraformDrift executes `terraform plan -detailed-exitcode` in parallel for all enabled, non-abstract Terraform components
+// in the selected stacks, and classifies each component as in-sync, drifted or errored
+func ExecuteTerraformDrift(atmosConfig schema.AtmosConfiguration, info schema.ConfigAndStacksInfo) (TerraformDriftReport, error) {
+	report := TerraformDriftReport{
+		CheckedAt: time.Now().UTC(),
+		Results:   []TerraformDriftResult{},
+	}
+
+	nodes, err := getTerraformAllNodes(atmosConfig, info, nil)
+	if err != nil {
+		return report, err
+	}
+
+	maxConcurrency := info.MaxConcurrency
+	if maxConcurrency < 1 {
+		maxConcurrency = cfg.DefaultTerraformConcurrency
+	}
+
+	u.LogInfo(fmt.Sprintf("Checking drift of %d components with max concurrency %d", len(nodes), maxConcurrency))
+
+	// The components don't depend on each other since `plan` doesn't change the infrastructure
+	results := runTerraformDependencyGraph(nodes, make([][]int, len(nodes)), maxConcurrency, func(node terraformNode) error {
+		nodeInfo := info
+		nodeInfo.SubCommand = "plan"
+		nodeInfo.Components = nil
+		nodeInfo.Query = ""
+		nodeInfo.ComponentFromArg = node.Component
+		nodeInfo.Stack = node.Stack
+		nodeInfo.AdditionalArgsAndFlags = append(slices.Clone(info.AdditionalArgsAndFlags), "-detailed-exitcode", "-input=false")
+
+		u.LogInfo(fmt.Sprintf("Checking drift of the component '%s' in the stack '%s'", node.Component, node.Stack))
+		return ExecuteTerraform(nodeInfo)
+	})
+
+	for i, node := range nodes {
+		result := TerraformDriftResult{
+			Stack:     node.Stack,
+			Component: node.Component,
+			Duration:  results[i].Duration.Round(time.Millisecond).Seconds(),
+		}
+		result.Status, result.Error = classifyTerraformDrift(results[i].Error)
+
+		switch result.Status {
+		case TerraformDriftStatusInSync:
+			report.InSync++
+		case TerraformDriftStatusDrifted:
+			report.Drifted++
+		default:
+			report.Errored++
+		}
+
+		report.Results = append(report.Results, result)
+	}
+
+	return report, nil
+}
+
+// classifyTerraformDrift returns the drift status from the result of `terraform plan -detailed-exitcode`:
+// exit code 0 means no changes, exit code 2 means the plan has changes, any other error means the plan failed
+func classifyTerraformDrift(err error) (string, string) {
+	if err == nil {
+		return TerraformDriftStatusInSync, ""
+	}
+
+	if isTerraformPlanChangesExitCode(err) {
+		return TerraformDriftStatusDrifted, ""
+	}
+
+	return TerraformDriftStatusErrored, strings.TrimSpace(err.Error())
+}
+
+// isTerraformPlanChangesExitCode returns `true` if the error is the exit code 2 of `terraform plan -detailed-exitcode`,
+// which means that the plan succeeded and has changes
+func isTerraformPlanChangesExitCode(err error) bool {
+	var exitErr *osexec.ExitError
+	return errors.As(err, &exitErr) && exitErr.ExitCode() == terraformPlanDriftExitCode
+}
+
+// formatTerraformDriftReport formats the drift report as a table, JSON, Markdown or JUnit XML
+func formatTerraformDriftReport(report TerraformDriftReport, format string) (string, error) {
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	case "markdown":
+		return formatTerraformDriftReportMarkdown(report), nil
+	case "junit":
+		return formatTerraformDriftReportJUnit(report)
+	default:
+		return formatTerraformDriftReportTable(report), nil
+	}
+}
+
+// terraformDriftSummary returns the one-line summary of the drift report
+func terraformDriftSummary(report TerraformDriftReport) string {
+	return fmt.Sprintf("%d in sync, %d drifted, %d errored", report.InSync, report.Drifted, report.Errored)
+}
+
+func formatTerraformDriftReportTable(report TerraformDriftReport) string {
+	rows := make([][]string, 0, len(report.Results))
+
+	for _, r := range report.Results {
+		status := r.Status
+		switch r.Status {
+		case TerraformDriftStatusInSync:
+			status = theme.Styles.Checkmark.String() + " " + status
+		case TerraformDriftStatusDrifted:
+			status = lipgloss.NewStyle().Foreground(lipgloss.Color(theme.ColorPink)).Render("~") + " " + status
+		case TerraformDriftStatusErrored:
+			status = theme.Styles.XMark.String() + " " + status
+		}
+
+		details, _, _ := strings.Cut(r.Error, "\n")
+		rows = append(rows, []string{r.Stack, r.Component, status, details})
+	}
+
+	t := table.New().
+		Border(lipgloss.RoundedBorder()).
+		BorderStyle(lipgloss.NewStyle().Foreground(lipgloss.Color(theme.ColorBorder))).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			return lipgloss.NewStyle().PaddingLeft(1).PaddingRight(1)
+		}).
+		Headers("Stack", "Component", "Status", "Details").
+		Rows(rows...)
+
+	return t.String() + "\n" + terraformDriftSummary(report)
+}
+
+func formatTerraformDriftReportMarkdown(report TerraformDriftReport) string {
+	var sb strings.Builder
+
+	sb.WriteString("## Drift Detection\n\n")
+	sb.WriteString(fmt.Sprintf("Checked at %s: **%s**\n\n", report.CheckedAt.Format(time.RFC3339), terraformDriftSummary(report)))
+	sb.WriteString("| Stack | Component | Status | Details |\n")
+	sb.WriteString("| :---- | :-------- | :----- | :------ |\n")
+
+	for _, r := range report.Results {
+		details, _, _ := strings.Cut(r.Error, "\n")
+		details = strings.ReplaceAll(details, "|", "\\|")
+		sb.WriteString(fmt.Sprintf("| `%s` | `%s` | %s | %s |\n", r.Stack, r.Component, r.Status, details))
+	}
+
+	return sb.String()
+}
+
+// JUnit XML report. Each component is a test case, drifted components are failures and errored components are errors
+// https://github.com/testmoapp/junitxml
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      float64       `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func formatTerraformDriftReportJUnit(report TerraformDriftReport) (string, error) {
+	suites := map[string]*junitTestSuite{}
+	var stacks []string
+
+	for _, r := range report.Results {
+		suite, ok := suites[r.Stack]
+		if !ok {
+			suite = &junitTestSuite{Name: r.Stack, Timestamp: report.CheckedAt.Format(time.RFC3339)}
+			suites[r.Stack] = suite
+			stacks = append(stacks, r.Stack)
+		}
+
+		testCase := junitTestCase{Name: r.Component, Classname: r.Stack, Time: r.Duration}
+		switch r.Status {
+		case TerraformDriftStatusDrifted:
+			testCase.Failure = &junitMessage{Message: "drift detected"}
+			suite.Failures++
+		case TerraformDriftStatusErrored:
+			testCase.Error = &junitMessage{Message: "terraform plan failed", Text: r.Error}
+			suite.Errors++
+		}
+
+		suite.Tests++
+		suite.Cases = append(suite.Cases, testCase)
+	}
+
+	result := junitTestSuites{
+		Name:     "atmos terraform drift",
+		Tests:    len(report.Results),
+		Failures: report.Drifted,
+		Errors:   report.Errored,
+	}
+	for _, stack := range stacks {
+		result.Suites = append(result.Suites, *suites[stack])
+	}
+
+	data, err := xml.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return xml.Header + string(data), nil
+}
+
+// writeTerraformDriftReportToStore writes the drift status of each component to the store under the `drift` key
+func writeTerraformDriftReportToStore(atmosConfig schema.AtmosConfiguration, storeName string, report TerraformDriftReport) error {
+	store := atmosConfig.Stores[storeName]
+
+	for _, r := range report.Results {
+		value := terraformDriftStoreValue{
+			Status:    r.Status,
+			Error:     r.Error,
+			CheckedAt: report.CheckedAt,
+		}
+		if err := store.Set(r.Stack, r.Component, terraformDriftStoreKey, value); err != nil {
+			return fmt.Errorf("failed to write the drift status of the component '%s' in the stack '%s' to the store '%s': %w",
+				r.Component, r.Stack, storeName, err)
+		}
+	}
+
+	u.LogInfo(fmt.Sprintf("Wrote the drift status of %d components to the store '%s'", len(report.Results), storeName))
+	return nil
+}
@@ -0,0 +1,73 @@
+package exec
+
+import (
+	"encoding/json"
+	"errors"
+	osexec "os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyTerraformDrift(t *testing.T) {
+	status, msg := classifyTerraformDrift(nil)
+	assert.Equal(t, TerraformDriftStatusInSync, status)
+	assert.Empty(t, msg)
+
+	// `terraform plan -detailed-exitcode` exits with 2 when there are changes
+	err := osexec.Command("sh", "-c", "exit 2").Run()
+	status, msg = classifyTerraformDrift(err)
+	assert.Equal(t, TerraformDriftStatusDrifted, status)
+	assert.Empty(t, msg)
+
+	err = osexec.Command("sh", "-c", "exit 1").Run()
+	status, msg = classifyTerraformDrift(err)
+	assert.Equal(t, TerraformDriftStatusErrored, status)
+	assert.Equal(t, "exit status 1", msg)
+
+	status, msg = classifyTerraformDrift(errors.New("component 'vpc' does not exist"))
+	assert.Equal(t, TerraformDriftStatusErrored, status)
+	assert.Equal(t, "component 'vpc' does not exist", msg)
+}
+
+func TestIsTerraformPlanChangesExitCode(t *testing.T) {
+	assert.False(t, isTerraformPlanChangesExitCode(nil))
+	assert.True(t, isTerraformPlanChangesExitCode(osexec.Command("sh", "-c", "exit 2").Run()))
+	assert.False(t, isTerraformPlanChangesExitCode(osexec.Command("sh", "-c", "exit 1").Run()))
+	assert.False(t, isTerraformPlanChangesExitCode(errors.New("exit status 2")))
+}
+
+func TestFormatTerraformDriftReport(t *testing.T) {
+	report := TerraformDriftReport{
+		CheckedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		InSync:    1,
+		Drifted:   1,
+		Errored:   1,
+		Results: []TerraformDriftResult{
+			{Stack: "plat-ue2-dev", Component: "vpc", Status: TerraformDriftStatusInSync, Duration: 1.5},
+			{Stack: "plat-ue2-dev", Component: "eks", Status: TerraformDriftStatusDrifted, Duration: 2},
+			{Stack: "plat-ue2-prod", Component: "vpc", Status: TerraformDriftStatusErrored, Error: "exit status 1"},
+		},
+	}
+
+	output, err := formatTerraformDriftReport(report, "json")
+	require.NoError(t, err)
+	var decoded TerraformDriftReport
+	require.NoError(t, json.Unmarshal([]byte(output), &decoded))
+	assert.Equal(t, report, decoded)
+
+	output, err = formatTerraformDriftReport(report, "markdown")
+	require.NoError(t, err)
+	assert.Contains(t, output, "**1 in sync, 1 drifted, 1 errored**")
+	assert.Contains(t, output, "| `plat-ue2-dev` | `eks` | drifted |  |")
+	assert.Contains(t, output, "| `plat-ue2-prod` | `vpc` | errored | exit status 1 |")
+
+	output, err = formatTerraformDriftReport(report, "junit")
+	require.NoError(t, err)
+	assert.Contains(t, output, `<testsuites name="atmos terraform drift" tests="3" failures="1" errors="1">`)
+	assert.Contains(t, output, `<testsuite name="plat-ue2-dev" tests="2" failures="1" errors="0" timestamp="2025-01-02T03:04:05Z">`)
+	assert.Contains(t, output, `<failure message="drift detected"></failure>`)
+	assert.Contains(t, output, `<error message="terraform plan failed">exit status 1</error>`)
+}
@@ -0,0 +1,146 @@
+package exec
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+
+	cfg "github.com/cloudposse/atmos/pkg/config"
+	u "github.com/cloudposse/atmos/pkg/utils"
+)
+
+// terraformWorkspaceLockRetryDelay is the delay between the attempts to acquire the workspace lock when `--workspace-lock-timeout` is specified
+const terraformWorkspaceLockRetryDelay = 500 * time.Millisecond
+
+// terraformWorkspaceLockInfo describes the Atmos process holding the lock of a Terraform workspace in a component folder
+type terraformWorkspaceLockInfo struct {
+	PID     int       `json:"pid"`
+	User    string    `json:"user"`
+	Host    string    `json:"host"`
+	Command string    `json:"command"`
+	Created time.Time `json:"created"`
+}
+
+// getTerraformWorkspaceLockFile returns the lock file of the Terraform workspace in the component folder.
+// The locks are kept in `$XDG_CACHE_HOME/atmos/terraform-locks` (or in the user cache directory if `XDG_CACHE_HOME` is not set),
+// in a subdirectory per component folder, so they are not left in the (vendored) component folder
+func getTerraformWorkspaceLockFile(componentPath string, workspace string) (string, error) {
+	if workspace == "" {
+		workspace = defaultTerraformWorkspace
+	}
+	workspace = strings.NewReplacer("/", "_", "\\", "_").Replace(workspace)
+
+	componentPath, err := filepath.Abs(componentPath)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(componentPath))
+
+	lockDir, err := cfg.GetAtmosCacheDir("terraform-locks", hex.EncodeToString(sum[:8]))
+	if err != nil {
+		return "", err
+	}
+	if err = os.MkdirAll(lockDir, os.ModePerm); err != nil {
+		return "", fmt.Errorf("failed to create the lock directory '%s': %w", lockDir, err)
+	}
+
+	return filepath.Join(lockDir, workspace+".lock"), nil
+}
+
+// acquireTerraformWorkspaceLock takes an advisory file lock of the Terraform workspace in the component folder,
+// so multiple Atmos processes don't execute Terraform commands in the same workspace from the same checkout at the same time.
+// If the lock is held by another process, it waits for up to `timeout` for the lock to be released.
+// It returns the function that releases the lock
+func acquireTerraformWorkspaceLock(componentPath string, workspace string, timeout time.Duration, command string) (func(), error) {
+	lockFile, err := getTerraformWorkspaceLockFile(componentPath, workspace)
+	if err != nil {
+		return nil, err
+	}
+	lock := flock.New(lockFile)
+
+	var locked bool
+
+	if timeout > 0 {
+		u.LogDebug(fmt.Sprintf("Waiting for up to %s for the lock '%s'", timeout, lockFile))
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		locked, err = lock.TryLockContext(ctx, terraformWorkspaceLockRetryDelay)
+		if err != nil && ctx.Err() != nil {
+			err = nil
+		}
+	} else {
+		locked, err = lock.TryLock()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error acquiring the lock '%s': %w", lockFile, err)
+	}
+
+	if !locked {
+		return nil, terraformWorkspaceLockedError(lockFile, componentPath, workspace, timeout)
+	}
+
+	info := terraformWorkspaceLockInfo{
+		PID:     os.Getpid(),
+		User:    getCurrentUserName(),
+		Command: command,
+		Created: time.Now().UTC(),
+	}
+	info.Host, _ = os.Hostname()
+
+	// The holder is written to a separate file since the locked file can't be written on all platforms
+	if err = u.WriteToFileAsJSON(lockFile+".info", info, 0o644); err != nil {
+		u.LogWarning(fmt.Sprintf("Failed to write the lock holder info: %v", err))
+	}
+
+	u.LogTrace(fmt.Sprintf("Acquired the lock '%s'", lockFile))
+
+	return func() {
+		_ = os.Remove(lockFile + ".info")
+		if err := lock.Unlock(); err != nil {
+			u.LogWarning(fmt.Sprintf("Failed to release the lock '%s': %v", lockFile, err))
+			return
+		}
+		u.LogTrace(fmt.Sprintf("Released the lock '%s'", lockFile))
+	}, nil
+}
+
+// terraformWorkspaceLockedError returns the error describing the process holding the lock
+func terraformWorkspaceLockedError(lockFile string, componentPath string, workspace string, timeout time.Duration) error {
+	holder := "another Atmos process"
+
+	if data, err := os.ReadFile(lockFile + ".info"); err == nil {
+		var info terraformWorkspaceLockInfo
+		if err = json.Unmarshal(data, &info); err == nil {
+			holder = fmt.Sprintf("PID %d (user '%s' on '%s') running '%s' since %s",
+				info.PID, info.User, info.Host, info.Command, info.Created.Local().Format(time.RFC3339))
+		}
+	}
+
+	if timeout > 0 {
+		return fmt.Errorf("timed out after %s waiting for the lock of the Terraform workspace '%s' in '%s'. The lock is held by %s",
+			timeout, workspace, componentPath, holder)
+	}
+
+	return fmt.Errorf("the Terraform workspace '%s' in '%s' is locked by %s. Use the '--workspace-lock-timeout' flag to wait for the lock",
+		workspace, componentPath, holder)
+}
+
+// getCurrentUserName returns the name of the current OS user
+func getCurrentUserName() string {
+	if current, err := user.Current(); err == nil && current.Username != "" {
+		return current.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return os.Getenv("USERNAME")
+}
@@ -0,0 +1,89 @@
+package exec
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcquireTerraformWorkspaceLock(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	componentPath := t.TempDir()
+
+	unlock, err := acquireTerraformWorkspaceLock(componentPath, "plat-ue2-dev", 0, "atmos terraform apply vpc -s plat-ue2-dev")
+	require.NoError(t, err)
+
+	lockFile, err := getTerraformWorkspaceLockFile(componentPath, "plat-ue2-dev")
+	require.NoError(t, err)
+	assert.Equal(t, "plat-ue2-dev.lock", filepath.Base(lockFile))
+	assert.FileExists(t, lockFile+".info")
+
+	// The locks are not kept in the component folder
+	entries, err := os.ReadDir(componentPath)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	// The lock is contended. The error shows the lock holder
+	_, err = acquireTerraformWorkspaceLock(componentPath, "plat-ue2-dev", 0, "atmos terraform plan vpc -s plat-ue2-dev")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), fmt.Sprintf("PID %d", os.Getpid()))
+	assert.Contains(t, err.Error(), fmt.Sprintf("user '%s'", getCurrentUserName()))
+	assert.Contains(t, err.Error(), "running 'atmos terraform apply vpc -s plat-ue2-dev'")
+	assert.Contains(t, err.Error(), fmt.Sprintf("in '%s'", componentPath))
+	assert.Contains(t, err.Error(), "--workspace-lock-timeout")
+
+	start := time.Now()
+	_, err = acquireTerraformWorkspaceLock(componentPath, "plat-ue2-dev", 200*time.Millisecond, "atmos terraform plan vpc -s plat-ue2-dev")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timed out after 200ms")
+	assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+
+	// The other workspaces are not locked
+	unlockProd, err := acquireTerraformWorkspaceLock(componentPath, "plat-ue2-prod", 0, "atmos terraform apply vpc -s plat-ue2-prod")
+	require.NoError(t, err)
+	unlockProd()
+
+	// The lock is acquired when it's released while waiting
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		unlock()
+	}()
+	unlock, err = acquireTerraformWorkspaceLock(componentPath, "plat-ue2-dev", 5*time.Second, "atmos terraform plan vpc -s plat-ue2-dev")
+	require.NoError(t, err)
+	unlock()
+	assert.NoFileExists(t, lockFile+".info")
+
+	// The same workspace in another component folder is not locked
+	unlockOther, err := acquireTerraformWorkspaceLock(t.TempDir(), "plat-ue2-dev", 0, "atmos terraform apply vpc -s plat-ue2-dev")
+	require.NoError(t, err)
+	unlockOther()
+}
+
+func TestProcessArgsAndFlagsLockTimeout(t *testing.T) {
+	info, err := processArgsAndFlags("terraform", []string{"apply", "vpc", "-s", "dev", "--workspace-lock-timeout=5m", "-lock-timeout=10s"})
+	require.NoError(t, err)
+	assert.Equal(t, "5m", info.WorkspaceLockTimeout)
+	// The Terraform state lock timeout is passed to Terraform
+	assert.Equal(t, []string{"-lock-timeout=10s"}, info.AdditionalArgsAndFlags)
+
+	info, err = processArgsAndFlags("terraform", []string{"plan", "vpc", "--workspace-lock-timeout", "30s", "-s", "dev"})
+	require.NoError(t, err)
+	assert.Equal(t, "30s", info.WorkspaceLockTimeout)
+	assert.Empty(t, info.AdditionalArgsAndFlags)
+
+	// Terraform accepts the state lock timeout with a double dash too, and it's passed to Terraform
+	info, err = processArgsAndFlags("terraform", []string{"apply", "vpc", "-s", "dev", "--lock-timeout=5m"})
+	require.NoError(t, err)
+	assert.Empty(t, info.WorkspaceLockTimeout)
+	assert.Equal(t, []string{"--lock-timeout=5m"}, info.AdditionalArgsAndFlags)
+
+	info, err = processArgsAndFlags("terraform", []string{"apply", "vpc", "--lock-timeout", "5m", "-s", "dev"})
+	require.NoError(t, err)
+	assert.Empty(t, info.WorkspaceLockTimeout)
+	assert.Equal(t, []string{"--lock-timeout", "5m"}, info.AdditionalArgsAndFlags)
+}
@@ -20,6 +20,9 @@ import (
 
 var terraformOutputsCache = sync.Map{}
 
+// terraformComponentPathLocks are the locks of the component folders where `terraform output` is executed
+var terraformComponentPathLocks = sync.Map{}
+
 const (
 	cliArgsEnvVar            = "TF_CLI_ARGS"
 	inputEnvVar              = "TF_INPUT"
@@ -112,6 +115,21 @@ func execTerraformOutput(
 			return nil, fmt.Errorf("the component '%s' in the stack '%s' has an invalid 'component_info.component_path' section", component, stack)
 		}
 
+		// Read the outputs directly from the state in the component's backend.
+		// Execute `terraform output` if the backend is not supported or the state can't be read
+		var outputs map[string]any
+		outputs, _, err = readTerraformOutputsFromState(atmosConfig, component, stack, sections)
+		if err == nil {
+			return outputs, nil
+		}
+		l.Debug(fmt.Sprintf("Executing 'terraform output %s -s %s' since the outputs can't be read from the state", component, stack), "reason", err)
+
+		// The outputs of multiple components can be read concurrently.
+		// Don't execute `terraform init` and `terraform output` in the same component folder at the same time
+		componentPathLock, _ := terraformComponentPathLocks.LoadOrStore(componentPath, &sync.Mutex{})
+		componentPathLock.(*sync.Mutex).Lock()
+		defer componentPathLock.(*sync.Mutex).Unlock()
+
 		// Auto-generate backend file
 		if atmosConfig.Components.Terraform.AutoGenerateBackendFile {
 			backendFileName := filepath.Join(componentPath, "backend.tf.json")
@@ -294,50 +312,66 @@ func GetTerraformOutput(
 		defer StopSpinner(p, spinnerDone)
 	}
 
-	sections, err := ExecuteDescribeComponent(component, stack, true, true, nil)
+	outputs, static, err := fetchTerraformOutputs(atmosConfig, component, stack)
 	if err != nil {
 		if atmosConfig.Logs.Level == u.LogLevelTrace || atmosConfig.Logs.Level == u.LogLevelDebug {
 			fmt.Printf("\r✗ %s\n", message)
 		}
-		l.Fatal("Failed to describe the component", "component", component, "stack", stack, "error", err)
+		l.Fatal("Failed to get the outputs", "component", component, "stack", stack, "error", err)
+	}
+
+	var result any
+	if static {
+		result = getStaticRemoteStateOutput(atmosConfig, component, stack, outputs, output)
+	} else {
+		result = getTerraformOutputVariable(atmosConfig, component, stack, outputs, output)
+	}
+
+	if atmosConfig.Logs.Level == u.LogLevelTrace || atmosConfig.Logs.Level == u.LogLevelDebug {
+		// Show success
+		fmt.Printf("\r✓ %s\n", message)
+	}
+
+	return result
+}
+
+// fetchTerraformOutputs returns the outputs of the component in the stack, and caches them in memory.
+// If the component is configured with the 'static' remote state backend, the static remote state is returned
+// instead of executing `terraform output`, and `static` is `true`
+func fetchTerraformOutputs(
+	atmosConfig *schema.AtmosConfiguration,
+	component string,
+	stack string,
+) (outputs map[string]any, static bool, err error) {
+	stackSlug := fmt.Sprintf("%s-%s", stack, component)
+
+	sections, err := ExecuteDescribeComponent(component, stack, true, true, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to describe the component '%s' in the stack '%s': %w", component, stack, err)
 	}
 
 	// Check if the component in the stack is configured with the 'static' remote state backend, in which case get the
 	// `output` from the static remote state instead of executing `terraform output`
 	remoteStateBackendStaticTypeOutputs, err := GetComponentRemoteStateBackendStaticType(sections)
 	if err != nil {
-		if atmosConfig.Logs.Level == u.LogLevelTrace || atmosConfig.Logs.Level == u.LogLevelDebug {
-			fmt.Printf("\r✗ %s\n", message)
-		}
-		l.Fatal("Failed to get remote state backend static type outputs", "error", err)
+		return nil, false, fmt.Errorf("failed to get the 'static' remote state backend outputs: %w", err)
 	}
 
-	var result any
 	if remoteStateBackendStaticTypeOutputs != nil {
 		// Cache the result
 		terraformOutputsCache.Store(stackSlug, remoteStateBackendStaticTypeOutputs)
-		result = getStaticRemoteStateOutput(atmosConfig, component, stack, remoteStateBackendStaticTypeOutputs, output)
-	} else {
-		// Execute `terraform output`
-		terraformOutputs, err := execTerraformOutput(atmosConfig, component, stack, sections)
-		if err != nil {
-			if atmosConfig.Logs.Level == u.LogLevelTrace || atmosConfig.Logs.Level == u.LogLevelDebug {
-				fmt.Printf("\r✗ %s\n", message)
-			}
-			l.Fatal("Failed to execute terraform output", "component", component, "stack", stack, "error", err)
-		}
-
-		// Cache the result
-		terraformOutputsCache.Store(stackSlug, terraformOutputs)
-		result = getTerraformOutputVariable(atmosConfig, component, stack, terraformOutputs, output)
+		return remoteStateBackendStaticTypeOutputs, true, nil
 	}
 
-	if atmosConfig.Logs.Level == u.LogLevelTrace || atmosConfig.Logs.Level == u.LogLevelDebug {
-		// Show success
-		fmt.Printf("\r✓ %s\n", message)
+	// Execute `terraform output`
+	terraformOutputs, err := getTerraformOutputs(atmosConfig, component, stack, sections)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to execute terraform output for the component '%s' in the stack '%s': %w", component, stack, err)
 	}
 
-	return result
+	// Cache the result
+	terraformOutputsCache.Store(stackSlug, terraformOutputs)
+	return terraformOutputs, false, nil
 }
 
 func getTerraformOutputVariable(
@@ -382,7 +416,6 @@ func getStaticRemoteStateOutput(
 
 // environToMap converts all the environment variables (excluding the variables prohibited by terraform-exec/tfexec)
 // in the environment into a map of strings
-// TODO: review this (find another way to execute `terraform output` not using `terraform-exec/tfexec`)
 func environToMap() map[string]string {
 	envMap := make(map[string]string)
 	for _, env := range os.Environ() {
@@ -0,0 +1,348 @@
+package exec
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	l "github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+
+	cfg "github.com/cloudposse/atmos/pkg/config"
+	"github.com/cloudposse/atmos/pkg/schema"
+	u "github.com/cloudposse/atmos/pkg/utils"
+)
+
+// terraformOutputsCacheEntry is the cached outputs of a component in a stack
+type terraformOutputsCacheEntry struct {
+	Stack     string `json:"stack"`
+	Component string `json:"component"`
+	// The hash of the backend type, backend configuration and workspace of the component
+	BackendHash string `json:"backend_hash"`
+	// The version of the state the outputs were read from (see `getTerraformStateVersion`).
+	// If the version can't be read from the backend, the entry is valid until the TTL expires
+	StateVersion string         `json:"state_version,omitempty"`
+	Created      time.Time      `json:"created"`
+	Outputs      map[string]any `json:"outputs"`
+}
+
+// ExecuteCacheClearCmd executes `atmos cache clear` commands
+func ExecuteCacheClearCmd(cmd *cobra.Command, args []string) error {
+	stack, err := cmd.Flags().GetString("stack")
+	if err != nil {
+		return err
+	}
+
+	component, err := cmd.Flags().GetString("component")
+	if err != nil {
+		return err
+	}
+
+	atmosConfig, err := cfg.InitCliConfig(schema.ConfigAndStacksInfo{}, false)
+	if err != nil {
+		return err
+	}
+
+	cacheDir, err := getTerraformOutputsCacheDir(&atmosConfig)
+	if err != nil {
+		return err
+	}
+
+	removed, err := clearTerraformOutputsCache(cacheDir, stack, component)
+	if err != nil {
+		return err
+	}
+
+	u.PrintMessage(fmt.Sprintf("Removed %d cached component outputs from '%s'.", removed, cacheDir))
+
+	return nil
+}
+
+// getTerraformOutputsCacheDir returns the directory of the outputs cache of the Atmos project.
+// The `components.terraform.outputs_cache.path` directory is used if it's configured.
+// Otherwise, the outputs are cached in `$XDG_CACHE_HOME/atmos/terraform-outputs` (or in the user cache directory if `XDG_CACHE_HOME`
+// is not set), in a subdirectory per Atmos project
+func getTerraformOutputsCacheDir(atmosConfig *schema.AtmosConfiguration) (string, error) {
+	if cachePath := atmosConfig.Components.Terraform.OutputsCache.Path; cachePath != "" {
+		if filepath.IsAbs(cachePath) {
+			return cachePath, nil
+		}
+		return filepath.Join(atmosConfig.BasePath, cachePath), nil
+	}
+
+	basePath, err := filepath.Abs(atmosConfig.BasePath)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(basePath))
+
+	return cfg.GetAtmosCacheDir("terraform-outputs", hex.EncodeToString(sum[:8]))
+}
+
+// getTerraformOutputsCacheTTL returns the time-to-live of the cached outputs (`components.terraform.outputs_cache.ttl`).
+// The default TTL is used if it's not configured
+func getTerraformOutputsCacheTTL(atmosConfig *schema.AtmosConfiguration) (time.Duration, error) {
+	ttl := atmosConfig.Components.Terraform.OutputsCache.TTL
+	if ttl == "" {
+		ttl = cfg.DefaultTerraformOutputsTTL
+	}
+
+	duration, err := time.ParseDuration(ttl)
+	if err != nil {
+		return 0, fmt.Errorf("invalid 'components.terraform.outputs_cache.ttl' '%s': %w", ttl, err)
+	}
+	return duration, nil
+}
+
+// getTerraformOutputsCacheFile returns the file of the cached outputs of the component in the stack
+func getTerraformOutputsCacheFile(cacheDir string, stack string, component string) string {
+	sum := sha256.Sum256([]byte(stack + "\x00" + component))
+	return filepath.Join(cacheDir, hex.EncodeToString(sum[:])+".json")
+}
+
+// getTerraformOutputsBackendHash returns the hash of the backend type, backend configuration and workspace of the component.
+// The cached outputs are not used if the component's backend changes
+func getTerraformOutputsBackendHash(sections map[string]any) (string, error) {
+	data, err := json.Marshal(map[string]any{
+		cfg.BackendTypeSectionName: sections[cfg.BackendTypeSectionName],
+		cfg.BackendSectionName:     sections[cfg.BackendSectionName],
+		cfg.WorkspaceSectionName:   sections[cfg.WorkspaceSectionName],
+	})
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// getTerraformOutputs returns the outputs of the component in the stack.
+// If the outputs cache is enabled, the outputs are read from the cache, and cached after they are read from the backend
+func getTerraformOutputs(
+	atmosConfig *schema.AtmosConfiguration,
+	component string,
+	stack string,
+	sections map[string]any,
+) (map[string]any, error) {
+	if !atmosConfig.Components.Terraform.OutputsCache.Enabled {
+		return execTerraformOutput(atmosConfig, component, stack, sections)
+	}
+
+	ttl, err := getTerraformOutputsCacheTTL(atmosConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheDir, err := getTerraformOutputsCacheDir(atmosConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	backendHash, err := getTerraformOutputsBackendHash(sections)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheFile := getTerraformOutputsCacheFile(cacheDir, stack, component)
+
+	if outputs, ok := readTerraformOutputsCache(cacheFile, component, stack, sections, backendHash, ttl); ok {
+		return outputs, nil
+	}
+
+	// Read the version of the state before the outputs, so the cached outputs are never newer than the cached version
+	stateVersion, err := getTerraformStateVersion(component, stack, sections)
+	if err != nil {
+		l.Debug(fmt.Sprintf("Caching the outputs of the component '%s' in the stack '%s' for the TTL only", component, stack), "reason", err)
+		stateVersion = ""
+	}
+
+	outputs, err := execTerraformOutput(atmosConfig, component, stack, sections)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := terraformOutputsCacheEntry{
+		Stack:        stack,
+		Component:    component,
+		BackendHash:  backendHash,
+		StateVersion: stateVersion,
+		Created:      time.Now().UTC(),
+		Outputs:      outputs,
+	}
+
+	if err = writeTerraformOutputsCacheEntry(cacheFile, entry); err != nil {
+		l.Warn(fmt.Sprintf("Failed to cache the outputs of the component '%s' in the stack '%s'", component, stack), "error", err)
+	}
+
+	return outputs, nil
+}
+
+// readTerraformOutputsCache returns the cached outputs of the component in the stack.
+// The cached outputs are used if they were cached for the same backend within the TTL, and the state in the backend has not changed
+func readTerraformOutputsCache(
+	cacheFile string,
+	component string,
+	stack string,
+	sections map[string]any,
+	backendHash string,
+	ttl time.Duration,
+) (map[string]any, bool) {
+	entry, err := readTerraformOutputsCacheEntry(cacheFile)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			l.Debug(fmt.Sprintf("Ignoring the invalid cache entry '%s'", cacheFile), "error", err)
+		}
+		return nil, false
+	}
+
+	switch {
+	case entry.BackendHash != backendHash:
+		l.Debug(fmt.Sprintf("The backend of the component '%s' in the stack '%s' has changed since the outputs were cached", component, stack))
+		return nil, false
+	case time.Since(entry.Created) > ttl:
+		l.Debug(fmt.Sprintf("The cached outputs of the component '%s' in the stack '%s' have expired", component, stack))
+		return nil, false
+	}
+
+	if entry.StateVersion != "" {
+		stateVersion, err := getTerraformStateVersion(component, stack, sections)
+		if err != nil {
+			l.Debug(fmt.Sprintf("Failed to check the state of the component '%s' in the stack '%s'", component, stack), "error", err)
+			return nil, false
+		}
+		if stateVersion != entry.StateVersion {
+			l.Debug(fmt.Sprintf("The state of the component '%s' in the stack '%s' has changed since the outputs were cached", component, stack))
+			return nil, false
+		}
+	}
+
+	l.Debug(fmt.Sprintf("Using the cached outputs of the component '%s' in the stack '%s'", component, stack), "file", cacheFile)
+
+	return entry.Outputs, true
+}
+
+func readTerraformOutputsCacheEntry(cacheFile string) (terraformOutputsCacheEntry, error) {
+	var entry terraformOutputsCacheEntry
+
+	data, err := os.ReadFile(cacheFile)
+	if err != nil {
+		return entry, err
+	}
+
+	if err = json.Unmarshal(data, &entry); err != nil {
+		return entry, err
+	}
+
+	return entry, nil
+}
+
+// writeTerraformOutputsCacheEntry writes the cache entry to a temporary file and renames it, so the concurrent Atmos processes
+// never read a partially written entry. The outputs can contain sensitive values, so the cache is readable only by the user
+func writeTerraformOutputsCacheEntry(cacheFile string, entry terraformOutputsCacheEntry) error {
+	if err := os.MkdirAll(filepath.Dir(cacheFile), 0o700); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(cacheFile), filepath.Base(cacheFile)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err = f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), cacheFile)
+}
+
+// isTerraformStateChangingCommand returns `true` if the Terraform command can change the state (and the outputs) of the component
+func isTerraformStateChangingCommand(subCommand string) bool {
+	switch subCommand {
+	case "apply", "deploy", "destroy", "import", "refresh", "state", "taint", "untaint":
+		return true
+	}
+	return false
+}
+
+// invalidateTerraformOutputsCache removes the cached outputs of the component in the stack from the outputs cache
+// and from the in-memory caches of the `!terraform.output` YAML function and the `atmos.Component` template function
+func invalidateTerraformOutputsCache(atmosConfig schema.AtmosConfiguration, component string, stack string) {
+	stackSlug := fmt.Sprintf("%s-%s", stack, component)
+	terraformOutputsCache.Delete(stackSlug)
+	componentFuncSyncMap.Delete(stackSlug)
+
+	cacheDir, err := getTerraformOutputsCacheDir(&atmosConfig)
+	if err != nil {
+		return
+	}
+
+	cacheFile := getTerraformOutputsCacheFile(cacheDir, stack, component)
+	if err = os.Remove(cacheFile); err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			l.Warn(fmt.Sprintf("Failed to remove the cached outputs of the component '%s' in the stack '%s'", component, stack), "error", err)
+		}
+		return
+	}
+
+	l.Debug(fmt.Sprintf("Removed the cached outputs of the component '%s' in the stack '%s'", component, stack))
+}
+
+// clearTerraformOutputsCache removes the cached outputs from the cache directory.
+// If the stack or the component is specified, only the outputs of the component or the components in the stack are removed
+func clearTerraformOutputsCache(cacheDir string, stack string, component string) (int, error) {
+	dirEntries, err := os.ReadDir(cacheDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	sort.Slice(dirEntries, func(i, j int) bool {
+		return dirEntries[i].Name() < dirEntries[j].Name()
+	})
+
+	removed := 0
+	for _, d := range dirEntries {
+		if d.IsDir() || !strings.HasSuffix(d.Name(), ".json") {
+			continue
+		}
+
+		cacheFile := filepath.Join(cacheDir, d.Name())
+
+		if stack != "" || component != "" {
+			entry, err := readTerraformOutputsCacheEntry(cacheFile)
+			if err != nil {
+				l.Debug(fmt.Sprintf("Ignoring the invalid cache entry '%s'", cacheFile), "error", err)
+				continue
+			}
+			if (stack != "" && entry.Stack != stack) || (component != "" && entry.Component != component) {
+				continue
+			}
+		}
+
+		if err = os.Remove(cacheFile); err != nil && !os.IsNotExist(err) {
+			return removed, err
+		}
+		removed++
+	}
+
+	return removed, nil
+}
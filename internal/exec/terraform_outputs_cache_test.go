@@ -0,0 +1,129 @@
+package exec
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudposse/atmos/pkg/schema"
+)
+
+func TestGetTerraformOutputsWithCache(t *testing.T) {
+	componentPath := t.TempDir()
+	cacheDir := t.TempDir()
+
+	atmosConfig := &schema.AtmosConfiguration{}
+	atmosConfig.Components.Terraform.OutputsCache = schema.TerraformOutputsCache{Enabled: true, TTL: "1h", Path: cacheDir}
+
+	sections := map[string]any{
+		"command":        "terraform",
+		"backend_type":   "local",
+		"backend":        map[string]any{},
+		"workspace":      "plat-ue2-dev",
+		"component_info": map[string]any{"component_path": componentPath},
+	}
+
+	stateDir := filepath.Join(componentPath, "terraform.tfstate.d", "plat-ue2-dev")
+	require.NoError(t, os.MkdirAll(stateDir, 0o755))
+	stateFile := filepath.Join(stateDir, "terraform.tfstate")
+	require.NoError(t, os.WriteFile(stateFile, []byte(testTerraformState), 0o644))
+
+	outputs, err := getTerraformOutputs(atmosConfig, "vpc", "plat-ue2-dev", sections)
+	require.NoError(t, err)
+	assert.Equal(t, "vpc-123", outputs["vpc_id"])
+
+	cacheFile := getTerraformOutputsCacheFile(cacheDir, "plat-ue2-dev", "vpc")
+	entry, err := readTerraformOutputsCacheEntry(cacheFile)
+	require.NoError(t, err)
+	assert.Equal(t, "f0d4c9a6-55a7-c8b2-6e4c-0f3f0b2a5c11:3", entry.StateVersion)
+
+	// The cached outputs are used while the serial of the state does not change
+	require.NoError(t, os.WriteFile(stateFile, []byte(strings.Replace(testTerraformState, "vpc-123", "vpc-456", 1)), 0o644))
+	outputs, err = getTerraformOutputs(atmosConfig, "vpc", "plat-ue2-dev", sections)
+	require.NoError(t, err)
+	assert.Equal(t, "vpc-123", outputs["vpc_id"])
+
+	// A new serial of the state invalidates the cached outputs
+	require.NoError(t, os.WriteFile(stateFile, []byte(strings.NewReplacer("vpc-123", "vpc-456", `"serial": 3`, `"serial": 4`).Replace(testTerraformState)), 0o644))
+	outputs, err = getTerraformOutputs(atmosConfig, "vpc", "plat-ue2-dev", sections)
+	require.NoError(t, err)
+	assert.Equal(t, "vpc-456", outputs["vpc_id"])
+
+	// The cached outputs are not used if the backend changes
+	backendHash, err := getTerraformOutputsBackendHash(sections)
+	require.NoError(t, err)
+	_, ok := readTerraformOutputsCache(cacheFile, "vpc", "plat-ue2-dev", sections, backendHash, time.Hour)
+	assert.True(t, ok)
+
+	otherSections := map[string]any{"backend_type": "local", "backend": map[string]any{"workspace_dir": "states"}, "workspace": "plat-ue2-dev"}
+	otherBackendHash, err := getTerraformOutputsBackendHash(otherSections)
+	require.NoError(t, err)
+	assert.NotEqual(t, backendHash, otherBackendHash)
+	_, ok = readTerraformOutputsCache(cacheFile, "vpc", "plat-ue2-dev", sections, otherBackendHash, time.Hour)
+	assert.False(t, ok)
+
+	// The cached outputs expire after the TTL
+	_, ok = readTerraformOutputsCache(cacheFile, "vpc", "plat-ue2-dev", sections, backendHash, time.Nanosecond)
+	assert.False(t, ok)
+
+	// `atmos terraform apply` removes the cached outputs of the component
+	terraformOutputsCache.Store("plat-ue2-dev-vpc", outputs)
+	invalidateTerraformOutputsCache(*atmosConfig, "vpc", "plat-ue2-dev")
+	assert.NoFileExists(t, cacheFile)
+	_, found := terraformOutputsCache.Load("plat-ue2-dev-vpc")
+	assert.False(t, found)
+}
+
+func TestGetTerraformOutputsCacheTTL(t *testing.T) {
+	atmosConfig := &schema.AtmosConfiguration{}
+
+	// The default TTL is used if the `ttl` is not configured
+	atmosConfig.Components.Terraform.OutputsCache = schema.TerraformOutputsCache{Enabled: true}
+	ttl, err := getTerraformOutputsCacheTTL(atmosConfig)
+	require.NoError(t, err)
+	assert.Equal(t, time.Hour, ttl)
+
+	atmosConfig.Components.Terraform.OutputsCache.TTL = "30m"
+	ttl, err = getTerraformOutputsCacheTTL(atmosConfig)
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Minute, ttl)
+
+	atmosConfig.Components.Terraform.OutputsCache.TTL = "1 hour"
+	_, err = getTerraformOutputsCacheTTL(atmosConfig)
+	assert.ErrorContains(t, err, "invalid 'components.terraform.outputs_cache.ttl' '1 hour'")
+}
+
+func TestClearTerraformOutputsCache(t *testing.T) {
+	cacheDir := t.TempDir()
+
+	for _, e := range []terraformOutputsCacheEntry{
+		{Stack: "plat-ue2-dev", Component: "vpc"},
+		{Stack: "plat-ue2-dev", Component: "eks/cluster"},
+		{Stack: "plat-ue2-prod", Component: "vpc"},
+		{Stack: "plat-ue2-prod", Component: "eks/cluster"},
+	} {
+		require.NoError(t, writeTerraformOutputsCacheEntry(getTerraformOutputsCacheFile(cacheDir, e.Stack, e.Component), e))
+	}
+
+	removed, err := clearTerraformOutputsCache(cacheDir, "plat-ue2-dev", "vpc")
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+
+	removed, err = clearTerraformOutputsCache(cacheDir, "", "eks/cluster")
+	require.NoError(t, err)
+	assert.Equal(t, 2, removed)
+	assert.FileExists(t, getTerraformOutputsCacheFile(cacheDir, "plat-ue2-prod", "vpc"))
+
+	removed, err = clearTerraformOutputsCache(cacheDir, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+
+	removed, err = clearTerraformOutputsCache(filepath.Join(cacheDir, "missing"), "", "")
+	require.NoError(t, err)
+	assert.Equal(t, 0, removed)
+}
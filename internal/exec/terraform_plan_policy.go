@@ -0,0 +1,249 @@
+package exec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/mitchellh/mapstructure"
+
+	cfg "github.com/cloudposse/atmos/pkg/config"
+	"github.com/cloudposse/atmos/pkg/schema"
+	"github.com/cloudposse/atmos/pkg/ui/theme"
+	u "github.com/cloudposse/atmos/pkg/utils"
+)
+
+// terraformPlanPolicySchemaType is the `schema_type` of the `settings.validation` items which are evaluated against the Terraform plan
+const terraformPlanPolicySchemaType = "opa-plan"
+
+// TerraformPlanPolicyViolation is a violation of an OPA policy evaluated against the Terraform plan.
+// The policies return the violations in the `errors` array of the `atmos` package, either as strings or as objects
+// with the `rule`, `address` and `message` fields
+type TerraformPlanPolicyViolation struct {
+	// The name of the `settings.validation` item
+	Policy string `yaml:"policy" json:"policy" mapstructure:"policy"`
+	// The rule that denied the plan. Defaults to the name of the `settings.validation` item
+	Rule string `yaml:"rule" json:"rule" mapstructure:"rule"`
+	// The address of the resource that violates the rule
+	Address string `yaml:"address" json:"address" mapstructure:"address"`
+	Message string `yaml:"message" json:"message" mapstructure:"message"`
+}
+
+// findTerraformPlanPolicies returns the enabled `settings.validation` items of the component with `schema_type: opa-plan`
+func findTerraformPlanPolicies(componentSection map[string]any) (schema.Validation, error) {
+	validations, err := FindValidationSection(componentSection)
+	if err != nil {
+		return nil, err
+	}
+
+	policies := schema.Validation{}
+	for name, v := range validations {
+		if v.SchemaType == terraformPlanPolicySchemaType && !v.Disabled {
+			policies[name] = v
+		}
+	}
+
+	return policies, nil
+}
+
+// getTerraformPlanPolicyInput returns the input document for the OPA policies.
+// It contains the Terraform plan (the output of `terraform show -json`), the component section and the stack context
+func getTerraformPlanPolicyInput(info schema.ConfigAndStacksInfo, planJSON []byte) (map[string]any, error) {
+	var plan any
+	dec := json.NewDecoder(bytes.NewReader(planJSON))
+	dec.UseNumber()
+	if err := dec.Decode(&plan); err != nil {
+		return nil, fmt.Errorf("failed to parse the plan: %w", err)
+	}
+
+	return map[string]any{
+		"plan":      plan,
+		"component": info.ComponentSection,
+		"stack":     info.Stack,
+		"context":   info.Context,
+	}, nil
+}
+
+// evaluateTerraformPlanPolicies evaluates the OPA policies against the input document and returns the violations
+// sorted by policy, rule and resource address
+func evaluateTerraformPlanPolicies(
+	atmosConfig schema.AtmosConfiguration,
+	policies schema.Validation,
+	input map[string]any,
+) ([]TerraformPlanPolicyViolation, error) {
+	violations := []TerraformPlanPolicyViolation{}
+
+	for name, v := range policies {
+		schemaPath := v.SchemaPath
+		if !u.FileExists(schemaPath) {
+			schemaPath = filepath.Join(atmosConfig.BasePath, atmosConfig.Schemas.Opa.BasePath, v.SchemaPath)
+			if !u.FileExists(schemaPath) {
+				return nil, fmt.Errorf("the file '%s' does not exist for schema type '%s'", v.SchemaPath, v.SchemaType)
+			}
+		}
+
+		modulePaths, err := u.JoinAbsolutePathWithPaths(filepath.Join(atmosConfig.BasePath, atmosConfig.Schemas.Opa.BasePath), v.ModulePaths)
+		if err != nil {
+			return nil, err
+		}
+
+		u.LogDebug(fmt.Sprintf("Evaluating the policy '%s' in the file '%s' against the Terraform plan", name, schemaPath))
+
+		ers, err := evaluateOpaErrors(input, schemaPath, modulePaths, v.Timeout)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, e := range ers {
+			violation, err := toTerraformPlanPolicyViolation(e)
+			if err != nil {
+				return nil, fmt.Errorf("invalid item in the 'errors' output of the policy '%s': %w", name, err)
+			}
+			violation.Policy = name
+			if violation.Rule == "" {
+				violation.Rule = name
+			}
+			violations = append(violations, violation)
+		}
+	}
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		a, b := violations[i], violations[j]
+		if a.Policy != b.Policy {
+			return a.Policy < b.Policy
+		}
+		if a.Rule != b.Rule {
+			return a.Rule < b.Rule
+		}
+		return a.Address < b.Address
+	})
+
+	return violations, nil
+}
+
+// toTerraformPlanPolicyViolation converts an item of the `errors` output array of the policy to a violation
+func toTerraformPlanPolicyViolation(e any) (TerraformPlanPolicyViolation, error) {
+	var violation TerraformPlanPolicyViolation
+
+	switch v := e.(type) {
+	case string:
+		violation.Message = v
+	case map[string]any:
+		if err := mapstructure.Decode(v, &violation); err != nil {
+			return violation, err
+		}
+	default:
+		return violation, fmt.Errorf("expected a string or an object with 'rule', 'address' and 'message' fields, got '%v'", e)
+	}
+
+	return violation, nil
+}
+
+// processTerraformPlanPolicies evaluates the `opa-plan` policies of the component against the plan in the planfile.
+// If the policies deny the plan, `atmos terraform plan` fails
+func processTerraformPlanPolicies(
+	atmosConfig schema.AtmosConfiguration,
+	info schema.ConfigAndStacksInfo,
+	componentPath string,
+	planFile string,
+) error {
+	policies, err := findTerraformPlanPolicies(info.ComponentSection)
+	if err != nil || len(policies) == 0 {
+		return err
+	}
+
+	violations, err := getTerraformPlanPolicyViolations(atmosConfig, info, policies, componentPath, planFile)
+	if err != nil {
+		return err
+	}
+
+	if len(violations) == 0 {
+		u.LogDebug(fmt.Sprintf("The Terraform plan for the component '%s' in the stack '%s' passed the policies", info.ComponentFromArg, info.Stack))
+		return nil
+	}
+
+	u.PrintMessage(formatTerraformPlanPolicyViolations(violations))
+	return terraformPlanDeniedError(info, violations)
+}
+
+// checkTerraformPlanPolicies checks the `opa-plan` policies of the component before `terraform apply`.
+// The policies are evaluated against the applied planfile. Without a planfile, there is no plan to evaluate the policies against,
+// so the components with the `opa-plan` policies can only be applied from a planfile
+func checkTerraformPlanPolicies(
+	atmosConfig schema.AtmosConfiguration,
+	info schema.ConfigAndStacksInfo,
+	componentPath string,
+	planFile string,
+) error {
+	policies, err := findTerraformPlanPolicies(info.ComponentSection)
+	if err != nil || len(policies) == 0 {
+		return err
+	}
+
+	if !info.UseTerraformPlan {
+		return fmt.Errorf("the component '%s' in the stack '%s' has '%s' policies, and can only be applied from a planfile. "+
+			"Run 'atmos terraform plan' and then 'atmos terraform apply' (or 'atmos terraform deploy') with the '%s' or '%s' flag",
+			info.ComponentFromArg, info.Stack, terraformPlanPolicySchemaType, cfg.FromPlanFlag, cfg.PlanFileFlag)
+	}
+
+	violations, err := getTerraformPlanPolicyViolations(atmosConfig, info, policies, componentPath, planFile)
+	if err != nil {
+		return err
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+
+	u.PrintMessage(formatTerraformPlanPolicyViolations(violations))
+	return terraformPlanDeniedError(info, violations)
+}
+
+// getTerraformPlanPolicyViolations evaluates the policies against the plan in the planfile
+func getTerraformPlanPolicyViolations(
+	atmosConfig schema.AtmosConfiguration,
+	info schema.ConfigAndStacksInfo,
+	policies schema.Validation,
+	componentPath string,
+	planFile string,
+) ([]TerraformPlanPolicyViolation, error) {
+	planJSON, err := getTerraformPlanJSON(info, componentPath, planFile)
+	if err != nil {
+		return nil, err
+	}
+
+	input, err := getTerraformPlanPolicyInput(info, planJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	return evaluateTerraformPlanPolicies(atmosConfig, policies, input)
+}
+
+func terraformPlanDeniedError(info schema.ConfigAndStacksInfo, violations []TerraformPlanPolicyViolation) error {
+	return fmt.Errorf("the Terraform plan for the component '%s' in the stack '%s' was denied by %d policy violation(s). "+
+		"Fix the violations and run 'atmos terraform plan' again", info.ComponentFromArg, info.Stack, len(violations))
+}
+
+// formatTerraformPlanPolicyViolations formats the policy violations as a table with a row per rule and resource address
+func formatTerraformPlanPolicyViolations(violations []TerraformPlanPolicyViolation) string {
+	rows := make([][]string, 0, len(violations))
+	for _, v := range violations {
+		rows = append(rows, []string{theme.Styles.XMark.String() + " " + v.Policy, v.Rule, v.Address, v.Message})
+	}
+
+	t := table.New().
+		Border(lipgloss.RoundedBorder()).
+		BorderStyle(lipgloss.NewStyle().Foreground(lipgloss.Color(theme.ColorBorder))).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			return lipgloss.NewStyle().PaddingLeft(1).PaddingRight(1)
+		}).
+		Headers("Policy", "Rule", "Address", "Message").
+		Rows(rows...)
+
+	return t.String()
+}
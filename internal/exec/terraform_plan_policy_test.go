@@ -0,0 +1,114 @@
+package exec
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudposse/atmos/pkg/schema"
+)
+
+const testTerraformPlanPolicy = `package atmos
+
+import rego.v1
+
+errors contains {"rule": "no_destroy_in_prod", "address": rc.address, "message": "resources can't be destroyed in prod"} if {
+	input.context.stage == "prod"
+	some rc in input.plan.resource_changes
+	"delete" in rc.change.actions
+}
+
+errors contains "the 'team' tag is required" if {
+	not input.component.vars.tags.team
+}
+`
+
+func TestEvaluateTerraformPlanPolicies(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "plan.rego"), []byte(testTerraformPlanPolicy), 0o644))
+
+	atmosConfig := schema.AtmosConfiguration{BasePath: dir}
+
+	componentSection := map[string]any{
+		"vars": map[string]any{"tags": map[string]any{}},
+		"settings": map[string]any{
+			"validation": map[string]any{
+				"plan-policy": map[string]any{
+					"schema_type": "opa-plan",
+					"schema_path": "plan.rego",
+				},
+				"config-policy": map[string]any{
+					"schema_type": "opa",
+					"schema_path": "config.rego",
+				},
+			},
+		},
+	}
+
+	policies, err := findTerraformPlanPolicies(componentSection)
+	require.NoError(t, err)
+	assert.Len(t, policies, 1)
+	assert.Contains(t, policies, "plan-policy")
+
+	info := schema.ConfigAndStacksInfo{
+		Stack:            "plat-ue2-prod",
+		ComponentSection: componentSection,
+		Context:          schema.Context{Tenant: "plat", Environment: "ue2", Stage: "prod"},
+	}
+
+	input, err := getTerraformPlanPolicyInput(info, []byte(testTerraformPlanJSON))
+	require.NoError(t, err)
+
+	violations, err := evaluateTerraformPlanPolicies(atmosConfig, policies, input)
+	require.NoError(t, err)
+	assert.Equal(t, []TerraformPlanPolicyViolation{
+		{Policy: "plan-policy", Rule: "no_destroy_in_prod", Address: "aws_eip.nat", Message: "resources can't be destroyed in prod"},
+		{Policy: "plan-policy", Rule: "no_destroy_in_prod", Address: "aws_nat_gateway.default", Message: "resources can't be destroyed in prod"},
+		{Policy: "plan-policy", Rule: "plan-policy", Message: "the 'team' tag is required"},
+	}, violations)
+
+	// The plan is allowed in the other stages if the component has the required tags
+	info.Context.Stage = "dev"
+	componentSection["vars"] = map[string]any{"tags": map[string]any{"team": "platform"}}
+	input, err = getTerraformPlanPolicyInput(info, []byte(testTerraformPlanJSON))
+	require.NoError(t, err)
+
+	violations, err = evaluateTerraformPlanPolicies(atmosConfig, policies, input)
+	require.NoError(t, err)
+	assert.Empty(t, violations)
+
+	// The `opa-plan` policies can't validate the component configuration
+	_, err = validateComponentInternal(atmosConfig, componentSection, "plan.rego", "opa-plan", nil, 0)
+	assert.Error(t, err)
+}
+
+func TestCheckTerraformPlanPoliciesWithoutPlanfile(t *testing.T) {
+	componentSection := map[string]any{
+		"settings": map[string]any{
+			"validation": map[string]any{
+				"plan-policy": map[string]any{
+					"schema_type": "opa-plan",
+					"schema_path": "plan.rego",
+				},
+			},
+		},
+	}
+
+	info := schema.ConfigAndStacksInfo{
+		ComponentFromArg: "vpc",
+		Stack:            "plat-ue2-prod",
+		ComponentSection: componentSection,
+	}
+
+	// Without a prior plan, the apply is refused instead of skipping the policies
+	err := checkTerraformPlanPolicies(schema.AtmosConfiguration{}, info, t.TempDir(), "plat-ue2-prod-vpc.planfile")
+	assert.ErrorContains(t, err, "can only be applied from a planfile")
+
+	// The components without the `opa-plan` policies are applied as usual
+	info.ComponentSection = map[string]any{}
+	err = checkTerraformPlanPolicies(schema.AtmosConfiguration{}, info, t.TempDir(), "plat-ue2-prod-vpc.planfile")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,291 @@
+package exec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	osexec "os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	tfjson "github.com/hashicorp/terraform-json"
+
+	"github.com/cloudposse/atmos/pkg/schema"
+	u "github.com/cloudposse/atmos/pkg/utils"
+)
+
+// terraformPlanSummaryFormats are the supported formats of the plan summary file
+var terraformPlanSummaryFormats = []string{"markdown", "json"}
+
+// TerraformPlanSummary is the summary of the changes in a Terraform plan
+type TerraformPlanSummary struct {
+	Component string `yaml:"component" json:"component"`
+	Stack     string `yaml:"stack" json:"stack"`
+	// The addresses of the resources to create, update, delete and replace (delete and create)
+	Add     []string `yaml:"add" json:"add"`
+	Change  []string `yaml:"change" json:"change"`
+	Destroy []string `yaml:"destroy" json:"destroy"`
+	Replace []string `yaml:"replace" json:"replace"`
+	// The changed outputs and their actions (create, update or delete)
+	Outputs map[string]string `yaml:"outputs" json:"outputs"`
+	// HasChanges is 'true' if the plan changes any resources or outputs
+	HasChanges bool `yaml:"has_changes" json:"has_changes"`
+	// Destructive is 'true' if the plan destroys or replaces any resources
+	Destructive bool `yaml:"destructive" json:"destructive"`
+}
+
+// parseTerraformPlanSummary creates the summary of the plan from the output of `terraform show -json <planfile>`
+func parseTerraformPlanSummary(data []byte) (TerraformPlanSummary, error) {
+	summary := TerraformPlanSummary{
+		Add:     []string{},
+		Change:  []string{},
+		Destroy: []string{},
+		Replace: []string{},
+		Outputs: map[string]string{},
+	}
+
+	var plan tfjson.Plan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return summary, fmt.Errorf("failed to parse the plan: %w", err)
+	}
+
+	for _, rc := range plan.ResourceChanges {
+		// Data sources are read, not changed
+		if rc.Change == nil || rc.Mode == tfjson.DataResourceMode {
+			continue
+		}
+
+		actions := rc.Change.Actions
+		switch {
+		case actions.Replace():
+			summary.Replace = append(summary.Replace, rc.Address)
+		case actions.Create():
+			summary.Add = append(summary.Add, rc.Address)
+		case actions.Update():
+			summary.Change = append(summary.Change, rc.Address)
+		case actions.Delete():
+			summary.Destroy = append(summary.Destroy, rc.Address)
+		}
+	}
+
+	for name, change := range plan.OutputChanges {
+		if change == nil {
+			continue
+		}
+		switch {
+		case change.Actions.Create():
+			summary.Outputs[name] = string(tfjson.ActionCreate)
+		case change.Actions.Update():
+			summary.Outputs[name] = string(tfjson.ActionUpdate)
+		case change.Actions.Delete():
+			summary.Outputs[name] = string(tfjson.ActionDelete)
+		}
+	}
+
+	summary.Destructive = len(summary.Destroy) > 0 || len(summary.Replace) > 0
+	summary.HasChanges = summary.Destructive || len(summary.Add) > 0 || len(summary.Change) > 0 || len(summary.Outputs) > 0
+
+	return summary, nil
+}
+
+// getTerraformPlanJSON executes `terraform show -json` on the planfile of the component and returns the JSON representation of the plan
+func getTerraformPlanJSON(info schema.ConfigAndStacksInfo, componentPath string, planFile string) ([]byte, error) {
+	cmd := osexec.Command(info.Command, "show", "-json", planFile)
+	cmd.Dir = componentPath
+	cmd.Env = append(os.Environ(), info.ComponentEnvList...)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	u.LogDebug(fmt.Sprintf("Executing '%s show -json %s' in '%s'", info.Command, planFile, componentPath))
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to execute '%s show -json %s': %w\n%s", info.Command, planFile, err, stderr.String())
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// getTerraformPlanSummary returns the summary of the plan in the planfile of the component
+func getTerraformPlanSummary(info schema.ConfigAndStacksInfo, componentPath string, planFile string) (TerraformPlanSummary, error) {
+	planJSON, err := getTerraformPlanJSON(info, componentPath, planFile)
+	if err != nil {
+		return TerraformPlanSummary{}, err
+	}
+
+	summary, err := parseTerraformPlanSummary(planJSON)
+	if err != nil {
+		return summary, err
+	}
+
+	summary.Component = info.ComponentFromArg
+	summary.Stack = info.Stack
+	return summary, nil
+}
+
+// getTerraformPlanFileFromArgs returns the planfile from the `-out` flag passed to `terraform plan`, or the default planfile
+func getTerraformPlanFileFromArgs(args []string, defaultPlanFile string) string {
+	for i, arg := range args {
+		if arg == outFlag && i+1 < len(args) {
+			return args[i+1]
+		}
+		if strings.HasPrefix(arg, outFlag+"=") {
+			return strings.TrimPrefix(arg, outFlag+"=")
+		}
+	}
+	return defaultPlanFile
+}
+
+// processTerraformPlanSummary prints the summary of the plan in the terminal, and writes it to the `--summary-file` if it's provided
+func processTerraformPlanSummary(info schema.ConfigAndStacksInfo, componentPath string, planFile string) error {
+	summary, err := getTerraformPlanSummary(info, componentPath, planFile)
+	if err != nil {
+		return err
+	}
+
+	u.PrintfMarkdown("%s", formatTerraformPlanSummaryMarkdown(summary))
+
+	if info.PlanSummaryFile != "" {
+		return writeTerraformPlanSummaryFile(info.PlanSummaryFile, info.PlanSummaryFormat, []TerraformPlanSummary{summary})
+	}
+
+	return nil
+}
+
+// writeTerraformPlanSummaryFile writes the plan summaries to the file as Markdown (e.g. for a PR comment) or JSON.
+// In JSON, a single summary is written as an object, and multiple summaries are written as an array
+func writeTerraformPlanSummaryFile(file string, format string, summaries []TerraformPlanSummary) error {
+	var content string
+
+	switch format {
+	case "json":
+		var data []byte
+		var err error
+		if len(summaries) == 1 {
+			data, err = json.MarshalIndent(summaries[0], "", "  ")
+		} else {
+			data, err = json.MarshalIndent(summaries, "", "  ")
+		}
+		if err != nil {
+			return err
+		}
+		content = string(data) + "\n"
+	default:
+		sections := make([]string, 0, len(summaries))
+		for _, s := range summaries {
+			sections = append(sections, formatTerraformPlanSummaryMarkdown(s))
+		}
+		content = strings.Join(sections, "\n")
+	}
+
+	return writeTerraformPlanSummaryContent(file, content)
+}
+
+// writeTerraformPlanSummaryContent writes the formatted plan summary to the file, creating the parent directories if needed
+func writeTerraformPlanSummaryContent(file string, content string) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+		return err
+	}
+
+	u.LogDebug(fmt.Sprintf("Wrote the plan summary to '%s'", file))
+	return nil
+}
+
+// terraformPlanSummaryTempFile returns the path to the temporary plan summary file of the component in the stack
+func terraformPlanSummaryTempFile(dir string, node terraformNode) string {
+	name := strings.ReplaceAll(fmt.Sprintf("%s-%s.json", node.Stack, node.Component), "/", "-")
+	return filepath.Join(dir, name)
+}
+
+// combineTerraformPlanSummaries reads the plan summaries of the components from the temporary directory
+// and writes them to one file. The components for which the plan failed or was skipped are not included
+func combineTerraformPlanSummaries(dir string, nodes []terraformNode, file string, format string) error {
+	summaries := []TerraformPlanSummary{}
+
+	for _, node := range nodes {
+		data, err := os.ReadFile(terraformPlanSummaryTempFile(dir, node))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return err
+		}
+
+		var summary TerraformPlanSummary
+		if err = json.Unmarshal(data, &summary); err != nil {
+			return err
+		}
+		summaries = append(summaries, summary)
+	}
+
+	// Always write an array when planning multiple components
+	if format == "json" {
+		data, err := json.MarshalIndent(summaries, "", "  ")
+		if err != nil {
+			return err
+		}
+		return writeTerraformPlanSummaryContent(file, string(data)+"\n")
+	}
+
+	return writeTerraformPlanSummaryFile(file, format, summaries)
+}
+
+// formatTerraformPlanSummaryMarkdown formats the plan summary as Markdown. Destructive changes are highlighted
+func formatTerraformPlanSummaryMarkdown(s TerraformPlanSummary) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("### Plan for `%s` in `%s`\n\n", s.Component, s.Stack))
+
+	if !s.HasChanges {
+		sb.WriteString("No changes. The infrastructure matches the configuration.\n")
+		return sb.String()
+	}
+
+	sb.WriteString(fmt.Sprintf("**%d** to add, **%d** to change, **%d** to destroy, **%d** to replace\n\n",
+		len(s.Add), len(s.Change), len(s.Destroy), len(s.Replace)))
+
+	if s.Destructive {
+		sb.WriteString(fmt.Sprintf("> :warning: **Destructive changes:** %d to destroy, %d to replace\n\n", len(s.Destroy), len(s.Replace)))
+	}
+
+	if len(s.Add)+len(s.Change)+len(s.Destroy)+len(s.Replace) > 0 {
+		sb.WriteString("| Action | Resource |\n")
+		sb.WriteString("| :----- | :------- |\n")
+		// Destructive changes first
+		for _, address := range s.Destroy {
+			sb.WriteString(fmt.Sprintf("| **destroy** | `%s` |\n", address))
+		}
+		for _, address := range s.Replace {
+			sb.WriteString(fmt.Sprintf("| **replace** | `%s` |\n", address))
+		}
+		for _, address := range s.Change {
+			sb.WriteString(fmt.Sprintf("| change | `%s` |\n", address))
+		}
+		for _, address := range s.Add {
+			sb.WriteString(fmt.Sprintf("| add | `%s` |\n", address))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(s.Outputs) > 0 {
+		names := make([]string, 0, len(s.Outputs))
+		for name := range s.Outputs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		sb.WriteString("**Changed outputs:**\n\n")
+		for _, name := range names {
+			sb.WriteString(fmt.Sprintf("- `%s` (%s)\n", name, s.Outputs[name]))
+		}
+	}
+
+	return sb.String()
+}
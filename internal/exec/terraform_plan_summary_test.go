@@ -0,0 +1,106 @@
+package exec
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testTerraformPlanJSON = `{
+  "format_version": "1.2",
+  "terraform_version": "1.9.8",
+  "resource_changes": [
+    {"address": "aws_vpc.default", "mode": "managed", "type": "aws_vpc", "name": "default", "change": {"actions": ["no-op"]}},
+    {"address": "aws_subnet.private[0]", "mode": "managed", "type": "aws_subnet", "name": "private", "change": {"actions": ["create"]}},
+    {"address": "aws_route_table.private", "mode": "managed", "type": "aws_route_table", "name": "private", "change": {"actions": ["update"]}},
+    {"address": "aws_nat_gateway.default", "mode": "managed", "type": "aws_nat_gateway", "name": "default", "change": {"actions": ["delete"]}},
+    {"address": "aws_eip.nat", "mode": "managed", "type": "aws_eip", "name": "nat", "change": {"actions": ["delete", "create"]}},
+    {"address": "data.aws_region.current", "mode": "data", "type": "aws_region", "name": "current", "change": {"actions": ["read"]}}
+  ],
+  "output_changes": {
+    "vpc_id": {"actions": ["no-op"]},
+    "private_subnet_ids": {"actions": ["create"]},
+    "nat_gateway_id": {"actions": ["delete"]}
+  }
+}`
+
+func TestParseTerraformPlanSummary(t *testing.T) {
+	summary, err := parseTerraformPlanSummary([]byte(testTerraformPlanJSON))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"aws_subnet.private[0]"}, summary.Add)
+	assert.Equal(t, []string{"aws_route_table.private"}, summary.Change)
+	assert.Equal(t, []string{"aws_nat_gateway.default"}, summary.Destroy)
+	assert.Equal(t, []string{"aws_eip.nat"}, summary.Replace)
+	assert.Equal(t, map[string]string{"private_subnet_ids": "create", "nat_gateway_id": "delete"}, summary.Outputs)
+	assert.True(t, summary.HasChanges)
+	assert.True(t, summary.Destructive)
+
+	summary, err = parseTerraformPlanSummary([]byte(`{"format_version": "1.2", "resource_changes": []}`))
+	require.NoError(t, err)
+	assert.False(t, summary.HasChanges)
+	assert.False(t, summary.Destructive)
+
+	_, err = parseTerraformPlanSummary([]byte(`{"format_version": "2.0"}`))
+	assert.Error(t, err)
+}
+
+func TestFormatTerraformPlanSummary(t *testing.T) {
+	summary, err := parseTerraformPlanSummary([]byte(testTerraformPlanJSON))
+	require.NoError(t, err)
+	summary.Component = "vpc"
+	summary.Stack = "plat-ue2-dev"
+
+	md := formatTerraformPlanSummaryMarkdown(summary)
+	assert.Contains(t, md, "### Plan for `vpc` in `plat-ue2-dev`")
+	assert.Contains(t, md, "**1** to add, **1** to change, **1** to destroy, **1** to replace")
+	assert.Contains(t, md, "**Destructive changes:** 1 to destroy, 1 to replace")
+	assert.Contains(t, md, "| **replace** | `aws_eip.nat` |")
+	assert.Contains(t, md, "- `nat_gateway_id` (delete)")
+
+	md = formatTerraformPlanSummaryMarkdown(TerraformPlanSummary{Component: "vpc", Stack: "plat-ue2-prod"})
+	assert.Contains(t, md, "No changes")
+	assert.NotContains(t, md, "Destructive")
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "summaries", "plan.json")
+	require.NoError(t, writeTerraformPlanSummaryFile(file, "json", []TerraformPlanSummary{summary}))
+
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	var decoded TerraformPlanSummary
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, summary, decoded)
+
+	// The summaries of multiple components are combined into one file
+	nodes := []terraformNode{
+		{Component: "vpc", Stack: "plat-ue2-dev"},
+		{Component: "eks/cluster", Stack: "plat-ue2-dev"},
+	}
+	require.NoError(t, writeTerraformPlanSummaryFile(terraformPlanSummaryTempFile(dir, nodes[0]), "json", []TerraformPlanSummary{summary}))
+
+	combined := filepath.Join(dir, "plan.md")
+	require.NoError(t, combineTerraformPlanSummaries(dir, nodes, combined, "markdown"))
+	data, err = os.ReadFile(combined)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "### Plan for `vpc` in `plat-ue2-dev`")
+	assert.NotContains(t, string(data), "eks/cluster")
+}
+
+func TestProcessArgsAndFlagsPlanSummary(t *testing.T) {
+	info, err := processArgsAndFlags("terraform", []string{
+		"plan", "vpc", "-s", "dev", "--summary", "--summary-format", "json", "--summary-file=plan.json", "-out", "vpc.planfile",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "vpc", info.ComponentFromArg)
+	assert.True(t, info.PlanSummary)
+	assert.Equal(t, "json", info.PlanSummaryFormat)
+	assert.Equal(t, "plan.json", info.PlanSummaryFile)
+	assert.Equal(t, []string{"-out", "vpc.planfile"}, info.AdditionalArgsAndFlags)
+	assert.Equal(t, "vpc.planfile", getTerraformPlanFileFromArgs(info.AdditionalArgsAndFlags, "dev-vpc.planfile"))
+	assert.Equal(t, "dev-vpc.planfile", getTerraformPlanFileFromArgs(nil, "dev-vpc.planfile"))
+}
@@ -0,0 +1,664 @@
+package exec
+
+import (
+	"context"
+	"crypto/md5" //nolint:gosec
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	l "github.com/charmbracelet/log"
+	"google.golang.org/api/option"
+
+	cfg "github.com/cloudposse/atmos/pkg/config"
+	"github.com/cloudposse/atmos/pkg/schema"
+	u "github.com/cloudposse/atmos/pkg/utils"
+)
+
+// errTerraformStateBackendNotSupported is returned when the outputs can't be read directly from the state in the component's backend,
+// in which case `terraform output` is executed
+var errTerraformStateBackendNotSupported = errors.New("reading the state from the backend is not supported")
+
+// The default workspace. Terraform stores the state of the default workspace without the workspace prefix
+const defaultTerraformWorkspace = "default"
+
+// terraformStateAbsentVersion is the version of the state of a component which has not been provisioned in the stack
+const terraformStateAbsentVersion = "absent"
+
+// terraformState is the part of the Terraform state (version 4) that contains the root module outputs
+type terraformState struct {
+	Version int                             `json:"version"`
+	Serial  int64                           `json:"serial"`
+	Lineage string                          `json:"lineage"`
+	Outputs map[string]terraformStateOutput `json:"outputs"`
+}
+
+type terraformStateOutput struct {
+	Value json.RawMessage `json:"value"`
+}
+
+// readTerraformOutputsFromState reads the outputs of the component in the stack directly from the state in the component's backend
+// (`local`, `s3`, `gcs`, `azurerm` or `http`) without executing `terraform init` and `terraform output`.
+// It returns the outputs and the version of the state in the backend (see `getTerraformStateVersion`).
+// It returns `errTerraformStateBackendNotSupported` for other backends and backend configurations
+func readTerraformOutputsFromState(
+	atmosConfig *schema.AtmosConfiguration,
+	component string,
+	stack string,
+	sections map[string]any,
+) (map[string]any, string, error) {
+	data, version, err := readTerraformState(component, stack, sections, false)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// The component has not been provisioned in the stack
+	if data == nil {
+		l.Debug(fmt.Sprintf("The state of the component '%s' in the stack '%s' does not exist", component, stack))
+		return map[string]any{}, version, nil
+	}
+
+	outputs, err := parseTerraformStateOutputs(data)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if atmosConfig.Logs.Level == u.LogLevelTrace {
+		y, err2 := u.ConvertToYAML(outputs)
+		if err2 == nil {
+			l.Debug(fmt.Sprintf("Outputs of the component '%s' in the stack '%s' read from the state:\n%s\n", component, stack, y))
+		}
+	}
+
+	return outputs, version, nil
+}
+
+// getTerraformStateVersion returns the version of the state of the component in the stack without downloading the state when possible:
+// the lineage and serial of the `local` state, the ETag of the S3 object and the Azure blob, or the generation of the GCS object.
+// It returns `terraformStateAbsentVersion` if the component has not been provisioned in the stack
+func getTerraformStateVersion(component string, stack string, sections map[string]any) (string, error) {
+	_, version, err := readTerraformState(component, stack, sections, true)
+	return version, err
+}
+
+// readTerraformState reads the state of the component in the stack from the component's backend.
+// If `probe` is `true`, only the version of the state is returned
+func readTerraformState(component string, stack string, sections map[string]any, probe bool) ([]byte, string, error) {
+	backendType, _ := sections[cfg.BackendTypeSectionName].(string)
+	if backendType == "" {
+		return nil, "", fmt.Errorf("%w: the component '%s' in the stack '%s' does not have 'backend_type' defined", errTerraformStateBackendNotSupported, component, stack)
+	}
+
+	backend, _ := sections[cfg.BackendSectionName].(map[string]any)
+	if backend == nil {
+		backend = map[string]any{}
+	}
+
+	workspace, ok := sections[cfg.WorkspaceSectionName].(string)
+	if !ok || workspace == "" {
+		workspace = defaultTerraformWorkspace
+	}
+
+	componentPath := ""
+	if componentInfo, ok := sections["component_info"].(map[string]any); ok {
+		componentPath, _ = componentInfo["component_path"].(string)
+	}
+
+	env := map[string]string{}
+	if envSection, ok := sections[cfg.EnvSectionName].(map[string]any); ok {
+		for k, v := range envSection {
+			env[k] = fmt.Sprintf("%v", v)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	l.Debug(fmt.Sprintf("Reading the state of the component '%s' in the stack '%s' from the '%s' backend", component, stack, backendType))
+
+	var data []byte
+	var version string
+	var err error
+
+	switch backendType {
+	case "local":
+		data, version, err = readTerraformStateLocal(backend, workspace, componentPath)
+	case "s3":
+		data, version, err = readTerraformStateS3(ctx, backend, workspace, env, probe)
+	case "gcs":
+		data, version, err = readTerraformStateGCS(ctx, backend, workspace, probe)
+	case "azurerm":
+		data, version, err = readTerraformStateAzurerm(ctx, backend, workspace, env, probe)
+	case "http":
+		data, version, err = readTerraformStateHTTP(ctx, backend, workspace, env)
+	default:
+		return nil, "", fmt.Errorf("%w: '%s'", errTerraformStateBackendNotSupported, backendType)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	if version == "" {
+		version = terraformStateAbsentVersion
+	}
+
+	return data, version, nil
+}
+
+// parseTerraformStateOutputs returns the root module outputs from the Terraform state JSON
+func parseTerraformStateOutputs(data []byte) (map[string]any, error) {
+	var state terraformState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse the Terraform state: %w", err)
+	}
+
+	// Older state versions store the outputs in the modules
+	if state.Version < 4 {
+		return nil, fmt.Errorf("%w: state version %d", errTerraformStateBackendNotSupported, state.Version)
+	}
+
+	outputs := make(map[string]any, len(state.Outputs))
+	for k, v := range state.Outputs {
+		d, err := u.ConvertFromJSON(string(v.Value))
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert the output '%s': %w", k, err)
+		}
+		outputs[k] = d
+	}
+
+	return outputs, nil
+}
+
+// readTerraformStateLocal reads the state file of the `local` backend.
+// https://developer.hashicorp.com/terraform/language/settings/backends/local
+// The version of the state is its lineage and serial
+func readTerraformStateLocal(backend map[string]any, workspace string, componentPath string) ([]byte, string, error) {
+	var statePath string
+	if workspace == defaultTerraformWorkspace {
+		statePath = getBackendString(backend, "path", "terraform.tfstate")
+	} else {
+		statePath = filepath.Join(getBackendString(backend, "workspace_dir", "terraform.tfstate.d"), workspace, "terraform.tfstate")
+	}
+
+	if !filepath.IsAbs(statePath) {
+		statePath = filepath.Join(componentPath, statePath)
+	}
+
+	data, err := os.ReadFile(statePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, "", nil
+		}
+		return nil, "", err
+	}
+
+	var state terraformState
+	if err = json.Unmarshal(data, &state); err != nil {
+		return nil, "", fmt.Errorf("failed to parse the Terraform state '%s': %w", statePath, err)
+	}
+
+	return data, fmt.Sprintf("%s:%d", state.Lineage, state.Serial), nil
+}
+
+// terraformStateS3UnsupportedOptions are the options of the `s3` backend that are not supported when reading the state directly.
+// For the backends with these options, the outputs are read with `terraform output`
+var terraformStateS3UnsupportedOptions = []string{
+	"assume_role_with_web_identity",
+	"allowed_account_ids",
+	"forbidden_account_ids",
+	"custom_ca_bundle",
+	"insecure",
+	"use_fips_endpoint",
+	"use_dualstack_endpoint",
+	"http_proxy",
+	"https_proxy",
+	"no_proxy",
+	"ec2_metadata_service_endpoint",
+	"ec2_metadata_service_endpoint_mode",
+}
+
+// terraformStateS3AssumeRoleUnsupportedOptions are the options of the `assume_role` block of the `s3` backend
+// that are not supported when reading the state directly
+var terraformStateS3AssumeRoleUnsupportedOptions = []string{
+	"policy",
+	"policy_arns",
+	"tags",
+	"transitive_tag_keys",
+	"source_identity",
+}
+
+// readTerraformStateS3 reads the state object from the `s3` backend.
+// https://developer.hashicorp.com/terraform/language/settings/backends/s3
+func readTerraformStateS3(ctx context.Context, backend map[string]any, workspace string, env map[string]string, probe bool) ([]byte, string, error) {
+	bucket := getBackendString(backend, "bucket", "")
+	key := getBackendString(backend, "key", "")
+	if bucket == "" || key == "" {
+		return nil, "", fmt.Errorf("%w: the 's3' backend requires 'bucket' and 'key'", errTerraformStateBackendNotSupported)
+	}
+
+	for _, option := range terraformStateS3UnsupportedOptions {
+		if _, ok := backend[option]; ok {
+			return nil, "", fmt.Errorf("%w: the '%s' option of the 's3' backend", errTerraformStateBackendNotSupported, option)
+		}
+	}
+
+	if workspace != defaultTerraformWorkspace {
+		key = path.Join(getBackendString(backend, "workspace_key_prefix", "env:"), workspace, key)
+	}
+
+	awsConfig, err := loadTerraformStateS3Config(ctx, backend, env)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Custom endpoint (e.g. an S3-compatible storage)
+	endpoint := getBackendString(backend, "endpoint", "")
+	if endpoints, ok := backend["endpoints"].(map[string]any); ok {
+		endpoint = getBackendString(endpoints, "s3", endpoint)
+	}
+	if endpoint != "" && !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+
+	client := s3.NewFromConfig(awsConfig, func(o *s3.Options) {
+		if endpoint != "" {
+			o.BaseEndpoint = aws.String(endpoint)
+		}
+		o.UsePathStyle = getBackendBool(backend, "use_path_style") || getBackendBool(backend, "force_path_style")
+	})
+
+	// Server-side encryption with a customer-provided key (SSE-C)
+	var sseAlgorithm, sseKey, sseKeyMD5 *string
+	if sseCustomerKey := getBackendString(backend, "sse_customer_key", getEnv(env, "AWS_SSE_CUSTOMER_KEY")); sseCustomerKey != "" {
+		decoded, err := base64.StdEncoding.DecodeString(sseCustomerKey)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid 'sse_customer_key' of the 's3' backend: %w", err)
+		}
+		sum := md5.Sum(decoded) //nolint:gosec // S3 requires the MD5 digest of the customer-provided key
+		sseAlgorithm = aws.String("AES256")
+		sseKey = aws.String(sseCustomerKey)
+		sseKeyMD5 = aws.String(base64.StdEncoding.EncodeToString(sum[:]))
+	}
+
+	// The version of the state is the ETag of the object, which is returned by the `HeadObject` requests without downloading the object
+	if probe {
+		out, err := client.HeadObject(ctx, &s3.HeadObjectInput{
+			Bucket:               aws.String(bucket),
+			Key:                  aws.String(key),
+			SSECustomerAlgorithm: sseAlgorithm,
+			SSECustomerKey:       sseKey,
+			SSECustomerKeyMD5:    sseKeyMD5,
+		})
+		if err != nil {
+			if isS3NotFoundError(err) {
+				return nil, "", nil
+			}
+			return nil, "", fmt.Errorf("failed to read the state from 's3://%s/%s': %w", bucket, key, err)
+		}
+		return nil, aws.ToString(out.ETag), nil
+	}
+
+	out, err := client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket:               aws.String(bucket),
+		Key:                  aws.String(key),
+		SSECustomerAlgorithm: sseAlgorithm,
+		SSECustomerKey:       sseKey,
+		SSECustomerKeyMD5:    sseKeyMD5,
+	})
+	if err != nil {
+		if isS3NotFoundError(err) {
+			return nil, "", nil
+		}
+		return nil, "", fmt.Errorf("failed to read the state from 's3://%s/%s': %w", bucket, key, err)
+	}
+	defer out.Body.Close()
+
+	data, err := io.ReadAll(out.Body)
+	return data, aws.ToString(out.ETag), err
+}
+
+// loadTerraformStateS3Config loads the AWS config with the region, profile, credentials and the role of the `s3` backend
+func loadTerraformStateS3Config(ctx context.Context, backend map[string]any, env map[string]string) (aws.Config, error) {
+	var loadOptions []func(*awsconfig.LoadOptions) error
+	if region := getBackendString(backend, "region", getEnv(env, "AWS_REGION")); region != "" {
+		loadOptions = append(loadOptions, awsconfig.WithRegion(region))
+	}
+	if profile := getBackendString(backend, "profile", getEnv(env, "AWS_PROFILE")); profile != "" {
+		loadOptions = append(loadOptions, awsconfig.WithSharedConfigProfile(profile))
+	}
+	if files := getBackendStrings(backend, "shared_config_files"); len(files) > 0 {
+		loadOptions = append(loadOptions, awsconfig.WithSharedConfigFiles(files))
+	}
+	if files := getBackendStrings(backend, "shared_credentials_files"); len(files) > 0 {
+		loadOptions = append(loadOptions, awsconfig.WithSharedCredentialsFiles(files))
+	}
+	if accessKey := getBackendString(backend, "access_key", ""); accessKey != "" {
+		loadOptions = append(loadOptions, awsconfig.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(accessKey, getBackendString(backend, "secret_key", ""), getBackendString(backend, "token", "")),
+		))
+	}
+
+	awsConfig, err := awsconfig.LoadDefaultConfig(ctx, loadOptions...)
+	if err != nil {
+		return aws.Config{}, err
+	}
+
+	// The role can be specified in the `assume_role` block, or in the deprecated `role_arn`, `external_id` and `session_name` attributes
+	assumeRole := map[string]any{}
+	switch v := backend["assume_role"].(type) {
+	case nil:
+	case map[string]any:
+		assumeRole = v
+	default:
+		return aws.Config{}, fmt.Errorf("%w: the 'assume_role' option of the 's3' backend must be a map", errTerraformStateBackendNotSupported)
+	}
+
+	for _, option := range terraformStateS3AssumeRoleUnsupportedOptions {
+		if _, ok := assumeRole[option]; ok {
+			return aws.Config{}, fmt.Errorf("%w: the 'assume_role.%s' option of the 's3' backend", errTerraformStateBackendNotSupported, option)
+		}
+	}
+
+	roleArn := getBackendString(assumeRole, "role_arn", getBackendString(backend, "role_arn", ""))
+	if roleArn == "" {
+		return awsConfig, nil
+	}
+
+	externalID := getBackendString(assumeRole, "external_id", getBackendString(backend, "external_id", ""))
+	sessionName := getBackendString(assumeRole, "session_name", getBackendString(backend, "session_name", ""))
+
+	var duration time.Duration
+	if d := getBackendString(assumeRole, "duration", ""); d != "" {
+		if duration, err = time.ParseDuration(d); err != nil {
+			return aws.Config{}, fmt.Errorf("invalid 'assume_role.duration' of the 's3' backend: %w", err)
+		}
+	}
+
+	stsEndpoint := getBackendString(backend, "sts_endpoint", "")
+	if endpoints, ok := backend["endpoints"].(map[string]any); ok {
+		stsEndpoint = getBackendString(endpoints, "sts", stsEndpoint)
+	}
+
+	stsClient := sts.NewFromConfig(awsConfig, func(o *sts.Options) {
+		if stsEndpoint != "" {
+			o.BaseEndpoint = aws.String(stsEndpoint)
+		}
+	})
+
+	awsConfig.Credentials = aws.NewCredentialsCache(stscreds.NewAssumeRoleProvider(stsClient, roleArn, func(o *stscreds.AssumeRoleOptions) {
+		if externalID != "" {
+			o.ExternalID = aws.String(externalID)
+		}
+		if sessionName != "" {
+			o.RoleSessionName = sessionName
+		}
+		if duration > 0 {
+			o.Duration = duration
+		}
+	}))
+
+	return awsConfig, nil
+}
+
+// isS3NotFoundError checks if the S3 request failed because the object does not exist
+func isS3NotFoundError(err error) bool {
+	var respErr *awshttp.ResponseError
+	return errors.As(err, &respErr) && respErr.HTTPStatusCode() == http.StatusNotFound
+}
+
+// readTerraformStateGCS reads the state object from the `gcs` backend.
+// https://developer.hashicorp.com/terraform/language/settings/backends/gcs
+func readTerraformStateGCS(ctx context.Context, backend map[string]any, workspace string, probe bool) ([]byte, string, error) {
+	bucket := getBackendString(backend, "bucket", "")
+	if bucket == "" {
+		return nil, "", fmt.Errorf("%w: the 'gcs' backend requires 'bucket'", errTerraformStateBackendNotSupported)
+	}
+
+	if getBackendString(backend, "impersonate_service_account", "") != "" || getBackendString(backend, "encryption_key", "") != "" ||
+		getBackendString(backend, "kms_encryption_key", "") != "" {
+		return nil, "", fmt.Errorf("%w: service account impersonation and encryption keys of the 'gcs' backend", errTerraformStateBackendNotSupported)
+	}
+
+	var clientOptions []option.ClientOption
+	if credentials := getBackendString(backend, "credentials", ""); credentials != "" {
+		// The credentials can be a path to the service account key file or the contents of the file
+		if strings.HasPrefix(strings.TrimSpace(credentials), "{") {
+			clientOptions = append(clientOptions, option.WithCredentialsJSON([]byte(credentials)))
+		} else {
+			clientOptions = append(clientOptions, option.WithCredentialsFile(credentials))
+		}
+	}
+
+	client, err := storage.NewClient(ctx, clientOptions...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer client.Close()
+
+	object := path.Join(getBackendString(backend, "prefix", ""), workspace+".tfstate")
+
+	// The version of the state is the generation of the object
+	if probe {
+		attrs, err := client.Bucket(bucket).Object(object).Attrs(ctx)
+		if err != nil {
+			if errors.Is(err, storage.ErrObjectNotExist) {
+				return nil, "", nil
+			}
+			return nil, "", fmt.Errorf("failed to read the state from 'gs://%s/%s': %w", bucket, object, err)
+		}
+		return nil, strconv.FormatInt(attrs.Generation, 10), nil
+	}
+
+	reader, err := client.Bucket(bucket).Object(object).NewReader(ctx)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return nil, "", nil
+		}
+		return nil, "", fmt.Errorf("failed to read the state from 'gs://%s/%s': %w", bucket, object, err)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	return data, strconv.FormatInt(reader.Attrs.Generation, 10), err
+}
+
+// readTerraformStateAzurerm reads the state blob from the `azurerm` backend.
+// Authentication with the storage account access key, SAS token and Microsoft Entra ID (`use_azuread_auth`) is supported.
+// https://developer.hashicorp.com/terraform/language/settings/backends/azurerm
+func readTerraformStateAzurerm(ctx context.Context, backend map[string]any, workspace string, env map[string]string, probe bool) ([]byte, string, error) {
+	account := getBackendString(backend, "storage_account_name", "")
+	container := getBackendString(backend, "container_name", "")
+	key := getBackendString(backend, "key", "")
+	if account == "" || container == "" || key == "" {
+		return nil, "", fmt.Errorf("%w: the 'azurerm' backend requires 'storage_account_name', 'container_name' and 'key'", errTerraformStateBackendNotSupported)
+	}
+
+	if environment := getBackendString(backend, "environment", "public"); environment != "public" {
+		return nil, "", fmt.Errorf("%w: the '%s' Azure environment", errTerraformStateBackendNotSupported, environment)
+	}
+
+	if workspace != defaultTerraformWorkspace {
+		key = fmt.Sprintf("%senv:%s", key, workspace)
+	}
+
+	serviceURL := fmt.Sprintf("https://%s.blob.core.windows.net/", account)
+
+	var client *azblob.Client
+	var err error
+
+	accessKey := getBackendString(backend, "access_key", getEnv(env, "ARM_ACCESS_KEY"))
+	sasToken := getBackendString(backend, "sas_token", getEnv(env, "ARM_SAS_TOKEN"))
+
+	switch {
+	case accessKey != "":
+		credential, err2 := azblob.NewSharedKeyCredential(account, accessKey)
+		if err2 != nil {
+			return nil, "", err2
+		}
+		client, err = azblob.NewClientWithSharedKeyCredential(serviceURL, credential, nil)
+	case sasToken != "":
+		client, err = azblob.NewClientWithNoCredential(serviceURL+"?"+strings.TrimPrefix(sasToken, "?"), nil)
+	case getBackendBool(backend, "use_azuread_auth") || getEnv(env, "ARM_USE_AZUREAD") == "true":
+		credential, err2 := azidentity.NewDefaultAzureCredential(nil)
+		if err2 != nil {
+			return nil, "", err2
+		}
+		client, err = azblob.NewClient(serviceURL, credential, nil)
+	default:
+		// Terraform gets the access key of the storage account using the Azure Resource Manager API
+		return nil, "", fmt.Errorf("%w: the 'azurerm' backend without 'access_key', 'sas_token' or 'use_azuread_auth'", errTerraformStateBackendNotSupported)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	// The version of the state is the ETag of the blob
+	if probe {
+		props, err := client.ServiceClient().NewContainerClient(container).NewBlobClient(key).GetProperties(ctx, nil)
+		if err != nil {
+			if bloberror.HasCode(err, bloberror.BlobNotFound) {
+				return nil, "", nil
+			}
+			return nil, "", fmt.Errorf("failed to read the state from the blob '%s' in the container '%s': %w", key, container, err)
+		}
+		if props.ETag == nil {
+			return nil, "", nil
+		}
+		return nil, string(*props.ETag), nil
+	}
+
+	resp, err := client.DownloadStream(ctx, container, key, nil)
+	if err != nil {
+		if bloberror.HasCode(err, bloberror.BlobNotFound) {
+			return nil, "", nil
+		}
+		return nil, "", fmt.Errorf("failed to read the state from the blob '%s' in the container '%s': %w", key, container, err)
+	}
+	defer resp.Body.Close()
+
+	etag := ""
+	if resp.ETag != nil {
+		etag = string(*resp.ETag)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	return data, etag, err
+}
+
+// readTerraformStateHTTP reads the state from the `http` backend.
+// The backend does not support reading the version of the state without downloading the state.
+// https://developer.hashicorp.com/terraform/language/settings/backends/http
+func readTerraformStateHTTP(ctx context.Context, backend map[string]any, workspace string, env map[string]string) ([]byte, string, error) {
+	address := getBackendString(backend, "address", getEnv(env, "TF_HTTP_ADDRESS"))
+	if address == "" {
+		return nil, "", fmt.Errorf("%w: the 'http' backend requires 'address'", errTerraformStateBackendNotSupported)
+	}
+
+	// The `http` backend supports only the default workspace
+	if workspace != defaultTerraformWorkspace {
+		return nil, "", fmt.Errorf("%w: the workspace '%s' in the 'http' backend", errTerraformStateBackendNotSupported, workspace)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, address, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	username := getBackendString(backend, "username", getEnv(env, "TF_HTTP_USERNAME"))
+	password := getBackendString(backend, "password", getEnv(env, "TF_HTTP_PASSWORD"))
+	if username != "" || password != "" {
+		req.SetBasicAuth(username, password)
+	}
+
+	client := &http.Client{}
+	if getBackendBool(backend, "skip_cert_verification") {
+		client.Transport = &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, //nolint:gosec // The same as the `skip_cert_verification` setting of the backend
+		}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read the state from '%s': %w", address, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusNoContent:
+		return nil, "", nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, "", fmt.Errorf("failed to read the state from '%s': %s", address, resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// The backend API does not define the version of the state. Use the ETag if the server returns it, or the hash of the state
+	etag := resp.Header.Get("ETag")
+	if etag == "" {
+		sum := sha256.Sum256(data)
+		etag = hex.EncodeToString(sum[:])
+	}
+
+	return data, etag, nil
+}
+
+// getBackendString returns the string attribute of the backend configuration, or the default value if it's not set
+func getBackendString(backend map[string]any, name string, defaultValue string) string {
+	if v, ok := backend[name].(string); ok && v != "" {
+		return v
+	}
+	return defaultValue
+}
+
+// getBackendBool returns the boolean attribute of the backend configuration
+func getBackendBool(backend map[string]any, name string) bool {
+	v, _ := backend[name].(bool)
+	return v
+}
+
+// getBackendStrings returns the list of strings attribute of the backend configuration
+func getBackendStrings(backend map[string]any, name string) []string {
+	var values []string
+	if list, ok := backend[name].([]any); ok {
+		for _, v := range list {
+			if s, ok := v.(string); ok && s != "" {
+				values = append(values, s)
+			}
+		}
+	}
+	return values
+}
+
+// getEnv returns the environment variable from the component's `env` section or from the environment of the process
+func getEnv(env map[string]string, name string) string {
+	if v, ok := env[name]; ok {
+		return v
+	}
+	return os.Getenv(name)
+}
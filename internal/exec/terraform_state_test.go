@@ -0,0 +1,250 @@
+package exec
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudposse/atmos/pkg/schema"
+)
+
+const testTerraformState = `{
+  "version": 4,
+  "terraform_version": "1.9.8",
+  "serial": 3,
+  "lineage": "f0d4c9a6-55a7-c8b2-6e4c-0f3f0b2a5c11",
+  "outputs": {
+    "vpc_id": {"value": "vpc-123", "type": "string"},
+    "private_subnet_ids": {"value": ["subnet-1", "subnet-2"], "type": ["list", "string"]},
+    "nat": {"value": {"enabled": true, "count": 2}, "type": ["object", {"enabled": "bool", "count": "number"}]},
+    "password": {"value": "secret", "type": "string", "sensitive": true}
+  },
+  "resources": []
+}`
+
+func TestParseTerraformStateOutputs(t *testing.T) {
+	outputs, err := parseTerraformStateOutputs([]byte(testTerraformState))
+	require.NoError(t, err)
+	assert.Equal(t, "vpc-123", outputs["vpc_id"])
+	assert.Equal(t, []any{"subnet-1", "subnet-2"}, outputs["private_subnet_ids"])
+	assert.Equal(t, map[string]any{"enabled": true, "count": float64(2)}, outputs["nat"])
+	assert.Equal(t, "secret", outputs["password"])
+
+	_, err = parseTerraformStateOutputs([]byte(`{"version": 3, "modules": []}`))
+	assert.ErrorIs(t, err, errTerraformStateBackendNotSupported)
+}
+
+func TestReadTerraformOutputsFromStateLocal(t *testing.T) {
+	componentPath := t.TempDir()
+	atmosConfig := &schema.AtmosConfiguration{}
+
+	sections := map[string]any{
+		"backend_type":   "local",
+		"backend":        map[string]any{},
+		"workspace":      "plat-ue2-dev",
+		"component_info": map[string]any{"component_path": componentPath},
+	}
+
+	// The component has not been provisioned
+	outputs, version, err := readTerraformOutputsFromState(atmosConfig, "vpc", "plat-ue2-dev", sections)
+	require.NoError(t, err)
+	assert.Empty(t, outputs)
+	assert.Equal(t, terraformStateAbsentVersion, version)
+
+	stateDir := filepath.Join(componentPath, "terraform.tfstate.d", "plat-ue2-dev")
+	require.NoError(t, os.MkdirAll(stateDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(stateDir, "terraform.tfstate"), []byte(testTerraformState), 0o644))
+
+	outputs, version, err = readTerraformOutputsFromState(atmosConfig, "vpc", "plat-ue2-dev", sections)
+	require.NoError(t, err)
+	assert.Equal(t, "vpc-123", outputs["vpc_id"])
+	assert.Equal(t, "f0d4c9a6-55a7-c8b2-6e4c-0f3f0b2a5c11:3", version)
+
+	// The state of the default workspace in the custom path
+	sections["workspace"] = "default"
+	sections["backend"] = map[string]any{"path": "state/vpc.tfstate"}
+	require.NoError(t, os.MkdirAll(filepath.Join(componentPath, "state"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(componentPath, "state", "vpc.tfstate"), []byte(testTerraformState), 0o644))
+
+	outputs, _, err = readTerraformOutputsFromState(atmosConfig, "vpc", "plat-ue2-dev", sections)
+	require.NoError(t, err)
+	assert.Equal(t, []any{"subnet-1", "subnet-2"}, outputs["private_subnet_ids"])
+
+	// Unsupported backends
+	sections["backend_type"] = "remote"
+	_, _, err = readTerraformOutputsFromState(atmosConfig, "vpc", "plat-ue2-dev", sections)
+	assert.ErrorIs(t, err, errTerraformStateBackendNotSupported)
+}
+
+// The base64-encoded 256-bit customer-provided key of the S3 objects and its MD5 digest
+const (
+	testSSECustomerKey    = "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="
+	testSSECustomerKeyMD5 = "hRasmdxgYDKV3nvbahU1MA=="
+)
+
+const testAssumeRoleResponse = `<AssumeRoleResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleResult>
+    <Credentials>
+      <AccessKeyId>assumed</AccessKeyId>
+      <SecretAccessKey>secret</SecretAccessKey>
+      <SessionToken>token</SessionToken>
+      <Expiration>2099-01-01T00:00:00Z</Expiration>
+    </Credentials>
+    <AssumedRoleUser>
+      <Arn>arn:aws:sts::123456789012:assumed-role/terraform/atmos</Arn>
+      <AssumedRoleId>AROA123456789012:atmos</AssumedRoleId>
+    </AssumedRoleUser>
+  </AssumeRoleResult>
+</AssumeRoleResponse>`
+
+func TestReadTerraformOutputsFromStateS3(t *testing.T) {
+	// S3-compatible stand-in that serves the state objects with path-style addressing
+	objects := map[string]string{
+		"/tfstate/env:/plat-ue2-dev/vpc/terraform.tfstate":       testTerraformState,
+		"/tfstate/env:/plat-ue2-dev/encrypted/terraform.tfstate": testTerraformState,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		authorization := r.Header.Get("Authorization")
+		if !strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=test/") && !strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=assumed/") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		// The objects encrypted with a customer-provided key (SSE-C) can only be read with the key
+		if strings.Contains(r.URL.Path, "encrypted") && (r.Header.Get("X-Amz-Server-Side-Encryption-Customer-Algorithm") != "AES256" ||
+			r.Header.Get("X-Amz-Server-Side-Encryption-Customer-Key-Md5") != testSSECustomerKeyMD5) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body, ok := objects[r.URL.Path]
+		if !ok {
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`))
+			return
+		}
+		w.Header().Set("ETag", `"5d41402abc4b2a76b9719d911017c592"`)
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte(body))
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(t.TempDir(), "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+
+	sections := map[string]any{
+		"backend_type": "s3",
+		"backend": map[string]any{
+			"bucket":         "tfstate",
+			"key":            "vpc/terraform.tfstate",
+			"region":         "us-east-2",
+			"use_path_style": true,
+			"endpoints":      map[string]any{"s3": server.URL},
+		},
+		"workspace": "plat-ue2-dev",
+	}
+
+	outputs, version, err := readTerraformOutputsFromState(&schema.AtmosConfiguration{}, "vpc", "plat-ue2-dev", sections)
+	require.NoError(t, err)
+	assert.Equal(t, "vpc-123", outputs["vpc_id"])
+	assert.Equal(t, map[string]any{"enabled": true, "count": float64(2)}, outputs["nat"])
+	assert.Equal(t, `"5d41402abc4b2a76b9719d911017c592"`, version)
+
+	// The version of the state is read with a `HeadObject` request
+	version, err = getTerraformStateVersion("vpc", "plat-ue2-dev", sections)
+	require.NoError(t, err)
+	assert.Equal(t, `"5d41402abc4b2a76b9719d911017c592"`, version)
+
+	// The component has not been provisioned in the stack
+	sections["workspace"] = "plat-ue2-prod"
+	outputs, version, err = readTerraformOutputsFromState(&schema.AtmosConfiguration{}, "vpc", "plat-ue2-prod", sections)
+	require.NoError(t, err)
+	assert.Empty(t, outputs)
+	assert.Equal(t, terraformStateAbsentVersion, version)
+
+	// Server-side encryption with a customer-provided key
+	sections["workspace"] = "plat-ue2-dev"
+	backend := sections["backend"].(map[string]any)
+	backend["key"] = "encrypted/terraform.tfstate"
+	backend["sse_customer_key"] = testSSECustomerKey
+	outputs, _, err = readTerraformOutputsFromState(&schema.AtmosConfiguration{}, "vpc", "plat-ue2-dev", sections)
+	require.NoError(t, err)
+	assert.Equal(t, "vpc-123", outputs["vpc_id"])
+
+	// The role in the `assume_role` block is assumed with the STS endpoint of the backend
+	sts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.Form.Get("Action") != "AssumeRole" ||
+			r.Form.Get("ExternalId") != "atmos-external-id" || r.Form.Get("RoleSessionName") != "atmos" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "text/xml")
+		_, _ = w.Write([]byte(testAssumeRoleResponse))
+	}))
+	defer sts.Close()
+
+	delete(backend, "sse_customer_key")
+	backend["key"] = "vpc/terraform.tfstate"
+	backend["endpoints"] = map[string]any{"s3": server.URL, "sts": sts.URL}
+	backend["assume_role"] = map[string]any{
+		"role_arn":     "arn:aws:iam::123456789012:role/terraform",
+		"external_id":  "atmos-external-id",
+		"session_name": "atmos",
+	}
+	outputs, _, err = readTerraformOutputsFromState(&schema.AtmosConfiguration{}, "vpc", "plat-ue2-dev", sections)
+	require.NoError(t, err)
+	assert.Equal(t, "vpc-123", outputs["vpc_id"])
+
+	// The backend options that can't be handled fall back to `terraform output`
+	backend["assume_role"].(map[string]any)["policy"] = "{}"
+	_, _, err = readTerraformOutputsFromState(&schema.AtmosConfiguration{}, "vpc", "plat-ue2-dev", sections)
+	assert.ErrorIs(t, err, errTerraformStateBackendNotSupported)
+
+	delete(backend, "assume_role")
+	backend["custom_ca_bundle"] = "/etc/ssl/ca.pem"
+	_, _, err = readTerraformOutputsFromState(&schema.AtmosConfiguration{}, "vpc", "plat-ue2-dev", sections)
+	assert.ErrorIs(t, err, errTerraformStateBackendNotSupported)
+}
+
+func TestReadTerraformOutputsFromStateHTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != "atmos" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/state/vpc" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(testTerraformState))
+	}))
+	defer server.Close()
+
+	sections := map[string]any{
+		"backend_type": "http",
+		"backend":      map[string]any{"address": server.URL + "/state/vpc", "username": "atmos"},
+		"env":          map[string]any{"TF_HTTP_PASSWORD": "secret"},
+	}
+
+	outputs, _, err := readTerraformOutputsFromState(&schema.AtmosConfiguration{}, "vpc", "plat-ue2-dev", sections)
+	require.NoError(t, err)
+	assert.Equal(t, "vpc-123", outputs["vpc_id"])
+
+	sections["env"] = map[string]any{"TF_HTTP_PASSWORD": "wrong"}
+	_, _, err = readTerraformOutputsFromState(&schema.AtmosConfiguration{}, "vpc", "plat-ue2-dev", sections)
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, errTerraformStateBackendNotSupported)
+}
@@ -3,7 +3,9 @@ package exec
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/hashicorp/terraform-config-inspect/tfconfig"
@@ -50,8 +52,30 @@ var commonFlags = []string{
 	cfg.LogsLevelFlag,
 	cfg.LogsFileFlag,
 	cfg.QueryFlag,
+	cfg.WorkspaceLockTimeoutFlag,
 }
 
+// `multiComponentFlags` are the flags to execute the command for multiple components.
+// They are only removed from the arg list for the commands in `multiComponentCommands`, the other commands pass them to the underlying tool
+var multiComponentFlags = []string{
+	cfg.ComponentsFlag,
+	cfg.MaxConcurrencyFlag,
+	cfg.RefFlag,
+	cfg.SHAFlag,
+	cfg.RepoPathFlag,
+	cfg.SSHKeyFlag,
+	cfg.SSHKeyPasswordFlag,
+}
+
+// `planSummaryFlags` are the flags for the summary of `terraform plan`
+var planSummaryFlags = []string{
+	cfg.PlanSummaryFormatFlag,
+	cfg.PlanSummaryFileFlag,
+}
+
+// `multiComponentCommands` are the terraform commands that can be executed for multiple components
+var multiComponentCommands = []string{"plan", "apply", "deploy", "destroy"}
+
 // ProcessComponentConfig processes component config sections
 func ProcessComponentConfig(
 	configAndStacksInfo *schema.ConfigAndStacksInfo,
@@ -241,6 +265,21 @@ func ProcessCommandLineArgs(
 	configAndStacksInfo.LogsFile = argsAndFlagsInfo.LogsFile
 	configAndStacksInfo.SettingsListMergeStrategy = argsAndFlagsInfo.SettingsListMergeStrategy
 	configAndStacksInfo.Query = argsAndFlagsInfo.Query
+	configAndStacksInfo.All = argsAndFlagsInfo.All
+	configAndStacksInfo.Components = argsAndFlagsInfo.Components
+	configAndStacksInfo.MaxConcurrency = argsAndFlagsInfo.MaxConcurrency
+	configAndStacksInfo.Affected = argsAndFlagsInfo.Affected
+	configAndStacksInfo.Ref = argsAndFlagsInfo.Ref
+	configAndStacksInfo.SHA = argsAndFlagsInfo.SHA
+	configAndStacksInfo.RepoPath = argsAndFlagsInfo.RepoPath
+	configAndStacksInfo.SSHKeyPath = argsAndFlagsInfo.SSHKeyPath
+	configAndStacksInfo.SSHKeyPassword = argsAndFlagsInfo.SSHKeyPassword
+	configAndStacksInfo.CloneTargetRef = argsAndFlagsInfo.CloneTargetRef
+	configAndStacksInfo.IncludeDependents = argsAndFlagsInfo.IncludeDependents
+	configAndStacksInfo.PlanSummary = argsAndFlagsInfo.PlanSummary
+	configAndStacksInfo.PlanSummaryFormat = argsAndFlagsInfo.PlanSummaryFormat
+	configAndStacksInfo.PlanSummaryFile = argsAndFlagsInfo.PlanSummaryFile
+	configAndStacksInfo.WorkspaceLockTimeout = argsAndFlagsInfo.WorkspaceLockTimeout
 
 	flags := cmd.Flags()
 
@@ -663,6 +702,23 @@ func processArgsAndFlags(
 		return info, nil
 	}
 
+	// The flags to execute the command for multiple components and the flags for the plan summary
+	// are only parsed for the commands that support them
+	subCommand := ""
+	if len(inputArgsAndFlags) > 0 {
+		subCommand = inputArgsAndFlags[0]
+	}
+	multiComponentCommand := componentType == "terraform" && u.SliceContainsString(multiComponentCommands, subCommand)
+	planSummaryCommand := componentType == "terraform" && subCommand == "plan"
+
+	flagsToRemove := commonFlags
+	if multiComponentCommand {
+		flagsToRemove = append(slices.Clone(flagsToRemove), multiComponentFlags...)
+	}
+	if planSummaryCommand {
+		flagsToRemove = append(slices.Clone(flagsToRemove), planSummaryFlags...)
+	}
+
 	// https://github.com/roboll/helmfile#cli-reference
 	var globalOptionsFlagIndex int
 
@@ -974,6 +1030,91 @@ func processArgsAndFlags(
 			info.Query = parts[1]
 		}
 
+		if multiComponentCommand && arg == cfg.ComponentsFlag {
+			if len(inputArgsAndFlags) <= (i + 1) {
+				return info, fmt.Errorf("invalid flag: %s", arg)
+			}
+			info.Components = splitComponentsFlag(inputArgsAndFlags[i+1])
+		} else if multiComponentCommand && strings.HasPrefix(arg+"=", cfg.ComponentsFlag) {
+			parts := strings.Split(arg, "=")
+			if len(parts) != 2 {
+				return info, fmt.Errorf("invalid flag: %s", arg)
+			}
+			info.Components = splitComponentsFlag(parts[1])
+		}
+
+		if multiComponentCommand && arg == cfg.MaxConcurrencyFlag {
+			if len(inputArgsAndFlags) <= (i + 1) {
+				return info, fmt.Errorf("invalid flag: %s", arg)
+			}
+			maxConcurrency, err := strconv.Atoi(inputArgsAndFlags[i+1])
+			if err != nil || maxConcurrency < 1 {
+				return info, fmt.Errorf("invalid flag: %s %s. The value must be a positive integer", arg, inputArgsAndFlags[i+1])
+			}
+			info.MaxConcurrency = maxConcurrency
+		} else if multiComponentCommand && strings.HasPrefix(arg+"=", cfg.MaxConcurrencyFlag) {
+			parts := strings.Split(arg, "=")
+			if len(parts) != 2 {
+				return info, fmt.Errorf("invalid flag: %s", arg)
+			}
+			maxConcurrency, err := strconv.Atoi(parts[1])
+			if err != nil || maxConcurrency < 1 {
+				return info, fmt.Errorf("invalid flag: %s. The value must be a positive integer", arg)
+			}
+			info.MaxConcurrency = maxConcurrency
+		}
+
+		stringFlags := map[string]*string{
+			// The timeout to wait for the lock of the Terraform workspace
+			cfg.WorkspaceLockTimeoutFlag: &info.WorkspaceLockTimeout,
+		}
+		if multiComponentCommand {
+			// The flags to select the affected components (the same flags as in `atmos describe affected`)
+			stringFlags[cfg.RefFlag] = &info.Ref
+			stringFlags[cfg.SHAFlag] = &info.SHA
+			stringFlags[cfg.RepoPathFlag] = &info.RepoPath
+			stringFlags[cfg.SSHKeyFlag] = &info.SSHKeyPath
+			stringFlags[cfg.SSHKeyPasswordFlag] = &info.SSHKeyPassword
+		}
+		if planSummaryCommand {
+			// The flags for the summary of `terraform plan`
+			stringFlags[cfg.PlanSummaryFormatFlag] = &info.PlanSummaryFormat
+			stringFlags[cfg.PlanSummaryFileFlag] = &info.PlanSummaryFile
+		}
+
+		for flag, value := range stringFlags {
+			if arg == flag {
+				if len(inputArgsAndFlags) <= (i + 1) {
+					return info, fmt.Errorf("invalid flag: %s", arg)
+				}
+				*value = inputArgsAndFlags[i+1]
+			} else if strings.HasPrefix(arg, flag+"=") {
+				*value = strings.TrimPrefix(arg, flag+"=")
+			}
+		}
+
+		// Boolean flags, only the flag itself is removed from the args
+		if multiComponentCommand {
+			switch arg {
+			case cfg.AllFlag:
+				info.All = true
+				indexesToRemove = append(indexesToRemove, i)
+			case cfg.AffectedFlag:
+				info.Affected = true
+				indexesToRemove = append(indexesToRemove, i)
+			case cfg.CloneTargetRefFlag:
+				info.CloneTargetRef = true
+				indexesToRemove = append(indexesToRemove, i)
+			case cfg.IncludeDependentsFlag:
+				info.IncludeDependents = true
+				indexesToRemove = append(indexesToRemove, i)
+			}
+		}
+		if planSummaryCommand && arg == cfg.PlanSummaryFlag {
+			info.PlanSummary = true
+			indexesToRemove = append(indexesToRemove, i)
+		}
+
 		if arg == cfg.FromPlanFlag {
 			info.UseTerraformPlan = true
 		}
@@ -990,7 +1131,7 @@ func processArgsAndFlags(
 			info.NeedHelp = true
 		}
 
-		for _, f := range commonFlags {
+		for _, f := range flagsToRemove {
 			if arg == f {
 				indexesToRemove = append(indexesToRemove, i)
 				indexesToRemove = append(indexesToRemove, i+1)
@@ -1024,6 +1165,21 @@ func processArgsAndFlags(
 		return info, nil
 	}
 
+	// For commands that select the components with flags (e.g. `atmos terraform plan --all` or `atmos terraform plan --affected`),
+	// there is no component argument
+	if multiComponentCommand && len(additionalArgsAndFlags) > 0 && (info.All || info.Affected || len(info.Components) > 0 || info.Query != "") {
+		info.SubCommand = additionalArgsAndFlags[0]
+		args := additionalArgsAndFlags[1:]
+		if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+			return info, fmt.Errorf("the component argument '%s' can't be used with the '%s', '%s', '%s' or '%s' flags",
+				args[0], cfg.AllFlag, cfg.AffectedFlag, cfg.ComponentsFlag, cfg.QueryFlag)
+		}
+		if len(args) > 0 {
+			info.AdditionalArgsAndFlags = args
+		}
+		return info, nil
+	}
+
 	if len(additionalArgsAndFlags) > 1 {
 		twoWordsCommand := false
 
@@ -1097,6 +1253,17 @@ func processArgsAndFlags(
 	return info, nil
 }
 
+// splitComponentsFlag splits the comma-separated value of the `--components` flag
+func splitComponentsFlag(value string) []string {
+	var components []string
+	for _, c := range strings.Split(value, ",") {
+		if c = strings.TrimSpace(c); c != "" {
+			components = append(components, c)
+		}
+	}
+	return components
+}
+
 // generateComponentBackendConfig generates backend config for components
 func generateComponentBackendConfig(backendType string, backendConfig map[string]any, terraformWorkspace string) (map[string]any, error) {
 	// Generate backend config file for Terraform Cloud
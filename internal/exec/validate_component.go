@@ -132,7 +132,8 @@ func ValidateComponent(
 		}
 
 		for _, v := range validations {
-			if v.Disabled {
+			// The `opa-plan` policies are evaluated against the Terraform plan after `atmos terraform plan`
+			if v.Disabled || v.SchemaType == terraformPlanPolicySchemaType {
 				continue
 			}
 
@@ -193,8 +194,13 @@ func validateComponentInternal(
 	modulePaths []string,
 	timeoutSeconds int,
 ) (bool, error) {
-	if schemaType != "jsonschema" && schemaType != "opa" {
-		return false, fmt.Errorf("invalid schema type '%s'. Supported types: jsonschema, opa", schemaType)
+	if schemaType == terraformPlanPolicySchemaType {
+		return false, fmt.Errorf("the schema type '%s' is evaluated against the Terraform plan after 'atmos terraform plan' "+
+			"and can't be used to validate the component configuration", schemaType)
+	}
+
+	if schemaType != "jsonschema" && schemaType != "opa" && schemaType != "cue" {
+		return false, fmt.Errorf("invalid schema type '%s'. Supported types: jsonschema, opa, cue", schemaType)
 	}
 
 	// Check if the file pointed to by 'schemaPath' exists.
@@ -212,6 +218,10 @@ func validateComponentInternal(
 			{
 				filePath = filepath.Join(atmosConfig.BasePath, atmosConfig.Schemas.Opa.BasePath, schemaPath)
 			}
+		case "cue":
+			{
+				filePath = filepath.Join(atmosConfig.BasePath, atmosConfig.Schemas.Cue.BasePath, schemaPath)
+			}
 		}
 
 		if !u.FileExists(filePath) {
@@ -247,6 +257,13 @@ func validateComponentInternal(
 				return false, err
 			}
 		}
+	case "cue":
+		{
+			ok, err = ValidateWithCue(componentSection, filePath, schemaText)
+			if err != nil {
+				return false, err
+			}
+		}
 	case "opa_legacy":
 		{
 			ok, err = ValidateWithOpaLegacy(componentSection, filePath, schemaText, timeoutSeconds)
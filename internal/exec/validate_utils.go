@@ -8,11 +8,16 @@ import (
 	"strings"
 	"time"
 
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
+	cueerrors "cuelang.org/go/cue/errors"
+	cuejson "cuelang.org/go/encoding/json"
 	"github.com/open-policy-agent/opa/loader"
 	"github.com/open-policy-agent/opa/rego"
 	"github.com/open-policy-agent/opa/sdk"
 	opaTestServer "github.com/open-policy-agent/opa/sdk/test"
 	"github.com/pkg/errors"
+	"github.com/samber/lo"
 	"github.com/santhosh-tekuri/jsonschema/v5"
 
 	u "github.com/cloudposse/atmos/pkg/utils"
@@ -72,6 +77,26 @@ func ValidateWithOpa(
 	modulePaths []string,
 	timeoutSeconds int,
 ) (bool, error) {
+	ers, err := evaluateOpaErrors(data, schemaPath, modulePaths, timeoutSeconds)
+	if err != nil {
+		return false, err
+	}
+
+	if len(ers) > 0 {
+		return false, errors.New(strings.Join(u.SliceOfInterfacesToSliceOdStrings(ers), "\n"))
+	}
+
+	return true, nil
+}
+
+// evaluateOpaErrors evaluates the `data.atmos.errors` query of the provided OPA document using the data structure as input,
+// and returns the items of the `errors` output array
+func evaluateOpaErrors(
+	data any,
+	schemaPath string,
+	modulePaths []string,
+	timeoutSeconds int,
+) ([]any, error) {
 	// Set timeout for schema validation
 	if timeoutSeconds == 0 {
 		timeoutSeconds = 20
@@ -90,14 +115,14 @@ func ValidateWithOpa(
 	// Load the input document
 	j, err := u.ConvertToJSON(data)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 
 	var input any
 	dec := json.NewDecoder(bytes.NewBufferString(j))
 	dec.UseNumber()
 	if err = dec.Decode(&input); err != nil {
-		return false, err
+		return nil, err
 	}
 
 	// Construct a Rego object that can be prepared or evaluated.
@@ -111,7 +136,7 @@ func ValidateWithOpa(
 	// Create a prepared query that can be evaluated
 	query, err := r.PrepareForEval(ctx)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 
 	// Execute the prepared query
@@ -120,27 +145,24 @@ func ValidateWithOpa(
 		if err.Error() == "context deadline exceeded" {
 			err = errors.New(timeoutErrorMessage)
 		}
-		return false, err
+		return nil, err
 	}
 
 	if len(rs) < 1 {
-		return false, errors.New(invalidRegoPolicyErrorMessage)
+		return nil, errors.New(invalidRegoPolicyErrorMessage)
 	}
 
 	if len(rs[0].Expressions) < 1 {
-		return false, errors.New(invalidRegoPolicyErrorMessage)
+		return nil, errors.New(invalidRegoPolicyErrorMessage)
 	}
 
 	// Check the query evaluation result (if the `errors` output array has any items)
 	ers, ok := rs[0].Expressions[0].Value.([]any)
 	if !ok {
-		return false, errors.New(invalidRegoPolicyErrorMessage)
-	}
-	if len(ers) > 0 {
-		return false, errors.New(strings.Join(u.SliceOfInterfacesToSliceOdStrings(ers), "\n"))
+		return nil, errors.New(invalidRegoPolicyErrorMessage)
 	}
 
-	return true, nil
+	return ers, nil
 }
 
 // ValidateWithOpaLegacy validates the data structure using the provided OPA document
@@ -236,6 +258,66 @@ func ValidateWithOpaLegacy(
 
 // ValidateWithCue validates the data structure using the provided CUE document
 // https://cuelang.org/docs/integrations/go/#processing-cue-in-go
+// The data is unified with the schema, and all the errors (conflicting values, and missing or non-concrete values) are reported with their paths
 func ValidateWithCue(data any, schemaName string, schemaText string) (bool, error) {
-	return false, errors.New("validation using CUE is not supported yet")
+	// Convert the data to JSON to prevent the unsupported map[any]any data types
+	dataJson, err := u.ConvertToJSONFast(data)
+	if err != nil {
+		return false, err
+	}
+
+	ctx := cuecontext.New()
+
+	schema := ctx.CompileString(schemaText, cue.Filename(schemaName))
+	if schema.Err() != nil {
+		return false, fmt.Errorf("invalid CUE schema '%s': %w", schemaName, schema.Err())
+	}
+
+	dataExpr, err := cuejson.Extract("data", []byte(dataJson))
+	if err != nil {
+		return false, err
+	}
+
+	value := schema.Unify(ctx.BuildExpr(dataExpr))
+
+	// CUE does not report the missing (non-concrete) values if there are conflicting values, so check each value separately
+	errs := collectCueErrors(value)
+	errs = append(errs, cueerrors.Errors(value.Validate(cue.Concrete(true), cue.All()))...)
+
+	if len(errs) == 0 {
+		return true, nil
+	}
+
+	var messages []string
+	for _, e := range errs {
+		format, args := e.Msg()
+		message := fmt.Sprintf(format, args...)
+		if path := strings.Join(e.Path(), "."); path != "" {
+			message = path + ": " + message
+		}
+		messages = append(messages, message)
+	}
+
+	return false, errors.New(strings.Join(lo.Uniq(messages), "\n"))
+}
+
+// collectCueErrors validates each of the nested values of the CUE value, and returns all the errors
+func collectCueErrors(v cue.Value) []cueerrors.Error {
+	var errs []cueerrors.Error
+
+	if fields, err := v.Fields(); err == nil {
+		for fields.Next() {
+			errs = append(errs, collectCueErrors(fields.Value())...)
+		}
+		return errs
+	}
+
+	if items, err := v.List(); err == nil {
+		for items.Next() {
+			errs = append(errs, collectCueErrors(items.Value())...)
+		}
+		return errs
+	}
+
+	return cueerrors.Errors(v.Validate(cue.Concrete(true)))
 }
@@ -1,8 +1,6 @@
 package exec
 
 import (
-	"fmt"
-
 	"github.com/spf13/cobra"
 )
 
@@ -13,5 +11,30 @@ func ExecuteVendorPullCmd(cmd *cobra.Command, args []string) error {
 
 // ExecuteVendorDiffCmd executes `vendor diff` commands
 func ExecuteVendorDiffCmd(cmd *cobra.Command, args []string) error {
-	return fmt.Errorf("'atmos vendor diff' is not implemented yet")
+	return ExecuteVendorDiffCommand(cmd, args)
+}
+
+// ExecuteVendorVerifyCmd executes `vendor verify` commands
+func ExecuteVendorVerifyCmd(cmd *cobra.Command, args []string) error {
+	return ExecuteVendorVerifyCommand(cmd, args)
+}
+
+// ExecuteVendorUpdateCmd executes `vendor update` commands
+func ExecuteVendorUpdateCmd(cmd *cobra.Command, args []string) error {
+	return ExecuteVendorUpdateCommand(cmd, args)
+}
+
+// ExecuteVendorCacheListCmd executes `vendor cache list` commands
+func ExecuteVendorCacheListCmd(cmd *cobra.Command, args []string) error {
+	return ExecuteVendorCacheListCommand(cmd, args)
+}
+
+// ExecuteVendorCachePruneCmd executes `vendor cache prune` commands
+func ExecuteVendorCachePruneCmd(cmd *cobra.Command, args []string) error {
+	return ExecuteVendorCachePruneCommand(cmd, args)
+}
+
+// ExecuteVendorPushCmd executes `vendor push` commands
+func ExecuteVendorPushCmd(cmd *cobra.Command, args []string) error {
+	return ExecuteVendorPushCommand(cmd, args)
 }
@@ -0,0 +1,517 @@
+package exec
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/hashicorp/go-getter"
+	cp "github.com/otiai10/copy"
+	"github.com/spf13/cobra"
+
+	cfg "github.com/cloudposse/atmos/pkg/config"
+	"github.com/cloudposse/atmos/pkg/schema"
+	u "github.com/cloudposse/atmos/pkg/utils"
+)
+
+const (
+	vendorCacheContentDir   = "content"
+	vendorCacheMetadataFile = "metadata.yaml"
+	vendorCacheLockFileExt  = ".lock"
+)
+
+// vendorCacheEntry is the metadata of a source in the vendor download cache
+type vendorCacheEntry struct {
+	Key      string    `yaml:"key"`
+	Source   string    `yaml:"source"`
+	Resolved string    `yaml:"resolved"`
+	Size     int64     `yaml:"size"`
+	Created  time.Time `yaml:"created"`
+	LastUsed time.Time `yaml:"last_used"`
+}
+
+// ExecuteVendorCacheListCommand executes `atmos vendor cache list` commands
+func ExecuteVendorCacheListCommand(cmd *cobra.Command, args []string) error {
+	cacheDir, err := getVendorCacheDir()
+	if err != nil {
+		return err
+	}
+
+	entries, err := listVendorCacheEntries(cacheDir)
+	if err != nil {
+		return err
+	}
+
+	if len(entries) == 0 {
+		u.PrintMessage(fmt.Sprintf("The vendor cache '%s' is empty.", cacheDir))
+		return nil
+	}
+
+	u.PrintMessage(formatVendorCacheEntries(entries))
+	u.PrintMessage(fmt.Sprintf("\n%d sources in the vendor cache '%s'.", len(entries), cacheDir))
+
+	return nil
+}
+
+// ExecuteVendorCachePruneCommand executes `atmos vendor cache prune` commands
+func ExecuteVendorCachePruneCommand(cmd *cobra.Command, args []string) error {
+	olderThan, err := cmd.Flags().GetString("older-than")
+	if err != nil {
+		return err
+	}
+
+	var maxAge time.Duration
+	if olderThan != "" {
+		maxAge, err = time.ParseDuration(olderThan)
+		if err != nil {
+			return fmt.Errorf("invalid '--older-than' flag '%s': %w", olderThan, err)
+		}
+	}
+
+	cacheDir, err := getVendorCacheDir()
+	if err != nil {
+		return err
+	}
+
+	pruned, err := pruneVendorCache(cacheDir, maxAge)
+	if err != nil {
+		return err
+	}
+
+	var size int64
+	for _, entry := range pruned {
+		size += entry.Size
+	}
+
+	u.PrintMessage(fmt.Sprintf("Removed %d sources (%s) from the vendor cache '%s'.", len(pruned), formatVendorCacheSize(size), cacheDir))
+
+	return nil
+}
+
+// getVendorCacheDir returns the directory of the vendor download cache.
+// It is located in `$XDG_CACHE_HOME/atmos/vendor`, or in the user cache directory if `XDG_CACHE_HOME` is not set
+func getVendorCacheDir() (string, error) {
+	return cfg.GetAtmosCacheDir("vendor")
+}
+
+// vendorCacheKey returns the content address of a source in the vendor cache.
+// The key is the hash of the normalized source URI and the resolved revision (Git commit SHA or OCI image digest)
+func vendorCacheKey(sourceType pkgType, uri string, resolved string) string {
+	h := sha256.New()
+	h.Write([]byte(sourceType.String()))
+	h.Write([]byte{0})
+	h.Write([]byte(normalizeVendorCacheURI(uri)))
+	h.Write([]byte{0})
+	h.Write([]byte(resolved))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// normalizeVendorCacheURI removes the credentials and the parameters that do not affect the downloaded files from the source URI,
+// and sorts the query parameters.
+// The same source referenced with different tokens or clone depths is cached only once, and the tokens are never written to the cache
+func normalizeVendorCacheURI(uri string) string {
+	forced := ""
+	if i := strings.Index(uri, "::"); i > 0 && !strings.Contains(uri[:i], "/") {
+		forced = uri[:i+2]
+		uri = uri[i+2:]
+	}
+
+	if strings.HasPrefix(forced, "oci") || strings.HasPrefix(uri, "oci://") {
+		return forced + uri
+	}
+
+	parsed, err := url.Parse(uri)
+	if err != nil || parsed.Host == "" {
+		return forced + uri
+	}
+
+	parsed.User = nil
+	parsed.Host = strings.ToLower(parsed.Host)
+
+	query := parsed.Query()
+	query.Del("depth")
+	parsed.RawQuery = query.Encode()
+
+	return forced + parsed.String()
+}
+
+// downloadVendorSource downloads a remote or OCI source into the destination directory.
+// If the revision of the source can be resolved, the download is stored in the vendor cache, and the next downloads of the same
+// source and revision are copied from the cache.
+// The whole source is cached without the subdirectory (`//subdir`), so the same repository and revision vendored
+// from several subdirectories is downloaded only once
+func downloadVendorSource(atmosConfig schema.AtmosConfiguration, sourceType pkgType, uri string, destDir string) error {
+	downloadSource := func(uri string, dir string) error {
+		return retryVendorDownload(atmosConfig, normalizeVendorCacheURI(uri), func(attempt int) error {
+			if attempt > 0 {
+				// Remove the files left by the failed attempt
+				if err := os.RemoveAll(dir); err != nil {
+					return err
+				}
+			}
+
+			switch sourceType {
+			case pkgTypeRemote:
+				return GoGetterGet(atmosConfig, uri, dir, getter.ClientModeAny, 10*time.Minute)
+			case pkgTypeOci:
+				if err := os.MkdirAll(dir, 0o755); err != nil {
+					return err
+				}
+				return processOciImage(atmosConfig, strings.TrimPrefix(uri, "oci://"), dir)
+			default:
+				return fmt.Errorf("the source type '%s' can't be downloaded", sourceType.String())
+			}
+		})
+	}
+
+	// Sources that don't have an immutable revision (e.g. HTTP archives or branches that can't be resolved) are not cached
+	resolved, err := resolveVendorSourceRevision(sourceType, uri)
+	if err != nil {
+		u.LogDebug(fmt.Sprintf("Not using the vendor cache for '%s': %v", uri, err))
+		return downloadSource(uri, destDir)
+	}
+	if resolved == "" {
+		return downloadSource(uri, destDir)
+	}
+
+	cacheDir, err := getVendorCacheDir()
+	if err != nil {
+		u.LogWarning(fmt.Sprintf("Not using the vendor cache: %v", err))
+		return downloadSource(uri, destDir)
+	}
+
+	source, subDir := uri, ""
+	if sourceType == pkgTypeRemote {
+		source, subDir = getter.SourceDirSubdir(uri)
+	}
+
+	download := func(dir string) error {
+		return downloadSource(source, dir)
+	}
+
+	return getVendorCachedSource(cacheDir, vendorCacheKey(sourceType, source, resolved), normalizeVendorCacheURI(source), resolved, subDir, destDir, download)
+}
+
+// getVendorCachedSource copies the cached source (or the subdirectory of the cached source if `subDir` is not empty)
+// into the destination directory.
+// On a cache miss, the source is downloaded into the cache first.
+// The cache entry is locked with a file lock, so the cache can be shared by concurrent Atmos processes
+func getVendorCachedSource(
+	cacheDir string,
+	key string,
+	source string,
+	resolved string,
+	subDir string,
+	destDir string,
+	download func(dir string) error,
+) error {
+	if err := os.MkdirAll(cacheDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create the vendor cache directory: %w", err)
+	}
+
+	entryDir := filepath.Join(cacheDir, key)
+
+	return withVendorCacheLock(entryDir+vendorCacheLockFileExt, func() error {
+		entry, exists, err := readVendorCacheEntry(entryDir)
+		if err != nil {
+			u.LogWarning(fmt.Sprintf("Ignoring the invalid vendor cache entry '%s': %v", entryDir, err))
+			exists = false
+		}
+
+		if exists {
+			u.LogTrace(fmt.Sprintf("Using the vendor cache for '%s' (%s)", source, resolved))
+		} else {
+			u.LogTrace(fmt.Sprintf("Downloading '%s' (%s) into the vendor cache", source, resolved))
+
+			entry, err = addVendorCacheEntry(cacheDir, entryDir, key, source, resolved, download)
+			if err != nil {
+				return err
+			}
+		}
+
+		contentDir := filepath.Join(entryDir, vendorCacheContentDir)
+		if subDir != "" {
+			// The subdirectory can be a glob pattern, the same as in go-getter
+			contentDir, err = getter.SubdirGlob(contentDir, subDir)
+			if err != nil {
+				return fmt.Errorf("failed to find the subdirectory '%s' in '%s': %w", subDir, source, err)
+			}
+		}
+
+		copyOptions := cp.Options{
+			PreserveTimes: false,
+			PreserveOwner: false,
+			OnSymlink:     func(src string) cp.SymlinkAction { return cp.Deep },
+		}
+		if err := cp.Copy(contentDir, destDir, copyOptions); err != nil {
+			return fmt.Errorf("failed to copy '%s' from the vendor cache: %w", source, err)
+		}
+
+		entry.LastUsed = time.Now().UTC()
+		return u.WriteToFileAsYAML(filepath.Join(entryDir, vendorCacheMetadataFile), entry, 0o644)
+	})
+}
+
+// addVendorCacheEntry downloads the source into a staging directory, and moves it into the cache when the download succeeds,
+// so a failed or interrupted download never leaves a partial cache entry
+func addVendorCacheEntry(
+	cacheDir string,
+	entryDir string,
+	key string,
+	source string,
+	resolved string,
+	download func(dir string) error,
+) (vendorCacheEntry, error) {
+	stagingDir, err := os.MkdirTemp(cacheDir, key+".tmp-*")
+	if err != nil {
+		return vendorCacheEntry{}, fmt.Errorf("failed to create the vendor cache directory: %w", err)
+	}
+	defer func() {
+		if err := os.RemoveAll(stagingDir); err != nil {
+			u.LogWarning(err.Error())
+		}
+	}()
+
+	contentDir := filepath.Join(stagingDir, vendorCacheContentDir)
+	if err := download(contentDir); err != nil {
+		return vendorCacheEntry{}, err
+	}
+
+	// The Git metadata is never copied to the vendor targets
+	if err := os.RemoveAll(filepath.Join(contentDir, ".git")); err != nil {
+		return vendorCacheEntry{}, err
+	}
+
+	size, err := getVendorCacheDirSize(contentDir)
+	if err != nil {
+		return vendorCacheEntry{}, err
+	}
+
+	now := time.Now().UTC()
+	entry := vendorCacheEntry{
+		Key:      key,
+		Source:   source,
+		Resolved: resolved,
+		Size:     size,
+		Created:  now,
+		LastUsed: now,
+	}
+
+	if err := u.WriteToFileAsYAML(filepath.Join(stagingDir, vendorCacheMetadataFile), entry, 0o644); err != nil {
+		return vendorCacheEntry{}, err
+	}
+
+	// Remove an invalid entry left by an older version or a crashed process
+	if err := os.RemoveAll(entryDir); err != nil {
+		return vendorCacheEntry{}, err
+	}
+
+	if err := os.Rename(stagingDir, entryDir); err != nil {
+		return vendorCacheEntry{}, fmt.Errorf("failed to add '%s' to the vendor cache: %w", source, err)
+	}
+
+	return entry, nil
+}
+
+// readVendorCacheEntry reads the metadata of the cache entry. It returns `false` if the entry does not exist
+func readVendorCacheEntry(entryDir string) (vendorCacheEntry, bool, error) {
+	metadataFile := filepath.Join(entryDir, vendorCacheMetadataFile)
+	if !u.FileExists(metadataFile) {
+		return vendorCacheEntry{}, false, nil
+	}
+
+	content, err := os.ReadFile(metadataFile)
+	if err != nil {
+		return vendorCacheEntry{}, false, err
+	}
+
+	entry, err := u.UnmarshalYAML[vendorCacheEntry](string(content))
+	if err != nil {
+		return vendorCacheEntry{}, false, err
+	}
+
+	if !u.FileOrDirExists(filepath.Join(entryDir, vendorCacheContentDir)) {
+		return vendorCacheEntry{}, false, fmt.Errorf("the cached files are missing")
+	}
+
+	return entry, true, nil
+}
+
+// listVendorCacheEntries returns the entries in the vendor cache sorted by the last used time (most recent first)
+func listVendorCacheEntries(cacheDir string) ([]vendorCacheEntry, error) {
+	dirEntries, err := os.ReadDir(cacheDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var entries []vendorCacheEntry
+	for _, d := range dirEntries {
+		if !d.IsDir() || strings.Contains(d.Name(), ".tmp-") {
+			continue
+		}
+
+		entry, exists, err := readVendorCacheEntry(filepath.Join(cacheDir, d.Name()))
+		if err != nil {
+			u.LogWarning(fmt.Sprintf("Ignoring the invalid vendor cache entry '%s': %v", d.Name(), err))
+			continue
+		}
+		if exists {
+			entries = append(entries, entry)
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].LastUsed.After(entries[j].LastUsed)
+	})
+
+	return entries, nil
+}
+
+// pruneVendorCache removes the entries that were not used for longer than `maxAge` from the vendor cache.
+// If `maxAge` is 0, all entries are removed.
+// The entries that are in use by other Atmos processes are skipped.
+// The invalid entries are always removed, and are not returned
+func pruneVendorCache(cacheDir string, maxAge time.Duration) ([]vendorCacheEntry, error) {
+	dirEntries, err := os.ReadDir(cacheDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var pruned []vendorCacheEntry
+	for _, d := range dirEntries {
+		if !d.IsDir() {
+			continue
+		}
+
+		entryDir := filepath.Join(cacheDir, d.Name())
+
+		// The staging directories of the downloads in progress are locked by the entry lock
+		key, _, _ := strings.Cut(d.Name(), ".tmp-")
+		lock := flock.New(filepath.Join(cacheDir, key+vendorCacheLockFileExt))
+
+		locked, err := lock.TryLock()
+		if err != nil {
+			return pruned, fmt.Errorf("error acquiring file lock: %w", err)
+		}
+		if !locked {
+			u.LogDebug(fmt.Sprintf("Skipping the vendor cache entry '%s' since it's in use", d.Name()))
+			continue
+		}
+
+		entry, exists, err := readVendorCacheEntry(entryDir)
+		valid := err == nil && exists
+		if valid && maxAge > 0 && time.Since(entry.LastUsed) < maxAge {
+			_ = lock.Unlock()
+			continue
+		}
+
+		removeErr := os.RemoveAll(entryDir)
+		_ = lock.Unlock()
+		if removeErr != nil {
+			return pruned, removeErr
+		}
+
+		// The lock files are kept, since removing a lock file that another process is waiting on would break the locking.
+		// The invalid entries and the staging directories are removed, but not reported
+		if key != d.Name() {
+			continue
+		}
+		if !valid {
+			u.LogDebug(fmt.Sprintf("Removed the invalid vendor cache entry '%s'", d.Name()))
+			continue
+		}
+		pruned = append(pruned, entry)
+	}
+
+	return pruned, nil
+}
+
+// withVendorCacheLock runs the function while holding an exclusive file lock
+func withVendorCacheLock(lockFile string, fn func() error) error {
+	lock := flock.New(lockFile)
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("error acquiring file lock: %w", err)
+	}
+	defer func() {
+		_ = lock.Unlock()
+	}()
+	return fn()
+}
+
+// getVendorCacheDirSize returns the total size of the files in the directory
+func getVendorCacheDirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
+
+// formatVendorCacheEntries formats the vendor cache entries as a plain text table
+func formatVendorCacheEntries(entries []vendorCacheEntry) string {
+	sourceWidth := len("SOURCE")
+	for _, entry := range entries {
+		sourceWidth = max(sourceWidth, len(entry.Source))
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%-12s  %-*s  %-19s  %10s  %s\n", "KEY", sourceWidth, "SOURCE", "RESOLVED", "SIZE", "LAST USED"))
+	for _, entry := range entries {
+		sb.WriteString(fmt.Sprintf("%-12s  %-*s  %-19s  %10s  %s\n",
+			shortenVendorCacheValue(entry.Key, 12),
+			sourceWidth,
+			entry.Source,
+			shortenVendorCacheValue(entry.Resolved, 19),
+			formatVendorCacheSize(entry.Size),
+			entry.LastUsed.Local().Format(time.DateTime),
+		))
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// shortenVendorCacheValue truncates the commit SHAs, digests and keys for display
+func shortenVendorCacheValue(value string, length int) string {
+	if len(value) <= length {
+		return value
+	}
+	return value[:length]
+}
+
+// formatVendorCacheSize formats the size in bytes in a human-readable form
+func formatVendorCacheSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
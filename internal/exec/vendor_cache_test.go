@@ -0,0 +1,136 @@
+package exec
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeVendorCacheURI(t *testing.T) {
+	tests := []struct {
+		uri      string
+		expected string
+	}{
+		{
+			uri:      "git::https://token@GitHub.com/cloudposse/terraform-aws-components.git//modules/vpc?ref=1.300.0&depth=1",
+			expected: "git::https://github.com/cloudposse/terraform-aws-components.git//modules/vpc?ref=1.300.0",
+		},
+		{
+			uri:      "https://github.com/cloudposse/terraform-aws-components.git//modules/vpc?ref=1.300.0",
+			expected: "https://github.com/cloudposse/terraform-aws-components.git//modules/vpc?ref=1.300.0",
+		},
+		{
+			uri:      "public.ecr.aws/cloudposse/components/terraform/stable/aws/vpc:latest",
+			expected: "public.ecr.aws/cloudposse/components/terraform/stable/aws/vpc:latest",
+		},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, normalizeVendorCacheURI(tt.uri))
+	}
+
+	assert.Equal(t,
+		vendorCacheKey(pkgTypeRemote, "git::https://a@github.com/org/repo.git?ref=v1", "abc"),
+		vendorCacheKey(pkgTypeRemote, "git::https://b@github.com/org/repo.git?ref=v1&depth=1", "abc"),
+	)
+	assert.NotEqual(t,
+		vendorCacheKey(pkgTypeRemote, "git::https://github.com/org/repo.git?ref=v1", "abc"),
+		vendorCacheKey(pkgTypeRemote, "git::https://github.com/org/repo.git?ref=v1", "def"),
+	)
+}
+
+func TestVendorCache(t *testing.T) {
+	cacheDir := t.TempDir()
+	key := vendorCacheKey(pkgTypeRemote, "github.com/org/repo.git?ref=v1", "abc")
+
+	downloads := 0
+	download := func(dir string) error {
+		downloads++
+		if err := os.MkdirAll(filepath.Join(dir, ".git"), 0o755); err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(dir, "main.tf"), []byte("locals {}\n"), 0o644)
+	}
+
+	// The first download fills the cache, the next ones are copied from the cache
+	for i := 0; i < 3; i++ {
+		destDir := filepath.Join(t.TempDir(), "dest")
+		err := getVendorCachedSource(cacheDir, key, "github.com/org/repo.git?ref=v1", "abc", "", destDir, download)
+		require.NoError(t, err)
+		assert.FileExists(t, filepath.Join(destDir, "main.tf"))
+		assert.NoDirExists(t, filepath.Join(destDir, ".git"))
+	}
+	assert.Equal(t, 1, downloads)
+
+	// A failed download does not leave a cache entry
+	failedKey := vendorCacheKey(pkgTypeRemote, "github.com/org/repo.git?ref=v2", "def")
+	err := getVendorCachedSource(cacheDir, failedKey, "github.com/org/repo.git?ref=v2", "def", "", t.TempDir(), func(dir string) error {
+		return errors.New("download failed")
+	})
+	assert.Error(t, err)
+
+	entries, err := listVendorCacheEntries(cacheDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, key, entries[0].Key)
+	assert.Equal(t, "abc", entries[0].Resolved)
+	assert.Equal(t, int64(len("locals {}\n")), entries[0].Size)
+
+	// An entry without the metadata is invalid, it's removed but not reported
+	invalidDir := filepath.Join(cacheDir, vendorCacheKey(pkgTypeRemote, "github.com/org/repo.git?ref=v3", "ghi"), vendorCacheContentDir)
+	require.NoError(t, os.MkdirAll(invalidDir, 0o755))
+
+	pruned, err := pruneVendorCache(cacheDir, time.Hour)
+	require.NoError(t, err)
+	assert.Empty(t, pruned)
+	assert.NoDirExists(t, invalidDir)
+
+	pruned, err = pruneVendorCache(cacheDir, 0)
+	require.NoError(t, err)
+	require.Len(t, pruned, 1)
+	assert.Equal(t, key, pruned[0].Key)
+
+	entries, err = listVendorCacheEntries(cacheDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestVendorCacheSubdirectories(t *testing.T) {
+	cacheDir := t.TempDir()
+	key := vendorCacheKey(pkgTypeRemote, "github.com/org/repo.git?ref=v1", "abc")
+
+	downloads := 0
+	download := func(dir string) error {
+		downloads++
+		for _, module := range []string{"vpc", "eks"} {
+			if err := os.MkdirAll(filepath.Join(dir, "modules", module), 0o755); err != nil {
+				return err
+			}
+			if err := os.WriteFile(filepath.Join(dir, "modules", module, "main.tf"), []byte(module), 0o644); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// The same source vendored from several subdirectories is downloaded once
+	for _, module := range []string{"vpc", "eks"} {
+		destDir := filepath.Join(t.TempDir(), module)
+		err := getVendorCachedSource(cacheDir, key, "github.com/org/repo.git?ref=v1", "abc", "modules/"+module, destDir, download)
+		require.NoError(t, err)
+
+		content, err := os.ReadFile(filepath.Join(destDir, "main.tf"))
+		require.NoError(t, err)
+		assert.Equal(t, module, string(content))
+		assert.NoDirExists(t, filepath.Join(destDir, "modules"))
+	}
+	assert.Equal(t, 1, downloads)
+
+	err := getVendorCachedSource(cacheDir, key, "github.com/org/repo.git?ref=v1", "abc", "modules/rds", t.TempDir(), download)
+	assert.ErrorContains(t, err, "failed to find the subdirectory 'modules/rds'")
+}
@@ -105,67 +105,73 @@ func ExecuteStackVendorInternal(
 	return fmt.Errorf("command 'atmos vendor pull --stack <stack>' is not supported yet")
 }
 
-func copyComponentToDestination(atmosConfig schema.AtmosConfiguration, tempDir, componentPath string, vendorComponentSpec schema.VendorComponentSpec, sourceIsLocalFile bool, uri string) error {
-	// Copy from the temp folder to the destination folder and skip the excluded files
-	copyOptions := cp.Options{
-		// Skip specifies which files should be skipped
-		Skip: func(srcInfo os.FileInfo, src, dest string) (bool, error) {
-			if filepath.Base(src) == ".git" {
+// generateComponentSkipFunction creates a function that determines whether to skip files during copying
+// based on the 'included_paths' and 'excluded_paths' patterns in the component vendoring config
+func generateComponentSkipFunction(tempDir string, vendorComponentSpec schema.VendorComponentSpec) func(os.FileInfo, string, string) (bool, error) {
+	return func(srcInfo os.FileInfo, src, dest string) (bool, error) {
+		if filepath.Base(src) == ".git" {
+			return true, nil
+		}
+
+		trimmedSrc := u.TrimBasePathFromPath(tempDir+"/", src)
+
+		// Exclude the files that match the 'excluded_paths' patterns
+		// It supports POSIX-style Globs for file names/paths (double-star `**` is supported)
+		// https://en.wikipedia.org/wiki/Glob_(programming)
+		// https://github.com/bmatcuk/doublestar#patterns
+		for _, excludePath := range vendorComponentSpec.Source.ExcludedPaths {
+			excludePath := filepath.Clean(excludePath)
+			excludeMatch, err := u.PathMatch(excludePath, src)
+			if err != nil {
+				return true, err
+			} else if excludeMatch {
+				// If the file matches ANY of the 'excluded_paths' patterns, exclude the file
+				u.LogTrace(fmt.Sprintf("Excluding the file '%s' since it matches the '%s' pattern from 'excluded_paths'\n",
+					trimmedSrc,
+					excludePath,
+				))
 				return true, nil
 			}
+		}
 
-			trimmedSrc := u.TrimBasePathFromPath(tempDir+"/", src)
-
-			// Exclude the files that match the 'excluded_paths' patterns
-			// It supports POSIX-style Globs for file names/paths (double-star `**` is supported)
-			// https://en.wikipedia.org/wiki/Glob_(programming)
-			// https://github.com/bmatcuk/doublestar#patterns
-			for _, excludePath := range vendorComponentSpec.Source.ExcludedPaths {
-				excludePath := filepath.Clean(excludePath)
-				excludeMatch, err := u.PathMatch(excludePath, src)
+		// Only include the files that match the 'included_paths' patterns (if any pattern is specified)
+		if len(vendorComponentSpec.Source.IncludedPaths) > 0 {
+			anyMatches := false
+			for _, includePath := range vendorComponentSpec.Source.IncludedPaths {
+				includePath := filepath.Clean(includePath)
+				includeMatch, err := u.PathMatch(includePath, src)
 				if err != nil {
 					return true, err
-				} else if excludeMatch {
-					// If the file matches ANY of the 'excluded_paths' patterns, exclude the file
-					u.LogTrace(fmt.Sprintf("Excluding the file '%s' since it matches the '%s' pattern from 'excluded_paths'\n",
+				} else if includeMatch {
+					// If the file matches ANY of the 'included_paths' patterns, include the file
+					u.LogTrace(fmt.Sprintf("Including '%s' since it matches the '%s' pattern from 'included_paths'\n",
 						trimmedSrc,
-						excludePath,
+						includePath,
 					))
-					return true, nil
+					anyMatches = true
+					break
 				}
 			}
 
-			// Only include the files that match the 'included_paths' patterns (if any pattern is specified)
-			if len(vendorComponentSpec.Source.IncludedPaths) > 0 {
-				anyMatches := false
-				for _, includePath := range vendorComponentSpec.Source.IncludedPaths {
-					includePath := filepath.Clean(includePath)
-					includeMatch, err := u.PathMatch(includePath, src)
-					if err != nil {
-						return true, err
-					} else if includeMatch {
-						// If the file matches ANY of the 'included_paths' patterns, include the file
-						u.LogTrace(fmt.Sprintf("Including '%s' since it matches the '%s' pattern from 'included_paths'\n",
-							trimmedSrc,
-							includePath,
-						))
-						anyMatches = true
-						break
-					}
-				}
-
-				if anyMatches {
-					return false, nil
-				} else {
-					u.LogTrace(fmt.Sprintf("Excluding '%s' since it does not match any pattern from 'included_paths'\n", trimmedSrc))
-					return true, nil
-				}
+			if anyMatches {
+				return false, nil
+			} else {
+				u.LogTrace(fmt.Sprintf("Excluding '%s' since it does not match any pattern from 'included_paths'\n", trimmedSrc))
+				return true, nil
 			}
+		}
 
-			// If 'included_paths' is not provided, include all files that were not excluded
-			u.LogTrace(fmt.Sprintf("Including '%s'\n", u.TrimBasePathFromPath(tempDir+"/", src)))
-			return false, nil
-		},
+		// If 'included_paths' is not provided, include all files that were not excluded
+		u.LogTrace(fmt.Sprintf("Including '%s'\n", u.TrimBasePathFromPath(tempDir+"/", src)))
+		return false, nil
+	}
+}
+
+func copyComponentToDestination(atmosConfig schema.AtmosConfiguration, tempDir, componentPath string, vendorComponentSpec schema.VendorComponentSpec, sourceIsLocalFile bool, uri string) error {
+	// Copy from the temp folder to the destination folder and skip the excluded files
+	copyOptions := cp.Options{
+		// Skip specifies which files should be skipped
+		Skip: generateComponentSkipFunction(tempDir, vendorComponentSpec),
 
 		// Preserve the atime and the mtime of the entries
 		// On linux we can preserve only up to 1 millisecond accuracy
@@ -194,32 +200,32 @@ func copyComponentToDestination(atmosConfig schema.AtmosConfiguration, tempDir,
 	return nil
 }
 
-func ExecuteComponentVendorInternal(
-	atmosConfig schema.AtmosConfiguration,
+// getComponentVendorPackages converts the component vendoring config into the packages (the component and its mixins)
+// to download and install into the component folder
+func getComponentVendorPackages(
 	vendorComponentSpec schema.VendorComponentSpec,
 	component string,
 	componentPath string,
-	dryRun bool,
-) error {
+) ([]pkgComponentVendor, error) {
 	var err error
 	var t *template.Template
 	var uri string
 
 	if vendorComponentSpec.Source.Uri == "" {
-		return fmt.Errorf("'uri' must be specified in 'source.uri' in the component vendoring config file '%s'", cfg.ComponentVendorConfigFileName)
+		return nil, fmt.Errorf("'uri' must be specified in 'source.uri' in the component vendoring config file '%s'", cfg.ComponentVendorConfigFileName)
 	}
 
 	// Parse 'uri' template
 	if vendorComponentSpec.Source.Version != "" {
 		t, err = template.New(fmt.Sprintf("source-uri-%s", vendorComponentSpec.Source.Version)).Funcs(sprig.FuncMap()).Funcs(gomplate.CreateFuncs(context.Background(), nil)).Parse(vendorComponentSpec.Source.Uri)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		var tpl bytes.Buffer
 		err = t.Execute(&tpl, vendorComponentSpec.Source)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		uri = tpl.String()
@@ -286,24 +292,24 @@ func ExecuteComponentVendorInternal(
 	if len(vendorComponentSpec.Mixins) > 0 {
 		for _, mixin := range vendorComponentSpec.Mixins {
 			if mixin.Uri == "" {
-				return errors.New("'uri' must be specified for each 'mixin' in the 'component.yaml' file")
+				return nil, errors.New("'uri' must be specified for each 'mixin' in the 'component.yaml' file")
 			}
 
 			if mixin.Filename == "" {
-				return errors.New("'filename' must be specified for each 'mixin' in the 'component.yaml' file")
+				return nil, errors.New("'filename' must be specified for each 'mixin' in the 'component.yaml' file")
 			}
 
 			// Parse 'uri' template
 			if mixin.Version != "" {
 				t, err = template.New(fmt.Sprintf("mixin-uri-%s", mixin.Version)).Funcs(sprig.FuncMap()).Funcs(gomplate.CreateFuncs(context.Background(), nil)).Parse(mixin.Uri)
 				if err != nil {
-					return err
+					return nil, err
 				}
 
 				var tpl bytes.Buffer
 				err = t.Execute(&tpl, mixin)
 				if err != nil {
-					return err
+					return nil, err
 				}
 
 				uri = tpl.String()
@@ -318,23 +324,20 @@ func ExecuteComponentVendorInternal(
 				uri = strings.TrimPrefix(uri, "oci://")
 			}
 
-			// Check if `uri` is a file path.
-			// If it's a file path, check if it's an absolute path.
+			// Check if `uri` is a local file or directory (with or without the `file://` scheme).
 			// If it's not absolute path, join it with the base path (component dir) and convert to absolute path.
+			useLocalFileSystem = false
 			if !useOciScheme {
-				if absPath, err := u.JoinAbsolutePathWithPath(componentPath, uri); err == nil {
+				if absPath, err := u.JoinAbsolutePathWithPath(componentPath, strings.TrimPrefix(uri, "file://")); err == nil {
 					uri = absPath
+					useLocalFileSystem = true
 				}
 			}
-			// Check if it's a local file
-			if absPath, err := u.JoinAbsolutePathWithPath(componentPath, uri); err == nil {
-				if u.FileExists(absPath) {
-					pType = pkgTypeLocal
-					continue
-				}
-			}
+
 			if useOciScheme {
 				pType = pkgTypeOci
+			} else if useLocalFileSystem {
+				pType = pkgTypeLocal
 			} else {
 				pType = pkgTypeRemote
 			}
@@ -355,6 +358,21 @@ func ExecuteComponentVendorInternal(
 		}
 	}
 
+	return packages, nil
+}
+
+func ExecuteComponentVendorInternal(
+	atmosConfig schema.AtmosConfiguration,
+	vendorComponentSpec schema.VendorComponentSpec,
+	component string,
+	componentPath string,
+	dryRun bool,
+) error {
+	packages, err := getComponentVendorPackages(vendorComponentSpec, component, componentPath)
+	if err != nil {
+		return err
+	}
+
 	// Run TUI to process packages
 	if len(packages) > 0 {
 		model, err := newModelComponentVendorInternal(packages, dryRun, atmosConfig)
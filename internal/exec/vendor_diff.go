@@ -0,0 +1,590 @@
+package exec
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hexops/gotextdiff"
+	"github.com/hexops/gotextdiff/myers"
+	"github.com/hexops/gotextdiff/span"
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+
+	cfg "github.com/cloudposse/atmos/pkg/config"
+	"github.com/cloudposse/atmos/pkg/schema"
+	u "github.com/cloudposse/atmos/pkg/utils"
+)
+
+type vendorDiffStatus string
+
+const (
+	// vendorDiffModified means the file exists upstream and locally, but the contents differ
+	vendorDiffModified vendorDiffStatus = "modified"
+	// vendorDiffMissing means the file exists upstream, but not in the local target
+	vendorDiffMissing vendorDiffStatus = "missing"
+	// vendorDiffAdded means the file exists only in the local target
+	vendorDiffAdded vendorDiffStatus = "added"
+)
+
+// vendorDiffIgnoredPatterns are the files that Atmos and Terraform generate in the component folders.
+// They are never reported as added locally.
+var vendorDiffIgnoredPatterns = []string{
+	"**/.git/**",
+	"**/.terraform/**",
+	"**/.terraform.lock.hcl",
+	"**/*.tfstate",
+	"**/*.tfstate.*",
+	"**/*.tfvars.json",
+	"**/*.planfile",
+	"**/*.tfplan",
+	"**/backend.tf.json",
+	"**/component.yaml",
+	"**/component.yml",
+}
+
+// vendorFileDiff describes a difference between a vendored file and its upstream source
+type vendorFileDiff struct {
+	path   string
+	status vendorDiffStatus
+	diff   string
+}
+
+// vendorDiffTarget describes a local target and all the packages that are vendored into it
+type vendorDiffTarget struct {
+	path  string
+	names []string
+	// stage downloads the packages and copies the files into the upstream and the expected folders, the same way `atmos vendor pull` does.
+	// The local patches are only applied to the expected folder
+	stage []func(upstreamPath string, expectedPath string) error
+	// managed reports if a local file would be written by any of the packages
+	managed []func(localFile string) bool
+	// patchFiles are the local patch files of the packages
+	patchFiles []string
+}
+
+// ExecuteVendorDiffCommand executes `atmos vendor diff` commands
+func ExecuteVendorDiffCommand(cmd *cobra.Command, args []string) error {
+	info, err := ProcessCommandLineArgs("terraform", cmd, args, nil)
+	if err != nil {
+		return err
+	}
+
+	atmosConfig, err := cfg.InitCliConfig(info, false)
+	if err != nil {
+		return fmt.Errorf("failed to initialize CLI config: %w", err)
+	}
+
+	flags := cmd.Flags()
+
+	component, err := flags.GetString("component")
+	if err != nil {
+		return err
+	}
+
+	tagsCsv, err := flags.GetString("tags")
+	if err != nil {
+		return err
+	}
+
+	var tags []string
+	if tagsCsv != "" {
+		tags = strings.Split(tagsCsv, ",")
+	}
+
+	if component != "" && len(tags) > 0 {
+		return fmt.Errorf("either '--component' or '--tags' flag can be provided, but not both")
+	}
+
+	summaryOnly, err := flags.GetBool("summary")
+	if err != nil {
+		return err
+	}
+
+	var drifted bool
+
+	// Check `vendor.yaml`
+	vendorConfig, vendorConfigExists, foundVendorConfigFile, err := ReadAndProcessVendorConfigFile(atmosConfig, cfg.AtmosVendorConfigFileName, true)
+	if err != nil {
+		return err
+	}
+
+	if vendorConfigExists {
+		drifted, err = ExecuteAtmosVendorDiffInternal(atmosConfig, foundVendorConfigFile, vendorConfig.Spec, component, tags, summaryOnly)
+		if err != nil {
+			return err
+		}
+	} else {
+		if component == "" {
+			return fmt.Errorf("to compare a component, the '--component' (shorthand '-c') flag needs to be specified.\n" +
+				"Example: atmos vendor diff -c <component>")
+		}
+
+		componentType, err := flags.GetString("type")
+		if err != nil {
+			return err
+		}
+
+		if componentType == "" {
+			componentType = "terraform"
+		}
+
+		componentConfig, componentPath, err := ReadAndProcessComponentVendorConfigFile(atmosConfig, component, componentType)
+		if err != nil {
+			return err
+		}
+
+		drifted, err = ExecuteComponentVendorDiffInternal(atmosConfig, componentConfig.Spec, component, componentPath, summaryOnly)
+		if err != nil {
+			return err
+		}
+	}
+
+	if drifted {
+		return fmt.Errorf("the vendored files differ from the upstream sources")
+	}
+
+	return nil
+}
+
+// ExecuteAtmosVendorDiffInternal compares the targets defined in the vendor config file `vendor.yaml` with the upstream sources.
+// It prints the differences and returns `true` if any of the targets drifted from the sources
+func ExecuteAtmosVendorDiffInternal(
+	atmosConfig schema.AtmosConfiguration,
+	vendorConfigFileName string,
+	atmosVendorSpec schema.AtmosVendorSpec,
+	component string,
+	tags []string,
+	summaryOnly bool,
+) (bool, error) {
+	if len(atmosVendorSpec.Sources) == 0 && len(atmosVendorSpec.Imports) == 0 {
+		return false, fmt.Errorf("either 'spec.sources' or 'spec.imports' (or both) must be defined in the vendor config file '%s'", vendorConfigFileName)
+	}
+
+	sources, _, err := processVendorImports(
+		atmosConfig,
+		vendorConfigFileName,
+		atmosVendorSpec.Imports,
+		atmosVendorSpec.Sources,
+		[]string{vendorConfigFileName},
+	)
+	if err != nil {
+		return false, err
+	}
+
+	packages, err := getAtmosVendorPackages(filepath.Dir(vendorConfigFileName), vendorConfigFileName, sources, component, tags)
+	if err != nil {
+		return false, err
+	}
+
+	if len(packages) == 0 {
+		return false, fmt.Errorf("no sources to compare in the vendor config file '%s' and the imports", vendorConfigFileName)
+	}
+
+	var targets []*vendorDiffTarget
+	targetsByPath := map[string]*vendorDiffTarget{}
+
+	for i := range packages {
+		p := packages[i]
+
+		t, ok := targetsByPath[p.targetPath]
+		if !ok {
+			t = &vendorDiffTarget{path: p.targetPath}
+			targetsByPath[p.targetPath] = t
+			targets = append(targets, t)
+		}
+
+		t.names = append(t.names, p.name)
+
+		patchFiles := resolveVendorPatchPaths(p.vendorConfigFilePath, p.atmosVendorSource.Patches)
+		t.patchFiles = append(t.patchFiles, patchFiles...)
+
+		t.stage = append(t.stage, func(upstreamPath string, expectedPath string) error {
+			tempDir, err := os.MkdirTemp("", "atmos-vendor-diff")
+			if err != nil {
+				return err
+			}
+			defer removeTempDir(atmosConfig, tempDir)
+
+			srcDir, err := downloadAtmosVendorPackage(&p, tempDir, atmosConfig)
+			if err != nil {
+				return err
+			}
+			if err := copyToTarget(atmosConfig, srcDir, upstreamPath, &p.atmosVendorSource, p.sourceIsLocalFile, p.uri); err != nil {
+				return err
+			}
+			if err := copyToTarget(atmosConfig, srcDir, expectedPath, &p.atmosVendorSource, p.sourceIsLocalFile, p.uri); err != nil {
+				return err
+			}
+			if _, err := applyVendorPatches(getVendorPatchRoot(expectedPath), patchFiles); err != nil {
+				return fmt.Errorf("failed to apply patches to '%s': %w", p.name, err)
+			}
+			return nil
+		})
+
+		skip := generateSkipFunction(atmosConfig, p.targetPath, &p.atmosVendorSource)
+		t.managed = append(t.managed, func(localFile string) bool {
+			skipped, err := skip(nil, localFile, "")
+			return err == nil && !skipped
+		})
+	}
+
+	return diffVendorTargets(atmosConfig, targets, summaryOnly)
+}
+
+// ExecuteComponentVendorDiffInternal compares the component folder with the sources defined in the component vendoring config file `component.yaml`.
+// It prints the differences and returns `true` if the component drifted from the sources
+func ExecuteComponentVendorDiffInternal(
+	atmosConfig schema.AtmosConfiguration,
+	vendorComponentSpec schema.VendorComponentSpec,
+	component string,
+	componentPath string,
+	summaryOnly bool,
+) (bool, error) {
+	packages, err := getComponentVendorPackages(vendorComponentSpec, component, componentPath)
+	if err != nil {
+		return false, err
+	}
+
+	target := &vendorDiffTarget{
+		path:       componentPath,
+		patchFiles: resolveVendorPatchPaths(componentPath, vendorComponentSpec.Patches),
+	}
+
+	for i := range packages {
+		p := packages[i]
+
+		target.names = append(target.names, p.name)
+
+		target.stage = append(target.stage, func(upstreamPath string, expectedPath string) error {
+			tempDir, err := os.MkdirTemp("", "atmos-vendor-diff")
+			if err != nil {
+				return err
+			}
+			defer removeTempDir(atmosConfig, tempDir)
+
+			if p.IsMixins {
+				if err := downloadMixinPackage(&p, tempDir, atmosConfig); err != nil {
+					return err
+				}
+				if err := copyToTarget(atmosConfig, tempDir, upstreamPath, &schema.AtmosVendorSource{}, false, p.uri); err != nil {
+					return err
+				}
+				return copyToTarget(atmosConfig, tempDir, expectedPath, &schema.AtmosVendorSource{}, false, p.uri)
+			}
+
+			srcDir, err := downloadComponentPackage(&p, tempDir, atmosConfig)
+			if err != nil {
+				return err
+			}
+			if err := copyComponentToDestination(atmosConfig, srcDir, upstreamPath, p.vendorComponentSpec, p.sourceIsLocalFile, p.uri); err != nil {
+				return err
+			}
+			if err := copyComponentToDestination(atmosConfig, srcDir, expectedPath, p.vendorComponentSpec, p.sourceIsLocalFile, p.uri); err != nil {
+				return err
+			}
+			if _, err := applyVendorPatches(expectedPath, target.patchFiles); err != nil {
+				return fmt.Errorf("failed to apply patches to '%s': %w", p.name, err)
+			}
+			return nil
+		})
+	}
+
+	skip := generateComponentSkipFunction(componentPath, vendorComponentSpec)
+	target.managed = append(target.managed, func(localFile string) bool {
+		skipped, err := skip(nil, localFile, "")
+		return err == nil && !skipped
+	})
+
+	return diffVendorTargets(atmosConfig, []*vendorDiffTarget{target}, summaryOnly)
+}
+
+// diffVendorTargets downloads the upstream sources of the targets, compares them with the local files, and prints the differences.
+// The changes made by the local patches are printed separately from the upstream drift, and are not considered drift
+func diffVendorTargets(atmosConfig schema.AtmosConfiguration, targets []*vendorDiffTarget, summaryOnly bool) (bool, error) {
+	drifted := false
+	var summary []string
+
+	for _, t := range targets {
+		u.LogDebug(fmt.Sprintf("Comparing '%s' with the upstream sources of %s", t.path, strings.Join(t.names, ", ")))
+
+		diffs, patchDiffs, err := diffVendorTarget(atmosConfig, t)
+		if err != nil {
+			return false, fmt.Errorf("failed to compare '%s' with the upstream sources: %w", t.path, err)
+		}
+
+		patched := ""
+		if len(patchDiffs) > 0 {
+			patched = fmt.Sprintf(" (%d files patched locally)", len(patchDiffs))
+
+			u.PrintMessage(fmt.Sprintf("Local patches in %s:", filepath.ToSlash(t.path)))
+			printVendorFileDiffs(patchDiffs, summaryOnly)
+			u.PrintMessage("")
+		}
+
+		if len(diffs) == 0 {
+			summary = append(summary, fmt.Sprintf("%s: up to date%s", filepath.ToSlash(t.path), patched))
+			continue
+		}
+
+		drifted = true
+
+		if len(patchDiffs) > 0 {
+			u.PrintMessage(fmt.Sprintf("Upstream drift in %s:", filepath.ToSlash(t.path)))
+		}
+		printVendorFileDiffs(diffs, summaryOnly)
+
+		counts := map[vendorDiffStatus]int{}
+		for _, d := range diffs {
+			counts[d.status]++
+		}
+
+		summary = append(summary, fmt.Sprintf("%s: %d modified, %d missing, %d added%s",
+			filepath.ToSlash(t.path),
+			counts[vendorDiffModified],
+			counts[vendorDiffMissing],
+			counts[vendorDiffAdded],
+			patched,
+		))
+	}
+
+	if !summaryOnly || drifted {
+		u.PrintMessage("")
+	}
+	for _, s := range summary {
+		u.PrintMessage(s)
+	}
+
+	return drifted, nil
+}
+
+// printVendorFileDiffs prints the unified diffs, or only the status and the names of the files
+func printVendorFileDiffs(diffs []vendorFileDiff, summaryOnly bool) {
+	for _, d := range diffs {
+		if summaryOnly {
+			u.PrintMessage(fmt.Sprintf("%-9s %s", d.status, filepath.ToSlash(d.path)))
+		} else {
+			u.PrintMessage(d.diff)
+		}
+	}
+}
+
+// diffVendorTarget stages the upstream files of the target in a temp folder and compares them with the local files.
+// It returns the differences between the expected (upstream and patched) files and the local files,
+// and the differences made by the local patches
+func diffVendorTarget(atmosConfig schema.AtmosConfiguration, t *vendorDiffTarget) ([]vendorFileDiff, []vendorFileDiff, error) {
+	tempDir, err := os.MkdirTemp("", "atmos-vendor-diff-expected")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer removeTempDir(atmosConfig, tempDir)
+
+	upstreamPath := filepath.Join(tempDir, "upstream")
+	expectedPath := filepath.Join(tempDir, "target")
+
+	for _, stage := range t.stage {
+		if err := stage(upstreamPath, expectedPath); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	upstreamFiles, err := collectVendorDiffFiles(upstreamPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	expectedFiles, err := collectVendorDiffFiles(expectedPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	localFiles, err := collectVendorDiffFiles(t.path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var patchDiffs []vendorFileDiff
+	if len(t.patchFiles) > 0 {
+		patchDiffs, err = diffVendorFiles(upstreamFiles, expectedFiles, t.path, "upstream", "patched", func(string, string) bool {
+			return true
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	diffs, err := diffVendorFiles(expectedFiles, localFiles, t.path, "upstream", "local", func(rel string, localFile string) bool {
+		return !isVendorDiffIgnored(rel) && !isVendorDiffPatchFile(t, localFile) && isVendorDiffManaged(t, localFile)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return diffs, patchDiffs, nil
+}
+
+// diffVendorFiles compares the files and returns the differences.
+// The files that only exist in the second set are reported as added if `reportAdded` returns `true` for them
+func diffVendorFiles(
+	fromFiles map[string]string,
+	toFiles map[string]string,
+	displayRoot string,
+	fromLabel string,
+	toLabel string,
+	reportAdded func(rel string, file string) bool,
+) ([]vendorFileDiff, error) {
+	var diffs []vendorFileDiff
+
+	for _, rel := range sortedVendorDiffFiles(fromFiles) {
+		displayPath := filepath.Join(displayRoot, rel)
+
+		toFile, ok := toFiles[rel]
+		if !ok {
+			diffs = append(diffs, vendorFileDiff{
+				path:   displayPath,
+				status: vendorDiffMissing,
+				diff:   fmt.Sprintf("Only in %s: %s", fromLabel, filepath.ToSlash(displayPath)),
+			})
+			continue
+		}
+
+		diff, err := diffVendorFile(fromFiles[rel], toFile, displayPath, fromLabel, toLabel)
+		if err != nil {
+			return nil, err
+		}
+		if diff != "" {
+			diffs = append(diffs, vendorFileDiff{path: displayPath, status: vendorDiffModified, diff: diff})
+		}
+	}
+
+	for _, rel := range sortedVendorDiffFiles(toFiles) {
+		if _, ok := fromFiles[rel]; ok {
+			continue
+		}
+
+		if !reportAdded(rel, toFiles[rel]) {
+			continue
+		}
+
+		displayPath := filepath.Join(displayRoot, rel)
+		diffs = append(diffs, vendorFileDiff{
+			path:   displayPath,
+			status: vendorDiffAdded,
+			diff:   fmt.Sprintf("Only in %s: %s", toLabel, filepath.ToSlash(displayPath)),
+		})
+	}
+
+	sort.SliceStable(diffs, func(i, j int) bool {
+		return diffs[i].path < diffs[j].path
+	})
+
+	return diffs, nil
+}
+
+// collectVendorDiffFiles returns a map of the file paths relative to the root to the absolute file paths.
+// If the root is a file, the map contains one entry with an empty relative path
+func collectVendorDiffFiles(root string) (map[string]string, error) {
+	files := map[string]string{}
+
+	info, err := os.Stat(root)
+	if os.IsNotExist(err) {
+		return files, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		files[""] = root
+		return files, nil
+	}
+
+	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = path
+		return nil
+	})
+
+	return files, err
+}
+
+// sortedVendorDiffFiles returns the relative file paths sorted alphabetically
+func sortedVendorDiffFiles(files map[string]string) []string {
+	keys := lo.Keys(files)
+	sort.Strings(keys)
+	return keys
+}
+
+// diffVendorFile returns the unified diff between the expected and the local file, or an empty string if the files are identical
+func diffVendorFile(expectedFile string, localFile string, displayPath string, expectedLabel string, localLabel string) (string, error) {
+	expected, err := os.ReadFile(expectedFile)
+	if err != nil {
+		return "", err
+	}
+
+	local, err := os.ReadFile(localFile)
+	if err != nil {
+		return "", err
+	}
+
+	if bytes.Equal(expected, local) {
+		return "", nil
+	}
+
+	displayPath = filepath.ToSlash(displayPath)
+
+	if bytes.IndexByte(expected, 0) >= 0 || bytes.IndexByte(local, 0) >= 0 {
+		return fmt.Sprintf("Binary files %s/%s and %s/%s differ", expectedLabel, displayPath, localLabel, displayPath), nil
+	}
+
+	from := expectedLabel + "/" + displayPath
+	to := localLabel + "/" + displayPath
+	edits := myers.ComputeEdits(span.URIFromPath(from), string(expected), string(local))
+
+	return fmt.Sprint(gotextdiff.ToUnified(from, to, string(expected), edits)), nil
+}
+
+// isVendorDiffIgnored checks if the file is generated by Atmos or Terraform in the component folder
+func isVendorDiffIgnored(rel string) bool {
+	for _, pattern := range vendorDiffIgnoredPatterns {
+		if match, err := u.PathMatch(pattern, rel); err == nil && match {
+			return true
+		}
+	}
+	return false
+}
+
+// isVendorDiffManaged checks if any of the packages vendored into the target would write the local file
+func isVendorDiffManaged(t *vendorDiffTarget, localFile string) bool {
+	for _, managed := range t.managed {
+		if managed(localFile) {
+			return true
+		}
+	}
+	return false
+}
+
+// isVendorDiffPatchFile checks if the local file is one of the patch files of the target
+func isVendorDiffPatchFile(t *vendorDiffTarget, localFile string) bool {
+	for _, patchFile := range t.patchFiles {
+		if filepath.Clean(patchFile) == filepath.Clean(localFile) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,262 @@
+package exec
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/hashicorp/go-getter"
+
+	"github.com/cloudposse/atmos/pkg/schema"
+)
+
+const (
+	// cosignSignatureTagSuffix is the suffix of the tag where cosign stores the signatures of an image (`sha256-<hex>.sig`)
+	cosignSignatureTagSuffix = ".sig"
+	// cosignSignatureAnnotation is the annotation of the signature layers that contains the base64-encoded signature of the layer
+	cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+)
+
+// cosignSimpleSigningPayload is the payload signed by cosign
+// https://github.com/containers/image/blob/main/docs/containers-signature.5.md
+type cosignSimpleSigningPayload struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+}
+
+// parseVendorChecksum validates the `checksum` of a vendor source and returns it in the `sha256:<hex>` format
+func parseVendorChecksum(checksum string) (string, error) {
+	algorithm, value, found := strings.Cut(checksum, ":")
+	if !found || algorithm != "sha256" {
+		return "", fmt.Errorf("invalid checksum '%s'. The checksum must be in the format 'sha256:<hex>'", checksum)
+	}
+
+	value = strings.ToLower(value)
+	if decoded, err := hex.DecodeString(value); err != nil || len(decoded) != sha256.Size {
+		return "", fmt.Errorf("invalid checksum '%s'. The checksum must be in the format 'sha256:<hex>'", checksum)
+	}
+
+	return "sha256:" + value, nil
+}
+
+// isVendorArchiveSource checks if go-getter downloads the source as an archive and unpacks it
+// (the source has the `archive` query parameter, or the path has an archive extension)
+func isVendorArchiveSource(uri string) bool {
+	src, _ := getter.SourceDirSubdir(uri)
+
+	// Remove the forced getter, e.g. `git::`
+	if before, after, found := strings.Cut(src, "::"); found && !strings.Contains(before, "/") {
+		src = after
+	}
+
+	u, err := url.Parse(src)
+	if err != nil {
+		return false
+	}
+
+	if archive := u.Query().Get("archive"); archive != "" {
+		_, ok := getter.Decompressors[archive]
+		return ok
+	}
+
+	for ext := range getter.Decompressors {
+		if strings.HasSuffix(u.Path, "."+ext) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// addVendorArchiveChecksum adds the `checksum` query parameter to the source, so go-getter verifies the archive before unpacking it
+func addVendorArchiveChecksum(uri string, checksum string) string {
+	separator := "?"
+	if strings.Contains(uri, "?") {
+		separator = "&"
+	}
+	return uri + separator + "checksum=" + url.QueryEscape(checksum)
+}
+
+// verifyVendorChecksum checks that the `checksum` matches the tree hash of the downloaded files,
+// or the SHA-256 hash of the file if a single file was downloaded
+func verifyVendorChecksum(checksum string, lockSource schema.AtmosVendorLockSource) error {
+	if checksum == lockSource.TreeHash {
+		return nil
+	}
+
+	if len(lockSource.Files) == 1 {
+		for _, hash := range lockSource.Files {
+			if checksum == "sha256:"+hash {
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("the checksum '%s' does not match the tree hash '%s' of the downloaded files", checksum, lockSource.TreeHash)
+}
+
+// verifyVendorSignature verifies that the OCI image with the digest is signed with the key configured in the `signature` of the vendor source.
+// The signatures are read from the cosign signature tag (`sha256-<hex>.sig`) in the repository of the image
+func verifyVendorSignature(imageName string, digest string, signature *schema.AtmosVendorSignature, vendorConfigFilePath string) error {
+	if signature.Key == "" {
+		return errors.New("'key' must be specified in the 'signature'")
+	}
+
+	publicKey, err := loadVendorPublicKey(vendorConfigFilePath, signature.Key)
+	if err != nil {
+		return err
+	}
+
+	ref, err := name.ParseReference(imageName)
+	if err != nil {
+		return fmt.Errorf("cannot parse reference of the image '%s'. Error: %v", imageName, err)
+	}
+
+	signatureTag := ref.Context().Tag(strings.Replace(digest, ":", "-", 1) + cosignSignatureTagSuffix)
+
+	signatureImage, err := remote.Image(signatureTag, remote.WithAuthFromKeychain(authn.DefaultKeychain))
+	if err != nil {
+		return fmt.Errorf("cannot get the signatures '%s' of the image '%s'. Error: %v", signatureTag.Name(), imageName, err)
+	}
+
+	manifest, err := signatureImage.Manifest()
+	if err != nil {
+		return err
+	}
+
+	layers, err := signatureImage.Layers()
+	if err != nil {
+		return err
+	}
+
+	// The image is verified if any of the signatures is valid
+	var lastErr error
+	for i, descriptor := range manifest.Layers {
+		encoded, ok := descriptor.Annotations[cosignSignatureAnnotation]
+		if !ok || i >= len(layers) {
+			continue
+		}
+
+		sig, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			lastErr = fmt.Errorf("invalid signature encoding: %w", err)
+			continue
+		}
+
+		payload, err := readVendorSignatureLayer(layers[i].Compressed)
+		if err != nil {
+			return err
+		}
+
+		if err := verifyVendorSignatureBytes(publicKey, payload, sig); err != nil {
+			lastErr = err
+			continue
+		}
+
+		var p cosignSimpleSigningPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			lastErr = fmt.Errorf("invalid signature payload: %w", err)
+			continue
+		}
+
+		if p.Critical.Image.DockerManifestDigest != digest {
+			lastErr = fmt.Errorf("the signature is for the digest '%s'", p.Critical.Image.DockerManifestDigest)
+			continue
+		}
+
+		return nil
+	}
+
+	if lastErr == nil {
+		lastErr = errors.New("no signatures found")
+	}
+
+	return fmt.Errorf("the image '%s@%s' is not signed with the key '%s': %w", ref.Context().Name(), digest, signature.Key, lastErr)
+}
+
+// readVendorSignatureLayer reads the content of a signature layer
+func readVendorSignatureLayer(open func() (io.ReadCloser, error)) ([]byte, error) {
+	r, err := open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return io.ReadAll(r)
+}
+
+// loadVendorPublicKey reads the PEM-encoded public key from the file (relative to the vendor config file),
+// or parses the key itself if it's PEM-encoded
+func loadVendorPublicKey(vendorConfigFilePath string, key string) (crypto.PublicKey, error) {
+	data := []byte(key)
+
+	if !strings.HasPrefix(strings.TrimSpace(key), "-----BEGIN") {
+		keyFile := key
+		if !filepath.IsAbs(keyFile) {
+			keyFile = filepath.Join(vendorConfigFilePath, keyFile)
+		}
+
+		var err error
+		if data, err = os.ReadFile(keyFile); err != nil {
+			return nil, fmt.Errorf("failed to read the public key: %w", err)
+		}
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("the public key '%s' is not PEM-encoded", key)
+	}
+
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key '%s': %w", key, err)
+	}
+
+	return publicKey, nil
+}
+
+// verifyVendorSignatureBytes verifies the signature of the payload.
+// ECDSA and RSA (PKCS #1 v1.5) signatures are verified against the SHA-256 hash of the payload, the same way as cosign signs them
+func verifyVendorSignatureBytes(publicKey crypto.PublicKey, payload []byte, sig []byte) error {
+	digest := sha256.Sum256(payload)
+
+	switch k := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(k, digest[:], sig) {
+			return errors.New("invalid signature")
+		}
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], sig); err != nil {
+			return errors.New("invalid signature")
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(k, payload, sig) {
+			return errors.New("invalid signature")
+		}
+	default:
+		return fmt.Errorf("unsupported public key type %T", publicKey)
+	}
+
+	return nil
+}
@@ -0,0 +1,585 @@
+package exec
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	osexec "os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-getter"
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+
+	cfg "github.com/cloudposse/atmos/pkg/config"
+	"github.com/cloudposse/atmos/pkg/schema"
+	u "github.com/cloudposse/atmos/pkg/utils"
+)
+
+var (
+	gitCommitShaRegex = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+	// resolvedVendorRevisions memoizes the revisions resolved by `resolveVendorSourceRevision`
+	resolvedVendorRevisions sync.Map
+)
+
+// ExecuteVendorVerifyCommand executes `atmos vendor verify` commands
+func ExecuteVendorVerifyCommand(cmd *cobra.Command, args []string) error {
+	info, err := ProcessCommandLineArgs("terraform", cmd, args, nil)
+	if err != nil {
+		return err
+	}
+
+	atmosConfig, err := cfg.InitCliConfig(info, false)
+	if err != nil {
+		return fmt.Errorf("failed to initialize CLI config: %w", err)
+	}
+
+	component, err := cmd.Flags().GetString("component")
+	if err != nil {
+		return err
+	}
+
+	_, vendorConfigExists, foundVendorConfigFile, err := ReadAndProcessVendorConfigFile(atmosConfig, cfg.AtmosVendorConfigFileName, true)
+	if err != nil {
+		return err
+	}
+	if !vendorConfigExists {
+		return fmt.Errorf("the vendor config file '%s' does not exist", cfg.AtmosVendorConfigFileName)
+	}
+
+	return ExecuteVendorVerifyInternal(foundVendorConfigFile, component)
+}
+
+// ExecuteVendorVerifyInternal checks the local files against the hashes recorded in the vendor lock file.
+// It does not access the network
+func ExecuteVendorVerifyInternal(vendorConfigFileName string, component string) error {
+	lockFile := getVendorLockFilePath(vendorConfigFileName)
+
+	lock, exists, err := ReadVendorLockFile(lockFile)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("the vendor lock file '%s' does not exist. Execute 'atmos vendor pull' to create it", lockFile)
+	}
+
+	vendorConfigFilePath := filepath.Dir(vendorConfigFileName)
+	verified := 0
+	var failed []string
+
+	for _, source := range lock.Sources {
+		if component != "" && source.Component != component {
+			continue
+		}
+
+		verified++
+
+		name := source.Component
+		if name == "" {
+			name = source.Source
+		}
+
+		problems, err := verifyVendorLockSource(vendorConfigFilePath, source)
+		if err != nil {
+			return err
+		}
+
+		if len(problems) == 0 {
+			u.LogInfo(fmt.Sprintf("%s %s", checkMark, name))
+			continue
+		}
+
+		u.LogInfo(fmt.Sprintf("%s %s", xMark, name))
+		for _, problem := range problems {
+			u.LogInfo(fmt.Sprintf("  %s", problem))
+		}
+		failed = append(failed, name)
+	}
+
+	if component != "" && verified == 0 {
+		return fmt.Errorf("the component '%s' is not found in the vendor lock file '%s'", component, lockFile)
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("the local files of %v do not match the hashes in the vendor lock file '%s'", failed, lockFile)
+	}
+
+	return nil
+}
+
+// getVendorLockFilePath returns the path to the vendor lock file, which is placed next to the vendor config file (or folder)
+func getVendorLockFilePath(vendorConfigFileName string) string {
+	return filepath.Join(filepath.Dir(vendorConfigFileName), cfg.AtmosVendorLockFileName)
+}
+
+// ReadVendorLockFile reads the vendor lock file. It returns `false` if the file does not exist
+func ReadVendorLockFile(lockFile string) (schema.AtmosVendorLock, bool, error) {
+	var lock schema.AtmosVendorLock
+
+	if !u.FileExists(lockFile) {
+		return lock, false, nil
+	}
+
+	content, err := os.ReadFile(lockFile)
+	if err != nil {
+		return lock, false, err
+	}
+
+	lock, err = u.UnmarshalYAML[schema.AtmosVendorLock](string(content))
+	if err != nil {
+		return lock, false, fmt.Errorf("invalid vendor lock file '%s': %w", lockFile, err)
+	}
+
+	return lock, true, nil
+}
+
+// updateVendorLockFile adds the sources to the vendor lock file (replacing the existing sources for the same targets) and writes the file
+func updateVendorLockFile(lockFile string, sources []schema.AtmosVendorLockSource) error {
+	lock, _, err := ReadVendorLockFile(lockFile)
+	if err != nil {
+		return err
+	}
+
+	lock.ApiVersion = "atmos/v1"
+	lock.Kind = "AtmosVendorLock"
+
+	lock.Sources = lo.Filter(lock.Sources, func(existing schema.AtmosVendorLockSource, _ int) bool {
+		return !lo.ContainsBy(sources, func(s schema.AtmosVendorLockSource) bool {
+			return vendorLockKey(s) == vendorLockKey(existing)
+		})
+	})
+	lock.Sources = append(lock.Sources, sources...)
+
+	sort.SliceStable(lock.Sources, func(i, j int) bool {
+		return vendorLockKey(lock.Sources[i]) < vendorLockKey(lock.Sources[j])
+	})
+
+	return u.WriteToFileAsYAML(lockFile, lock, 0o644)
+}
+
+// vendorLockKey returns the key that identifies the source and the target in the vendor lock file
+func vendorLockKey(s schema.AtmosVendorLockSource) string {
+	name := s.Component
+	if name == "" {
+		name = s.Source
+	}
+	return s.Target + "|" + name
+}
+
+// findVendorLockSource finds the source for the package in the vendor lock file
+func findVendorLockSource(lock schema.AtmosVendorLock, p *pkgAtmosVendor) *schema.AtmosVendorLockSource {
+	key := vendorLockKey(schema.AtmosVendorLockSource{
+		Component: p.atmosVendorSource.Component,
+		Source:    vendorLockSourceURI(p),
+		Target:    vendorLockTarget(p),
+	})
+
+	for i := range lock.Sources {
+		if vendorLockKey(lock.Sources[i]) == key {
+			return &lock.Sources[i]
+		}
+	}
+	return nil
+}
+
+// vendorLockTarget returns the target of the package relative to the vendor config file
+func vendorLockTarget(p *pkgAtmosVendor) string {
+	target, err := filepath.Rel(p.vendorConfigFilePath, p.targetPath)
+	if err != nil {
+		target = p.targetPath
+	}
+	return filepath.ToSlash(target)
+}
+
+// vendorLockSourceURI returns the URI of the package source as recorded in the vendor lock file.
+// Local sources are recorded relative to the vendor config file
+func vendorLockSourceURI(p *pkgAtmosVendor) string {
+	switch p.pkgType {
+	case pkgTypeOci:
+		return "oci://" + p.uri
+	case pkgTypeLocal:
+		basePath, err := filepath.Abs(p.vendorConfigFilePath)
+		if err != nil {
+			return p.uri
+		}
+		if source, err := filepath.Rel(basePath, p.uri); err == nil {
+			return filepath.ToSlash(source)
+		}
+	}
+	return p.uri
+}
+
+// newVendorLockSource resolves the revision of the downloaded package and hashes the files that will be copied to the target
+func newVendorLockSource(atmosConfig schema.AtmosConfiguration, p *pkgAtmosVendor, srcDir string) (schema.AtmosVendorLockSource, error) {
+	lockSource := schema.AtmosVendorLockSource{
+		Component: p.atmosVendorSource.Component,
+		Source:    vendorLockSourceURI(p),
+		Version:   p.version,
+		Target:    vendorLockTarget(p),
+		Files:     map[string]string{},
+	}
+
+	// If the revision can't be resolved, only the tree hash is recorded.
+	// `atmos vendor pull --frozen` fails in this case if the lock file has the revision
+	resolved, err := resolveVendorSourceRevision(p.pkgType, p.uri)
+	if err != nil {
+		u.LogWarning(fmt.Sprintf("failed to resolve the revision of '%s': %v", p.name, err))
+	}
+	lockSource.Resolved = resolved
+
+	destPath := getVendorTargetPath(p.targetPath, p.sourceIsLocalFile, p.uri)
+	skip := generateSkipFunction(atmosConfig, srcDir, &p.atmosVendorSource)
+
+	files := map[string]string{}
+	if err := collectVendorCopiedFiles(srcDir, destPath, skip, files, true); err != nil {
+		return lockSource, err
+	}
+
+	for dest, src := range files {
+		hash, err := hashVendorFile(src)
+		if err != nil {
+			return lockSource, err
+		}
+
+		rel, err := filepath.Rel(p.vendorConfigFilePath, dest)
+		if err != nil {
+			return lockSource, err
+		}
+		lockSource.Files[filepath.ToSlash(rel)] = hash
+	}
+
+	lockSource.TreeHash = vendorTreeHash(lockSource.Target, lockSource.Files)
+
+	return lockSource, nil
+}
+
+// updateVendorLockSourcePatches records the hashes of the applied patch files in the lock source,
+// and updates the hashes of the files changed by the patches.
+// The tree hash is not changed, since it describes the upstream files
+func updateVendorLockSourcePatches(
+	lockSource *schema.AtmosVendorLockSource,
+	vendorConfigFilePath string,
+	patchRoot string,
+	patchFiles []string,
+	changed []string,
+) error {
+	lockSource.Patches = map[string]string{}
+
+	for _, patchFile := range patchFiles {
+		hash, err := hashVendorFile(patchFile)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(vendorConfigFilePath, patchFile)
+		if err != nil {
+			return err
+		}
+		lockSource.Patches[filepath.ToSlash(rel)] = hash
+	}
+
+	for _, f := range changed {
+		file := filepath.Join(patchRoot, filepath.FromSlash(f))
+
+		rel, err := filepath.Rel(vendorConfigFilePath, file)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if !u.FileExists(file) {
+			delete(lockSource.Files, rel)
+			continue
+		}
+
+		hash, err := hashVendorFile(file)
+		if err != nil {
+			return err
+		}
+		lockSource.Files[rel] = hash
+	}
+
+	return nil
+}
+
+// verifyFrozenVendorLockSource checks that the upstream source still matches the source recorded in the vendor lock file
+func verifyFrozenVendorLockSource(locked *schema.AtmosVendorLockSource, current schema.AtmosVendorLockSource) error {
+	if locked == nil {
+		return fmt.Errorf("the source '%s' for the target '%s' is not found in the vendor lock file", current.Source, current.Target)
+	}
+	if locked.Resolved != "" && locked.Resolved != current.Resolved {
+		return fmt.Errorf("the upstream revision '%s' does not match the locked revision '%s'", current.Resolved, locked.Resolved)
+	}
+	if locked.TreeHash != current.TreeHash {
+		return fmt.Errorf("the upstream tree hash '%s' does not match the locked tree hash '%s'", current.TreeHash, locked.TreeHash)
+	}
+	return nil
+}
+
+// verifyVendorLockSource checks the local files against the hashes recorded in the vendor lock file and returns the problems found
+func verifyVendorLockSource(vendorConfigFilePath string, source schema.AtmosVendorLockSource) ([]string, error) {
+	var problems []string
+	files := map[string]string{}
+
+	for _, rel := range lo.Keys(source.Files) {
+		localFile := filepath.Join(vendorConfigFilePath, filepath.FromSlash(rel))
+
+		if !u.FileExists(localFile) {
+			problems = append(problems, fmt.Sprintf("missing: %s", rel))
+			continue
+		}
+
+		hash, err := hashVendorFile(localFile)
+		if err != nil {
+			return nil, err
+		}
+		if hash != source.Files[rel] {
+			problems = append(problems, fmt.Sprintf("modified: %s", rel))
+		}
+		files[rel] = hash
+	}
+
+	// The tree hash describes the upstream files, so it only matches the local files if no patches were applied
+	if len(problems) == 0 && len(source.Patches) == 0 && vendorTreeHash(source.Target, files) != source.TreeHash {
+		problems = append(problems, fmt.Sprintf("the tree hash does not match '%s'", source.TreeHash))
+	}
+
+	for _, rel := range lo.Keys(source.Patches) {
+		patchFile := filepath.Join(vendorConfigFilePath, filepath.FromSlash(rel))
+
+		if !u.FileExists(patchFile) {
+			problems = append(problems, fmt.Sprintf("missing patch: %s", rel))
+			continue
+		}
+
+		hash, err := hashVendorFile(patchFile)
+		if err != nil {
+			return nil, err
+		}
+		if hash != source.Patches[rel] {
+			problems = append(problems, fmt.Sprintf("modified patch: %s", rel))
+		}
+	}
+
+	sort.Strings(problems)
+	return problems, nil
+}
+
+// getVendorTargetPath returns the path the files are copied to.
+// If the source is a local file and the target has no extension, the file is copied into the target folder
+func getVendorTargetPath(targetPath string, sourceIsLocalFile bool, uri string) string {
+	if sourceIsLocalFile && filepath.Ext(targetPath) == "" {
+		// Sanitize the URI for safe filenames, especially on Windows
+		return filepath.Join(targetPath, SanitizeFileName(uri))
+	}
+	return targetPath
+}
+
+// collectVendorCopiedFiles finds the files that are copied from the source to the destination,
+// following the same rules as the copy operation (the skip function is not applied to the root, and symlinks are followed).
+// It adds the destination files mapped to the source files to the `files` map
+func collectVendorCopiedFiles(
+	src string,
+	dest string,
+	skip func(os.FileInfo, string, string) (bool, error),
+	files map[string]string,
+	isRoot bool,
+) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+
+	if !isRoot && skip != nil {
+		skipped, err := skip(info, src, dest)
+		if err != nil {
+			return err
+		}
+		if skipped {
+			return nil
+		}
+	}
+
+	if !info.IsDir() {
+		files[dest] = src
+		return nil
+	}
+
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if err := collectVendorCopiedFiles(filepath.Join(src, entry.Name()), filepath.Join(dest, entry.Name()), skip, files, false); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// hashVendorFile returns the SHA-256 hash of the file content
+func hashVendorFile(file string) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer closeFile(file, f)
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// vendorTreeHash calculates the SHA-256 hash of the files (the paths relative to the target and the file hashes)
+func vendorTreeHash(target string, files map[string]string) string {
+	keys := lo.Keys(files)
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, k := range keys {
+		rel := strings.TrimPrefix(strings.TrimPrefix(k, target), "/")
+		_, _ = fmt.Fprintf(h, "%s\x00%s\n", rel, files[k])
+	}
+
+	return "sha256:" + hex.EncodeToString(h.Sum(nil))
+}
+
+// resolveVendorSourceRevision returns the commit SHA of a Git source or the digest of an OCI image.
+// For other sources, it returns an empty string.
+// The resolved revisions are memoized, so the sources shared by multiple targets are resolved only once
+func resolveVendorSourceRevision(sourceType pkgType, uri string) (string, error) {
+	key := sourceType.String() + "|" + uri
+	if resolved, ok := resolvedVendorRevisions.Load(key); ok {
+		return resolved.(string), nil
+	}
+
+	var resolved string
+	var err error
+
+	switch sourceType {
+	case pkgTypeOci:
+		resolved, err = resolveOciImageDigest(uri)
+	case pkgTypeRemote:
+		resolved, err = resolveGitRevision(uri)
+	default:
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	resolvedVendorRevisions.Store(key, resolved)
+	return resolved, nil
+}
+
+// resolveGitRevision resolves the `ref` of a Git source to the commit SHA using `git ls-remote`.
+// It returns an empty string if the source is not a Git repository
+func resolveGitRevision(uri string) (string, error) {
+	repoURL, ref, isGit, err := parseGitSource(uri)
+	if err != nil || !isGit {
+		return "", err
+	}
+
+	if gitCommitShaRegex.MatchString(ref) {
+		return ref, nil
+	}
+
+	args := []string{repoURL}
+	if ref != "" {
+		args = append(args, ref, ref+"^{}")
+	} else {
+		args = append(args, "HEAD")
+	}
+
+	refs, err := gitLsRemote(args...)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve the Git ref '%s': %w", ref, err)
+	}
+
+	resolved := ""
+	for _, r := range refs {
+		// Prefer the commit that an annotated tag points to
+		if strings.HasSuffix(r[1], "^{}") {
+			return r[0], nil
+		}
+		if resolved == "" {
+			resolved = r[0]
+		}
+	}
+
+	if resolved == "" {
+		return "", fmt.Errorf("the Git ref '%s' is not found in the repository", ref)
+	}
+
+	return resolved, nil
+}
+
+// parseGitSource detects the go-getter source and returns the Git repository URL (without the subdirectory and the query parameters)
+// and the `ref` query parameter. It returns `false` if the source is not a Git repository
+func parseGitSource(uri string) (string, string, bool, error) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		return "", "", false, err
+	}
+
+	src, err := getter.Detect(uri, pwd, getter.Detectors)
+	if err != nil {
+		return "", "", false, err
+	}
+
+	if !strings.HasPrefix(src, "git::") {
+		return "", "", false, nil
+	}
+
+	src, _ = getter.SourceDirSubdir(strings.TrimPrefix(src, "git::"))
+
+	parsedURL, err := url.Parse(src)
+	if err != nil {
+		return "", "", false, err
+	}
+
+	query := parsedURL.Query()
+	ref := query.Get("ref")
+	query.Del("ref")
+	query.Del("depth")
+	query.Del("sshkey")
+	parsedURL.RawQuery = query.Encode()
+
+	return parsedURL.String(), ref, true, nil
+}
+
+// gitLsRemote executes `git ls-remote` with the provided arguments and returns the pairs of the commit SHA and the ref name
+func gitLsRemote(args ...string) ([][2]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	output, err := osexec.CommandContext(ctx, "git", append([]string{"ls-remote"}, args...)...).Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var refs [][2]string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		refs = append(refs, [2]string{fields[0], fields[1]})
+	}
+
+	return refs, nil
+}
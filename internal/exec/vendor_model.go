@@ -12,8 +12,9 @@ import (
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/hashicorp/go-getter"
+	"github.com/google/go-containerregistry/pkg/name"
 	cp "github.com/otiai10/copy"
+	"github.com/samber/lo"
 
 	"github.com/cloudposse/atmos/internal/tui/templates/term"
 	"github.com/cloudposse/atmos/pkg/schema"
@@ -45,13 +46,16 @@ type pkgVendor struct {
 }
 
 type pkgAtmosVendor struct {
-	uri               string
-	name              string
-	targetPath        string
-	sourceIsLocalFile bool
-	pkgType           pkgType
-	version           string
-	atmosVendorSource schema.AtmosVendorSource
+	uri                  string
+	name                 string
+	targetPath           string
+	sourceIsLocalFile    bool
+	pkgType              pkgType
+	version              string
+	atmosVendorSource    schema.AtmosVendorSource
+	vendorConfigFilePath string
+	frozen               bool
+	lockedSource         *schema.AtmosVendorLockSource
 }
 
 type modelVendor struct {
@@ -64,8 +68,21 @@ type modelVendor struct {
 	done        bool
 	dryRun      bool
 	failedPkg   int
+	failedPkgs  []string
+	skippedPkg  int
 	atmosConfig schema.AtmosConfiguration
 	isTTY       bool
+	lockSources []schema.AtmosVendorLockSource
+	concurrency int
+	started     []bool
+	completed   []bool
+	inFlight    []int
+
+	// lockMismatchPkgs are the failed packages that do not match the vendor lock file (in the `--frozen` mode)
+	lockMismatchPkgs []string
+
+	// interruptedPkgs are the packages that were being installed when the vendoring was interrupted
+	interruptedPkgs []string
 }
 
 var (
@@ -104,15 +121,103 @@ func newModelAtmosVendorInternal(pkgs []pkgAtmosVendor, dryRun bool, atmosConfig
 		dryRun:      dryRun,
 		atmosConfig: atmosConfig,
 		isTTY:       isTTY,
+		concurrency: getVendorConcurrency(atmosConfig),
+		started:     make([]bool, len(vendorPks)),
+		completed:   make([]bool, len(vendorPks)),
 	}, nil
 }
 
+// getVendorConcurrency returns the maximum number of packages installed in parallel (`vendor.concurrency` in `atmos.yaml`)
+func getVendorConcurrency(atmosConfig schema.AtmosConfiguration) int {
+	if atmosConfig.Vendor.Concurrency < 1 {
+		return 1
+	}
+	return atmosConfig.Vendor.Concurrency
+}
+
 func (m *modelVendor) Init() tea.Cmd {
 	if len(m.packages) == 0 {
 		m.done = true
 		return nil
 	}
-	return tea.Batch(ExecuteInstall(m.packages[0], m.dryRun, m.atmosConfig), m.spinner.Tick)
+	return tea.Batch(append(m.startInstalls(), m.spinner.Tick)...)
+}
+
+// startInstalls starts installing the pending packages, up to the concurrency limit.
+// Packages with overlapping targets are installed one at a time in the order they are defined,
+// so the later packages (e.g. mixins) still overwrite the files of the earlier ones
+func (m *modelVendor) startInstalls() []tea.Cmd {
+	var cmds []tea.Cmd
+
+	for i := range m.packages {
+		if len(m.inFlight) >= m.concurrency {
+			break
+		}
+		if m.started[i] || m.isTargetBusy(i) {
+			continue
+		}
+
+		m.started[i] = true
+		m.inFlight = append(m.inFlight, i)
+
+		if !m.isTTY {
+			u.LogInfo(fmt.Sprintf("Pulling %s %s", m.packages[i].name, m.packages[i].versionLabel()))
+		}
+
+		cmds = append(cmds, m.install(i))
+	}
+
+	return cmds
+}
+
+// isTargetBusy checks if an earlier package that is not installed yet has a target that overlaps with the target of the package
+func (m *modelVendor) isTargetBusy(index int) bool {
+	target := m.packages[index].target()
+	for i := 0; i < index; i++ {
+		if !m.completed[i] && vendorTargetsOverlap(target, m.packages[i].target()) {
+			return true
+		}
+	}
+	return false
+}
+
+// install returns the command that installs the package and reports its index in the result
+func (m *modelVendor) install(index int) tea.Cmd {
+	installCmd := ExecuteInstall(m.packages[index], m.dryRun, m.atmosConfig)
+	return func() tea.Msg {
+		msg, ok := installCmd().(installedPkgMsg)
+		if !ok {
+			return installedPkgMsg{
+				err:   fmt.Errorf("unexpected result when installing %s", m.packages[index].name),
+				name:  m.packages[index].name,
+				index: index,
+			}
+		}
+		msg.index = index
+		return msg
+	}
+}
+
+// summary returns the summary of the succeeded, failed and skipped packages
+func (m *modelVendor) summary() string {
+	if m.dryRun {
+		return "Done! Dry run completed. No components vendored.\n"
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Vendored %d components.", len(m.packages)-m.failedPkg-m.skippedPkg-len(m.interruptedPkgs)))
+	if m.failedPkg > 0 {
+		sb.WriteString(fmt.Sprintf(" Failed to vendor %d components: %s.", m.failedPkg, strings.Join(m.failedPkgs, ", ")))
+	}
+	if len(m.interruptedPkgs) > 0 {
+		sb.WriteString(fmt.Sprintf(" Interrupted %d components: %s.", len(m.interruptedPkgs), strings.Join(m.interruptedPkgs, ", ")))
+	}
+	if m.skippedPkg > 0 {
+		sb.WriteString(fmt.Sprintf(" Skipped %d components.", m.skippedPkg))
+	}
+	sb.WriteString("\n")
+
+	return sb.String()
 }
 
 func (m *modelVendor) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -126,15 +231,29 @@ func (m *modelVendor) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "ctrl+c", "esc", "q":
+			// The packages that are being installed are interrupted, and the packages that are not started yet are skipped
+			m.interruptedPkgs = lo.Map(m.inFlight, func(i int, _ int) string {
+				return m.packages[i].name
+			})
+			m.skippedPkg = len(m.packages) - m.index - len(m.inFlight)
+			m.done = true
 			return m, tea.Quit
 		}
 
 	case installedPkgMsg:
 		// ensure index is within bounds
-		if m.index >= len(m.packages) {
+		if msg.index < 0 || msg.index >= len(m.packages) || m.completed[msg.index] {
 			return m, nil
 		}
-		pkg := m.packages[m.index]
+		pkg := m.packages[msg.index]
+
+		m.completed[msg.index] = true
+		m.inFlight = lo.Without(m.inFlight, msg.index)
+		m.index++
+
+		if msg.lockSource != nil {
+			m.lockSources = append(m.lockSources, *msg.lockSource)
+		}
 
 		mark := checkMark
 		errMsg := ""
@@ -145,43 +264,33 @@ func (m *modelVendor) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			mark = xMark
 			m.failedPkg++
+			m.failedPkgs = append(m.failedPkgs, pkg.name)
+			if msg.lockMismatch {
+				m.lockMismatchPkgs = append(m.lockMismatchPkgs, pkg.name)
+			}
 		}
-		version := ""
-		if pkg.version != "" {
-			version = fmt.Sprintf("(%s)", pkg.version)
+		version := pkg.versionLabel()
+		if !m.isTTY {
+			u.LogInfo(fmt.Sprintf("%s %s %s", mark, pkg.name, version))
 		}
-		if m.index >= len(m.packages)-1 {
+		version = grayColor.Render(version)
+		printCmd := tea.Printf("%s %s %s %s", mark, pkg.name, version, errMsg) // print message above our program
+
+		if m.index >= len(m.packages) {
 			// Everything's been installed. We're done!
 			m.done = true
 			if !m.isTTY {
-				u.LogInfo(fmt.Sprintf("%s %s %s", mark, pkg.name, version))
-				if m.dryRun {
-					u.LogInfo("Done! Dry run completed. No components vendored.\n")
-				}
-				if m.failedPkg > 0 {
-					u.LogInfo(fmt.Sprintf("Vendored %d components. Failed to vendor %d components.\n", len(m.packages)-m.failedPkg, m.failedPkg))
-				}
-				u.LogInfo(fmt.Sprintf("Vendored %d components.\n", len(m.packages)))
+				u.LogInfo(m.summary())
 			}
-			version := grayColor.Render(version)
-			return m, tea.Sequence(
-				tea.Printf("%s %s %s %s", mark, pkg.name, version, errMsg),
-				tea.Quit,
-			)
-		}
-		if !m.isTTY {
-			u.LogInfo(fmt.Sprintf("%s %s %s", mark, pkg.name, version))
+			return m, tea.Sequence(printCmd, tea.Quit)
 		}
-		m.index++
+
 		// Update progress bar
 		progressCmd := m.progress.SetPercent(float64(m.index) / float64(len(m.packages)))
 
-		version = grayColor.Render(version)
-		return m, tea.Batch(
-			progressCmd,
-			tea.Printf("%s %s %s %s", mark, pkg.name, version, errMsg),   // print message above our program
-			ExecuteInstall(m.packages[m.index], m.dryRun, m.atmosConfig), // download the next package
-		)
+		// Download the next packages
+		return m, tea.Batch(append([]tea.Cmd{progressCmd, printCmd}, m.startInstalls()...)...)
+
 	case spinner.TickMsg:
 		var cmd tea.Cmd
 		m.spinner, cmd = m.spinner.Update(msg)
@@ -200,25 +309,21 @@ func (m modelVendor) View() string {
 	n := len(m.packages)
 	w := lipgloss.Width(fmt.Sprintf("%d", n))
 	if m.done {
-		if m.dryRun {
-			return doneStyle.Render("Done! Dry run completed. No components vendored.\n")
-		}
-		if m.failedPkg > 0 {
-			return doneStyle.Render(fmt.Sprintf("Vendored %d components. Failed to vendor %d components.\n", n-m.failedPkg, m.failedPkg))
-		}
-		return doneStyle.Render(fmt.Sprintf("Vendored %d components.\n", n))
+		return doneStyle.Render(m.summary())
 	}
 
 	pkgCount := fmt.Sprintf(" %*d/%*d", w, m.index, w, n)
 	spin := m.spinner.View() + " "
 	prog := m.progress.View()
 	cellsAvail := max(0, m.width-lipgloss.Width(spin+prog+pkgCount))
-	if m.index >= len(m.packages) {
+	if len(m.inFlight) == 0 {
 		return ""
 	}
-	pkgName := currentPkgNameStyle.Render(m.packages[m.index].name)
+	pkgNames := lo.Map(m.inFlight, func(i int, _ int) string {
+		return currentPkgNameStyle.Render(m.packages[i].name)
+	})
 
-	info := lipgloss.NewStyle().MaxWidth(cellsAvail).Render("Pulling " + pkgName)
+	info := lipgloss.NewStyle().MaxWidth(cellsAvail).Render("Pulling " + strings.Join(pkgNames, ", "))
 
 	cellsRemaining := max(0, m.width-lipgloss.Width(spin+info+prog+pkgCount))
 	gap := strings.Repeat(" ", cellsRemaining)
@@ -227,8 +332,41 @@ func (m modelVendor) View() string {
 }
 
 type installedPkgMsg struct {
-	err  error
-	name string
+	err        error
+	name       string
+	index      int
+	lockSource *schema.AtmosVendorLockSource
+	// lockMismatch is `true` if the upstream source does not match the vendor lock file (in the `--frozen` mode)
+	lockMismatch bool
+}
+
+// target returns the target path of the package
+func (p pkgVendor) target() string {
+	if p.atmosPackage != nil {
+		return p.atmosPackage.targetPath
+	}
+	if p.componentPackage != nil {
+		return p.componentPackage.componentPath
+	}
+	return ""
+}
+
+// versionLabel returns the version of the package for display
+func (p pkgVendor) versionLabel() string {
+	if p.version == "" {
+		return ""
+	}
+	return fmt.Sprintf("(%s)", p.version)
+}
+
+// vendorTargetsOverlap checks if the target paths are the same, or if one of them is inside the other
+func vendorTargetsOverlap(target1 string, target2 string) bool {
+	target1 = filepath.Clean(target1)
+	target2 = filepath.Clean(target2)
+	if target1 == target2 {
+		return true
+	}
+	return strings.HasPrefix(target1, target2+string(filepath.Separator)) || strings.HasPrefix(target2, target1+string(filepath.Separator))
 }
 
 func max(a, b int) int {
@@ -266,57 +404,84 @@ func downloadAndInstall(p *pkgAtmosVendor, dryRun bool, atmosConfig schema.Atmos
 
 		defer removeTempDir(atmosConfig, tempDir)
 
-		switch p.pkgType {
-		case pkgTypeRemote:
-			// Use go-getter to download remote packages
-			if err := GoGetterGet(atmosConfig, p.uri, tempDir, getter.ClientModeAny, 10*time.Minute); err != nil {
+		download, err := getVerifiedVendorPackage(p)
+		if err != nil {
+			return installedPkgMsg{
+				err:  err,
+				name: p.name,
+			}
+		}
+
+		tempDir, err = downloadAtmosVendorPackage(download, tempDir, atmosConfig)
+		if err != nil {
+			return installedPkgMsg{
+				err:  err,
+				name: p.name,
+			}
+		}
+
+		// Resolve the revision and hash the files for the vendor lock file
+		lockSource, err := newVendorLockSource(atmosConfig, p, tempDir)
+		if err != nil {
+			return installedPkgMsg{
+				err:  fmt.Errorf("failed to lock package: %w", err),
+				name: p.name,
+			}
+		}
+
+		// Verify the checksum before copying the files to the target.
+		// The checksums of the archives are verified by go-getter before unpacking them
+		if p.atmosVendorSource.Checksum != "" && !(p.pkgType == pkgTypeRemote && isVendorArchiveSource(p.uri)) {
+			if err := verifyVendorChecksum(p.atmosVendorSource.Checksum, lockSource); err != nil {
 				return installedPkgMsg{
-					err:  fmt.Errorf("failed to download package: %w", err),
+					err:  err,
 					name: p.name,
 				}
 			}
+		}
 
-		case pkgTypeOci:
-			// Process OCI images
-			if err := processOciImage(atmosConfig, p.uri, tempDir); err != nil {
+		if p.frozen {
+			if err := verifyFrozenVendorLockSource(p.lockedSource, lockSource); err != nil {
 				return installedPkgMsg{
-					err:  fmt.Errorf("failed to process OCI image: %w", err),
-					name: p.name,
+					err:          err,
+					name:         p.name,
+					lockMismatch: true,
 				}
 			}
+		}
 
-		case pkgTypeLocal:
-			// Copy from local file system
-			copyOptions := cp.Options{
-				PreserveTimes: false,
-				PreserveOwner: false,
-				OnSymlink:     func(src string) cp.SymlinkAction { return cp.Deep },
-			}
-			if p.sourceIsLocalFile {
-				tempDir = filepath.Join(tempDir, SanitizeFileName(p.uri))
-			}
-			if err := cp.Copy(p.uri, tempDir, copyOptions); err != nil {
+		if len(p.atmosVendorSource.Patches) == 0 {
+			if err := copyToTarget(atmosConfig, tempDir, p.targetPath, &p.atmosVendorSource, p.sourceIsLocalFile, p.uri); err != nil {
 				return installedPkgMsg{
 					err:  fmt.Errorf("failed to copy package: %w", err),
 					name: p.name,
 				}
 			}
-		default:
-			return installedPkgMsg{
-				err:  fmt.Errorf("unknown package type %s for package %s", p.pkgType.String(), p.name),
-				name: p.name,
+		} else {
+			// Apply the local patches on top of the upstream files
+			patchFiles := resolveVendorPatchPaths(p.vendorConfigFilePath, p.atmosVendorSource.Patches)
+
+			patchRoot, changed, err := copyWithVendorPatches(atmosConfig, p.targetPath, patchFiles, func(stagingTarget string) error {
+				return copyToTarget(atmosConfig, tempDir, stagingTarget, &p.atmosVendorSource, p.sourceIsLocalFile, p.uri)
+			})
+			if err != nil {
+				return installedPkgMsg{
+					err:  err,
+					name: p.name,
+				}
 			}
 
-		}
-		if err := copyToTarget(atmosConfig, tempDir, p.targetPath, &p.atmosVendorSource, p.sourceIsLocalFile, p.uri); err != nil {
-			return installedPkgMsg{
-				err:  fmt.Errorf("failed to copy package: %w", err),
-				name: p.name,
+			if err := updateVendorLockSourcePatches(&lockSource, p.vendorConfigFilePath, patchRoot, patchFiles, changed); err != nil {
+				return installedPkgMsg{
+					err:  fmt.Errorf("failed to lock package: %w", err),
+					name: p.name,
+				}
 			}
 		}
 		return installedPkgMsg{
-			err:  nil,
-			name: p.name,
+			err:        nil,
+			name:       p.name,
+			lockSource: &lockSource,
 		}
 	}
 }
@@ -339,3 +504,95 @@ func ExecuteInstall(installer pkgVendor, dryRun bool, atmosConfig schema.AtmosCo
 		}
 	}
 }
+
+// getVerifiedVendorPackage returns the package to download.
+// If the source has a 'signature', the signature of the OCI image is verified, and the package is pinned to the verified digest.
+// If the source is an archive with a 'checksum', the checksum is passed to go-getter to verify the archive before unpacking it
+func getVerifiedVendorPackage(p *pkgAtmosVendor) (*pkgAtmosVendor, error) {
+	download := *p
+
+	if p.pkgType == pkgTypeOci && p.atmosVendorSource.Signature != nil {
+		digest, err := resolveVendorSourceRevision(p.pkgType, p.uri)
+		if err != nil {
+			return nil, fmt.Errorf("failed to verify signature: %w", err)
+		}
+
+		if err := verifyVendorSignature(p.uri, digest, p.atmosVendorSource.Signature, p.vendorConfigFilePath); err != nil {
+			return nil, fmt.Errorf("failed to verify signature: %w", err)
+		}
+
+		ref, err := name.ParseReference(p.uri)
+		if err != nil {
+			return nil, err
+		}
+		download.uri = ref.Context().Digest(digest).Name()
+	}
+
+	if p.pkgType == pkgTypeRemote && p.atmosVendorSource.Checksum != "" && isVendorArchiveSource(p.uri) {
+		download.uri = addVendorArchiveChecksum(p.uri, p.atmosVendorSource.Checksum)
+	}
+
+	return &download, nil
+}
+
+// downloadAtmosVendorPackage downloads the package into the temp directory and returns the directory to copy the files from
+func downloadAtmosVendorPackage(p *pkgAtmosVendor, tempDir string, atmosConfig schema.AtmosConfiguration) (string, error) {
+	switch p.pkgType {
+	case pkgTypeRemote:
+		// Use go-getter to download remote packages (or copy them from the vendor cache)
+		if err := downloadVendorSource(atmosConfig, p.pkgType, p.uri, tempDir); err != nil {
+			return tempDir, fmt.Errorf("failed to download package: %w", err)
+		}
+
+	case pkgTypeOci:
+		// Process OCI images (or copy them from the vendor cache)
+		if err := downloadVendorSource(atmosConfig, p.pkgType, p.uri, tempDir); err != nil {
+			return tempDir, fmt.Errorf("failed to process OCI image: %w", err)
+		}
+
+	case pkgTypeLocal:
+		// Copy from local file system
+		copyOptions := cp.Options{
+			PreserveTimes: false,
+			PreserveOwner: false,
+			OnSymlink:     func(src string) cp.SymlinkAction { return cp.Deep },
+		}
+		if p.sourceIsLocalFile {
+			tempDir = filepath.Join(tempDir, SanitizeFileName(p.uri))
+		}
+		if err := cp.Copy(p.uri, tempDir, copyOptions); err != nil {
+			return tempDir, fmt.Errorf("failed to copy package: %w", err)
+		}
+
+	default:
+		return tempDir, fmt.Errorf("unknown package type %s for package %s", p.pkgType.String(), p.name)
+	}
+
+	return tempDir, nil
+}
+
+// vendorRetryBaseDelay is the delay before the first retry of a failed download. The delay doubles with every retry
+var vendorRetryBaseDelay = 2 * time.Second
+
+// retryVendorDownload runs the download, and retries it with exponential backoff if it fails
+// (up to `vendor.retries` times as configured in `atmos.yaml`).
+// The attempt number (starting from 0) is passed to the download function
+func retryVendorDownload(atmosConfig schema.AtmosConfiguration, source string, download func(attempt int) error) error {
+	retries := max(atmosConfig.Vendor.Retries, 0)
+	delay := vendorRetryBaseDelay
+
+	var err error
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			u.LogWarning(fmt.Sprintf("Failed to download '%s' (attempt %d of %d): %v. Retrying in %s", source, attempt, retries+1, err, delay))
+			time.Sleep(delay)
+			delay *= 2
+		}
+
+		if err = download(attempt); err == nil {
+			return nil
+		}
+	}
+
+	return err
+}
@@ -60,6 +60,9 @@ func newModelComponentVendorInternal(pkgs []pkgComponentVendor, dryRun bool, atm
 		dryRun:      dryRun,
 		atmosConfig: atmosConfig,
 		isTTY:       tty,
+		concurrency: getVendorConcurrency(atmosConfig),
+		started:     make([]bool, len(vendorPks)),
+		completed:   make([]bool, len(vendorPks)),
 	}, nil
 }
 
@@ -123,19 +126,83 @@ func installComponent(p *pkgComponentVendor, atmosConfig schema.AtmosConfigurati
 
 	defer removeTempDir(atmosConfig, tempDir)
 
+	tempDir, err = downloadComponentPackage(p, tempDir, atmosConfig)
+	if err != nil {
+		return err
+	}
+
+	if len(p.vendorComponentSpec.Patches) == 0 {
+		if err = copyComponentToDestination(atmosConfig, tempDir, p.componentPath, p.vendorComponentSpec, p.sourceIsLocalFile, p.uri); err != nil {
+			return fmt.Errorf("failed to copy package %s error %s", p.name, err)
+		}
+		return nil
+	}
+
+	// Apply the local patches on top of the upstream files
+	patchFiles := resolveVendorPatchPaths(p.componentPath, p.vendorComponentSpec.Patches)
+
+	_, _, err = copyWithVendorPatches(atmosConfig, p.componentPath, patchFiles, func(stagingTarget string) error {
+		return copyComponentToDestination(atmosConfig, tempDir, stagingTarget, p.vendorComponentSpec, p.sourceIsLocalFile, p.uri)
+	})
+	if err != nil {
+		return fmt.Errorf("package %s: %w", p.name, err)
+	}
+
+	return nil
+}
+
+func installMixin(p *pkgComponentVendor, atmosConfig schema.AtmosConfiguration) error {
+	tempDir, err := os.MkdirTemp("", strconv.FormatInt(time.Now().Unix(), 10))
+	if err != nil {
+		return fmt.Errorf("Failed to create temp directory %s", err)
+	}
+
+	defer removeTempDir(atmosConfig, tempDir)
+
+	if err = downloadMixinPackage(p, tempDir, atmosConfig); err != nil {
+		return err
+	}
+
+	// Copy from the temp folder to the destination folder
+	copyOptions := cp.Options{
+		// Preserve the atime and the mtime of the entries
+		PreserveTimes: false,
+
+		// Preserve the uid and the gid of all entries
+		PreserveOwner: false,
+
+		// OnSymlink specifies what to do on symlink
+		// Override the destination file if it already exists
+		// Prevent the error:
+		// symlink components/terraform/mixins/context.tf components/terraform/infra/vpc-flow-logs-bucket/context.tf: file exists
+		OnSymlink: func(src string) cp.SymlinkAction {
+			return cp.Deep
+		},
+	}
+
+	if err = cp.Copy(tempDir, p.componentPath, copyOptions); err != nil {
+		return fmt.Errorf("Failed to copy package %s error %s", p.name, err)
+	}
+
+	return nil
+}
+
+// downloadComponentPackage downloads the component into the temp directory and returns the directory to copy the files from
+func downloadComponentPackage(p *pkgComponentVendor, tempDir string, atmosConfig schema.AtmosConfiguration) (string, error) {
 	switch p.pkgType {
 	case pkgTypeRemote:
 		tempDir = filepath.Join(tempDir, SanitizeFileName(p.uri))
 
-		if err = GoGetterGet(atmosConfig, p.uri, tempDir, getter.ClientModeAny, 10*time.Minute); err != nil {
-			return fmt.Errorf("failed to download package %s error %s", p.name, err)
+		// Use go-getter to download remote packages (or copy them from the vendor cache)
+		if err := downloadVendorSource(atmosConfig, p.pkgType, p.uri, tempDir); err != nil {
+			return tempDir, fmt.Errorf("failed to download package %s error %s", p.name, err)
 		}
 
 	case pkgTypeOci:
 		// Download the Image from the OCI-compatible registry, extract the layers from the tarball, and write to the destination directory
-		err = processOciImage(atmosConfig, p.uri, tempDir)
-		if err != nil {
-			return fmt.Errorf("Failed to process OCI image %s error %s", p.name, err)
+		// (or copy it from the vendor cache)
+		if err := downloadVendorSource(atmosConfig, p.pkgType, p.uri, tempDir); err != nil {
+			return tempDir, fmt.Errorf("Failed to process OCI image %s error %s", p.name, err)
 		}
 
 	case pkgTypeLocal:
@@ -154,38 +221,31 @@ func installComponent(p *pkgComponentVendor, atmosConfig schema.AtmosConfigurati
 			tempDir2 = filepath.Join(tempDir, SanitizeFileName(p.uri))
 		}
 
-		if err = cp.Copy(p.uri, tempDir2, copyOptions); err != nil {
-			return fmt.Errorf("failed to copy package %s error %s", p.name, err)
+		if err := cp.Copy(p.uri, tempDir2, copyOptions); err != nil {
+			return tempDir, fmt.Errorf("failed to copy package %s error %s", p.name, err)
 		}
 	default:
-		return fmt.Errorf("unknown package type %s package %s", p.pkgType.String(), p.name)
+		return tempDir, fmt.Errorf("unknown package type %s package %s", p.pkgType.String(), p.name)
 
 	}
-	if err = copyComponentToDestination(atmosConfig, tempDir, p.componentPath, p.vendorComponentSpec, p.sourceIsLocalFile, p.uri); err != nil {
-		return fmt.Errorf("failed to copy package %s error %s", p.name, err)
-	}
 
-	return nil
+	return tempDir, nil
 }
 
-func installMixin(p *pkgComponentVendor, atmosConfig schema.AtmosConfiguration) error {
-	tempDir, err := os.MkdirTemp("", strconv.FormatInt(time.Now().Unix(), 10))
-	if err != nil {
-		return fmt.Errorf("Failed to create temp directory %s", err)
-	}
-
-	defer removeTempDir(atmosConfig, tempDir)
-
+// downloadMixinPackage downloads the mixin into the temp directory
+func downloadMixinPackage(p *pkgComponentVendor, tempDir string, atmosConfig schema.AtmosConfiguration) error {
 	switch p.pkgType {
 	case pkgTypeRemote:
-		if err = GoGetterGet(atmosConfig, p.uri, filepath.Join(tempDir, p.mixinFilename), getter.ClientModeFile, 10*time.Minute); err != nil {
+		err := retryVendorDownload(atmosConfig, normalizeVendorCacheURI(p.uri), func(attempt int) error {
+			return GoGetterGet(atmosConfig, p.uri, filepath.Join(tempDir, p.mixinFilename), getter.ClientModeFile, 10*time.Minute)
+		})
+		if err != nil {
 			return fmt.Errorf("failed to download package %s error %s", p.name, err)
 		}
 
 	case pkgTypeOci:
 		// Download the Image from the OCI-compatible registry, extract the layers from the tarball, and write to the destination directory
-		err = processOciImage(atmosConfig, p.uri, tempDir)
-		if err != nil {
+		if err := processOciImage(atmosConfig, p.uri, tempDir); err != nil {
 			return fmt.Errorf("failed to process OCI image %s error %s", p.name, err)
 		}
 
@@ -193,32 +253,25 @@ func installMixin(p *pkgComponentVendor, atmosConfig schema.AtmosConfiguration)
 		if p.uri == "" {
 			return fmt.Errorf("local mixin URI cannot be empty")
 		}
-		// Implement local mixin installation logic
-		return fmt.Errorf("local mixin installation not implemented")
-
-	default:
-		return fmt.Errorf("unknown package type %s package %s", p.pkgType.String(), p.name)
-	}
 
-	// Copy from the temp folder to the destination folder
-	copyOptions := cp.Options{
-		// Preserve the atime and the mtime of the entries
-		PreserveTimes: false,
-
-		// Preserve the uid and the gid of all entries
-		PreserveOwner: false,
+		copyOptions := cp.Options{
+			PreserveTimes: false,
+			PreserveOwner: false,
+			// OnSymlink specifies what to do on symlink
+			// Override the destination file if it already exists
+			OnSymlink: func(src string) cp.SymlinkAction {
+				return cp.Deep
+			},
+		}
 
-		// OnSymlink specifies what to do on symlink
-		// Override the destination file if it already exists
-		// Prevent the error:
-		// symlink components/terraform/mixins/context.tf components/terraform/infra/vpc-flow-logs-bucket/context.tf: file exists
-		OnSymlink: func(src string) cp.SymlinkAction {
-			return cp.Deep
-		},
-	}
+		// A local file is copied to 'filename' in the component folder.
+		// The content of a local directory is copied into the 'filename' sub-folder of the component folder ('.' for the component folder itself).
+		if err := cp.Copy(p.uri, filepath.Join(tempDir, p.mixinFilename), copyOptions); err != nil {
+			return fmt.Errorf("failed to copy package %s error %s", p.name, err)
+		}
 
-	if err = cp.Copy(tempDir, p.componentPath, copyOptions); err != nil {
-		return fmt.Errorf("Failed to copy package %s error %s", p.name, err)
+	default:
+		return fmt.Errorf("unknown package type %s package %s", p.pkgType.String(), p.name)
 	}
 
 	return nil
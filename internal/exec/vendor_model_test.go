@@ -0,0 +1,133 @@
+package exec
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudposse/atmos/pkg/schema"
+)
+
+func TestVendorModelConcurrency(t *testing.T) {
+	atmosConfig := schema.AtmosConfiguration{}
+	atmosConfig.Vendor.Concurrency = 2
+
+	packages := []pkgAtmosVendor{
+		{name: "vpc", targetPath: filepath.Join("components", "terraform", "vpc")},
+		{name: "vpc-mixin", targetPath: filepath.Join("components", "terraform", "vpc")},
+		{name: "eks", targetPath: filepath.Join("components", "terraform", "eks")},
+		{name: "rds", targetPath: filepath.Join("components", "terraform", "rds")},
+	}
+
+	model, err := newModelAtmosVendorInternal(packages, true, atmosConfig)
+	require.NoError(t, err)
+
+	// The mixin has the same target as the first package, so it waits for the first package to finish
+	cmds := model.startInstalls()
+	assert.Len(t, cmds, 2)
+	assert.Equal(t, []int{0, 2}, model.inFlight)
+
+	_, _ = model.Update(installedPkgMsg{name: "eks", index: 2})
+	assert.Equal(t, []int{0, 3}, model.inFlight)
+
+	_, _ = model.Update(installedPkgMsg{name: "vpc", index: 0, err: errors.New("download failed")})
+	assert.Equal(t, []int{3, 1}, model.inFlight)
+
+	_, _ = model.Update(installedPkgMsg{name: "rds", index: 3})
+	_, _ = model.Update(installedPkgMsg{name: "vpc-mixin", index: 1})
+	assert.True(t, model.done)
+	assert.Equal(t, 1, model.failedPkg)
+	assert.Equal(t, []string{"vpc"}, model.failedPkgs)
+}
+
+func TestVendorModelInterrupted(t *testing.T) {
+	atmosConfig := schema.AtmosConfiguration{}
+	atmosConfig.Vendor.Concurrency = 2
+
+	packages := []pkgAtmosVendor{
+		{name: "vpc", targetPath: filepath.Join("components", "terraform", "vpc")},
+		{name: "eks", targetPath: filepath.Join("components", "terraform", "eks")},
+		{name: "rds", targetPath: filepath.Join("components", "terraform", "rds")},
+		{name: "s3", targetPath: filepath.Join("components", "terraform", "s3")},
+	}
+
+	model, err := newModelAtmosVendorInternal(packages, false, atmosConfig)
+	require.NoError(t, err)
+
+	_ = model.startInstalls()
+	_, _ = model.Update(installedPkgMsg{name: "vpc", index: 0})
+	assert.Equal(t, []int{1, 2}, model.inFlight)
+
+	// The packages in flight are reported as interrupted, not as vendored
+	_, _ = model.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	assert.True(t, model.done)
+	assert.Equal(t, []string{"eks", "rds"}, model.interruptedPkgs)
+	assert.Equal(t, 1, model.skippedPkg)
+	assert.Equal(t, "Vendored 1 components. Interrupted 2 components: eks, rds. Skipped 1 components.\n", model.summary())
+}
+
+func TestVendorTargetsOverlap(t *testing.T) {
+	assert.True(t, vendorTargetsOverlap("components/terraform/vpc", "components/terraform/vpc/"))
+	assert.True(t, vendorTargetsOverlap("components/terraform/vpc", "components/terraform/vpc/modules"))
+	assert.False(t, vendorTargetsOverlap("components/terraform/vpc", "components/terraform/vpc-flow-logs-bucket"))
+}
+
+func TestRetryVendorDownload(t *testing.T) {
+	baseDelay := vendorRetryBaseDelay
+	vendorRetryBaseDelay = 0
+	defer func() {
+		vendorRetryBaseDelay = baseDelay
+	}()
+
+	atmosConfig := schema.AtmosConfiguration{}
+	atmosConfig.Vendor.Retries = 2
+
+	attempts := 0
+	err := retryVendorDownload(atmosConfig, "github.com/org/repo.git", func(attempt int) error {
+		attempts++
+		if attempt < 2 {
+			return errors.New("transient error")
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+
+	attempts = 0
+	err = retryVendorDownload(atmosConfig, "github.com/org/repo.git", func(attempt int) error {
+		attempts++
+		return errors.New("permanent error")
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestFrozenVendorError(t *testing.T) {
+	packages := []pkgAtmosVendor{{name: "vpc"}, {name: "eks"}, {name: "rds"}}
+
+	model, err := newModelAtmosVendorInternal(packages, true, schema.AtmosConfiguration{})
+	require.NoError(t, err)
+
+	_, _ = model.Update(installedPkgMsg{name: "vpc", index: 0, err: errors.New("tree hash mismatch"), lockMismatch: true})
+	_, _ = model.Update(installedPkgMsg{name: "eks", index: 1, err: errors.New("connection refused")})
+	_, _ = model.Update(installedPkgMsg{name: "rds", index: 2})
+
+	// The lock mismatches are reported separately from the download failures
+	err = getFrozenVendorError(&model, "vendor.lock.yaml")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to vendor 1 components: the upstream sources do not match the vendor lock file 'vendor.lock.yaml': vpc")
+	assert.Contains(t, err.Error(), "failed to vendor 1 components: eks")
+	assert.NotContains(t, err.Error(), "lock file 'vendor.lock.yaml': vpc, eks")
+
+	model, err = newModelAtmosVendorInternal(packages[:1], true, schema.AtmosConfiguration{})
+	require.NoError(t, err)
+	_, _ = model.Update(installedPkgMsg{name: "vpc", index: 0, err: errors.New("connection refused")})
+
+	err = getFrozenVendorError(&model, "vendor.lock.yaml")
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "do not match the vendor lock file")
+}
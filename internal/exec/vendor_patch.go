@@ -0,0 +1,432 @@
+package exec
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	cp "github.com/otiai10/copy"
+
+	"github.com/cloudposse/atmos/pkg/schema"
+	u "github.com/cloudposse/atmos/pkg/utils"
+)
+
+var vendorPatchHunkHeaderRegex = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// vendorFilePatch describes the changes to one file in a unified diff
+type vendorFilePatch struct {
+	oldPath string
+	newPath string
+	hunks   []vendorPatchHunk
+}
+
+// vendorPatchHunk describes one hunk of a unified diff.
+// The lines include the ' ', '-' or '+' prefix and the line ending
+type vendorPatchHunk struct {
+	oldStart int
+	oldLines int
+	newStart int
+	newLines int
+	lines    []string
+}
+
+// vendorPatchChange describes a file changed by a patch
+type vendorPatchChange struct {
+	path    string
+	content []byte
+	deleted bool
+}
+
+// resolveVendorPatchPaths returns the paths of the patch files. Relative paths are relative to the base path
+func resolveVendorPatchPaths(basePath string, patches []string) []string {
+	var paths []string
+	for _, patch := range patches {
+		if filepath.IsAbs(patch) {
+			paths = append(paths, patch)
+		} else {
+			paths = append(paths, filepath.Join(basePath, patch))
+		}
+	}
+	return paths
+}
+
+// getVendorPatchRoot returns the folder the paths in the patches are relative to.
+// If the target is a file, the patches are relative to the folder of the file
+func getVendorPatchRoot(targetPath string) string {
+	if info, err := os.Stat(targetPath); err == nil && !info.IsDir() {
+		return filepath.Dir(targetPath)
+	}
+	return targetPath
+}
+
+// copyWithVendorPatches copies the files into a staging folder using the copy function, applies the patches to the staged files,
+// and copies the patched files to the target. The target is not changed if a patch does not apply.
+// It returns the folder the patches are relative to, and the paths (relative to this folder) of the files changed by the patches
+func copyWithVendorPatches(
+	atmosConfig schema.AtmosConfiguration,
+	targetPath string,
+	patchFiles []string,
+	copyFn func(stagingTarget string) error,
+) (string, []string, error) {
+	stagingDir, err := os.MkdirTemp("", "atmos-vendor-patch")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer removeTempDir(atmosConfig, stagingDir)
+
+	stagingTarget := filepath.Join(stagingDir, filepath.Base(targetPath))
+	if err := copyFn(stagingTarget); err != nil {
+		return "", nil, fmt.Errorf("failed to copy package: %w", err)
+	}
+
+	stagingRoot := getVendorPatchRoot(stagingTarget)
+	changed, err := applyVendorPatches(stagingRoot, patchFiles)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to apply patches: %w", err)
+	}
+
+	copyOptions := cp.Options{
+		PreserveTimes: false,
+		PreserveOwner: false,
+		OnSymlink:     func(src string) cp.SymlinkAction { return cp.Deep },
+	}
+	if err := cp.Copy(stagingTarget, targetPath, copyOptions); err != nil {
+		return "", nil, fmt.Errorf("failed to copy package: %w", err)
+	}
+
+	// Remove the files deleted by the patches from the target
+	root := getVendorPatchRoot(targetPath)
+	for _, f := range changed {
+		if u.FileExists(filepath.Join(stagingRoot, filepath.FromSlash(f))) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(root, filepath.FromSlash(f))); err != nil && !os.IsNotExist(err) {
+			return "", nil, err
+		}
+	}
+
+	return root, changed, nil
+}
+
+// applyVendorPatches applies the unified diff patch files in order to the files in the root folder.
+// It returns the paths (relative to the root folder) of the changed files
+func applyVendorPatches(root string, patchFiles []string) ([]string, error) {
+	var changed []string
+
+	for _, patchFile := range patchFiles {
+		files, err := applyVendorPatch(root, patchFile)
+		if err != nil {
+			return changed, err
+		}
+
+		for _, f := range files {
+			if !u.SliceContainsString(changed, f) {
+				changed = append(changed, f)
+			}
+		}
+	}
+
+	return changed, nil
+}
+
+// applyVendorPatch applies the unified diff patch file to the files in the root folder.
+// The files are only written if all the hunks of the patch apply
+func applyVendorPatch(root string, patchFile string) ([]string, error) {
+	content, err := os.ReadFile(patchFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the patch file '%s': %w", patchFile, err)
+	}
+
+	filePatches, err := parseVendorPatch(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("invalid patch file '%s': %w", patchFile, err)
+	}
+
+	var changes []vendorPatchChange
+
+	for _, fp := range filePatches {
+		change, err := applyVendorFilePatch(root, fp)
+		if err != nil {
+			return nil, fmt.Errorf("the patch '%s' does not apply: %w", patchFile, err)
+		}
+		changes = append(changes, change)
+	}
+
+	var changed []string
+
+	for _, change := range changes {
+		file := filepath.Join(root, filepath.FromSlash(change.path))
+
+		if change.deleted {
+			if err := os.Remove(file); err != nil {
+				return changed, err
+			}
+		} else {
+			if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+				return changed, err
+			}
+
+			mode := os.FileMode(0o644)
+			if info, err := os.Stat(file); err == nil {
+				mode = info.Mode()
+			}
+
+			if err := os.WriteFile(file, change.content, mode); err != nil {
+				return changed, err
+			}
+		}
+
+		changed = append(changed, change.path)
+	}
+
+	return changed, nil
+}
+
+// applyVendorFilePatch applies the hunks to the file and returns the new content of the file.
+// If a hunk is not found at the expected line, the nearest matching position is used (as `patch` does)
+func applyVendorFilePatch(root string, fp vendorFilePatch) (vendorPatchChange, error) {
+	path := fp.newPath
+	if path == "" {
+		path = fp.oldPath
+	}
+
+	file := filepath.Join(root, filepath.FromSlash(path))
+	if rel, err := filepath.Rel(root, file); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return vendorPatchChange{}, fmt.Errorf("the file '%s' is outside of the target folder", path)
+	}
+
+	var lines []string
+	var existing []byte
+
+	if fp.oldPath != "" {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return vendorPatchChange{}, fmt.Errorf("the file '%s' does not exist", path)
+		}
+		lines = strings.SplitAfter(string(content), "\n")
+		if lines[len(lines)-1] == "" {
+			lines = lines[:len(lines)-1]
+		}
+	} else if u.FileExists(file) {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return vendorPatchChange{}, err
+		}
+		existing = content
+	}
+
+	offset := 0
+
+	for i, hunk := range fp.hunks {
+		var oldLines, newLines []string
+		for _, line := range hunk.lines {
+			switch line[0] {
+			case ' ':
+				oldLines = append(oldLines, line[1:])
+				newLines = append(newLines, line[1:])
+			case '-':
+				oldLines = append(oldLines, line[1:])
+			case '+':
+				newLines = append(newLines, line[1:])
+			}
+		}
+
+		start := hunk.oldStart - 1
+		if hunk.oldLines == 0 {
+			// A hunk that only adds lines is inserted after the `oldStart` line
+			start = hunk.oldStart
+		}
+
+		pos := findVendorPatchHunk(lines, oldLines, start+offset)
+		if pos < 0 {
+			return vendorPatchChange{}, fmt.Errorf("hunk #%d (@@ -%d,%d +%d,%d @@) does not match the file '%s'",
+				i+1, hunk.oldStart, hunk.oldLines, hunk.newStart, hunk.newLines, path)
+		}
+
+		updated := make([]string, 0, len(lines)-len(oldLines)+len(newLines))
+		updated = append(updated, lines[:pos]...)
+		updated = append(updated, newLines...)
+		updated = append(updated, lines[pos+len(oldLines):]...)
+		lines = updated
+
+		offset += len(newLines) - len(oldLines)
+	}
+
+	if fp.newPath == "" {
+		if len(lines) > 0 {
+			return vendorPatchChange{}, fmt.Errorf("the file '%s' is not empty after removing the lines in the patch", path)
+		}
+		return vendorPatchChange{path: path, deleted: true}, nil
+	}
+
+	content := []byte(strings.Join(lines, ""))
+
+	// A file created by the patch can only exist if the patch was already applied
+	if existing != nil && !bytes.Equal(existing, content) {
+		return vendorPatchChange{}, fmt.Errorf("the file '%s' already exists", path)
+	}
+
+	return vendorPatchChange{path: path, content: content}, nil
+}
+
+// findVendorPatchHunk finds the position of the lines in the file, starting from the expected position and searching in both directions.
+// It returns -1 if the lines are not found
+func findVendorPatchHunk(lines []string, hunkLines []string, expected int) int {
+	matches := func(pos int) bool {
+		if pos < 0 || pos+len(hunkLines) > len(lines) {
+			return false
+		}
+		for i, line := range hunkLines {
+			if lines[pos+i] != line {
+				return false
+			}
+		}
+		return true
+	}
+
+	for delta := 0; delta <= len(lines); delta++ {
+		if matches(expected - delta) {
+			return expected - delta
+		}
+		if matches(expected + delta) {
+			return expected + delta
+		}
+	}
+
+	return -1
+}
+
+// parseVendorPatch parses a unified diff (as produced by `diff -u` or `git diff`) into the changes for each file
+func parseVendorPatch(content string) ([]vendorFilePatch, error) {
+	lines := strings.SplitAfter(content, "\n")
+
+	var patches []vendorFilePatch
+	var current *vendorFilePatch
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+
+		switch {
+		case strings.HasPrefix(line, "--- ") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ "):
+			oldPath := parseVendorPatchPath(line[4:])
+			newPath := parseVendorPatchPath(lines[i+1][4:])
+
+			// Strip the `a/` and `b/` prefixes added by `git diff`
+			if (oldPath == "" || strings.HasPrefix(oldPath, "a/")) && (newPath == "" || strings.HasPrefix(newPath, "b/")) {
+				oldPath = strings.TrimPrefix(oldPath, "a/")
+				newPath = strings.TrimPrefix(newPath, "b/")
+			}
+
+			patches = append(patches, vendorFilePatch{oldPath: oldPath, newPath: newPath})
+			current = &patches[len(patches)-1]
+			i++
+
+		case strings.HasPrefix(line, "@@ "):
+			if current == nil {
+				return nil, fmt.Errorf("line %d: hunk without a file header", i+1)
+			}
+
+			hunk, next, err := parseVendorPatchHunk(lines, i)
+			if err != nil {
+				return nil, err
+			}
+			current.hunks = append(current.hunks, hunk)
+			i = next - 1
+		}
+	}
+
+	if len(patches) == 0 {
+		return nil, fmt.Errorf("no file changes found")
+	}
+
+	return patches, nil
+}
+
+// parseVendorPatchHunk parses the hunk starting at the header line and returns the hunk and the index of the line after the hunk
+func parseVendorPatchHunk(lines []string, index int) (vendorPatchHunk, int, error) {
+	m := vendorPatchHunkHeaderRegex.FindStringSubmatch(lines[index])
+	if m == nil {
+		return vendorPatchHunk{}, 0, fmt.Errorf("line %d: invalid hunk header '%s'", index+1, strings.TrimSpace(lines[index]))
+	}
+
+	atoi := func(s string) int {
+		if s == "" {
+			return 1
+		}
+		v, _ := strconv.Atoi(s)
+		return v
+	}
+
+	hunk := vendorPatchHunk{
+		oldStart: atoi(m[1]),
+		oldLines: atoi(m[2]),
+		newStart: atoi(m[3]),
+		newLines: atoi(m[4]),
+	}
+
+	oldCount, newCount := 0, 0
+	i := index + 1
+
+	for ; i < len(lines) && (oldCount < hunk.oldLines || newCount < hunk.newLines); i++ {
+		line := lines[i]
+		if line == "" {
+			break
+		}
+
+		// An empty line in the hunk is an unchanged empty line with the trailing space removed by an editor
+		if line == "\n" || line == "\r\n" {
+			line = " " + line
+		}
+
+		switch line[0] {
+		case ' ':
+			oldCount++
+			newCount++
+		case '-':
+			oldCount++
+		case '+':
+			newCount++
+		case '\\':
+			// `\ No newline at end of file` applies to the previous line
+			if len(hunk.lines) > 0 {
+				last := len(hunk.lines) - 1
+				hunk.lines[last] = strings.TrimSuffix(strings.TrimSuffix(hunk.lines[last], "\n"), "\r")
+			}
+			continue
+		default:
+			return vendorPatchHunk{}, 0, fmt.Errorf("line %d: unexpected line in hunk", i+1)
+		}
+
+		hunk.lines = append(hunk.lines, line)
+	}
+
+	for ; i < len(lines) && strings.HasPrefix(lines[i], "\\"); i++ {
+		if len(hunk.lines) > 0 {
+			last := len(hunk.lines) - 1
+			hunk.lines[last] = strings.TrimSuffix(strings.TrimSuffix(hunk.lines[last], "\n"), "\r")
+		}
+	}
+
+	if oldCount != hunk.oldLines || newCount != hunk.newLines {
+		return vendorPatchHunk{}, 0, fmt.Errorf("line %d: the hunk is truncated", index+1)
+	}
+
+	return hunk, i, nil
+}
+
+// parseVendorPatchPath returns the file path from the `---` or `+++` header line, or an empty string for `/dev/null`
+func parseVendorPatchPath(header string) string {
+	header = strings.TrimRight(header, "\r\n")
+	if i := strings.IndexByte(header, '\t'); i >= 0 {
+		header = header[:i]
+	}
+	header = strings.TrimSpace(header)
+	if header == "/dev/null" {
+		return ""
+	}
+	return header
+}
@@ -0,0 +1,82 @@
+package exec
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyVendorPatches(t *testing.T) {
+	root := t.TempDir()
+
+	main := "variable \"name\" {}\n\nresource \"null_resource\" \"this\" {\n  count = 1\n}\n\noutput \"id\" {\n  value = null_resource.this[0].id\n}\n"
+	err := os.WriteFile(filepath.Join(root, "main.tf"), []byte(main), 0o644)
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(root, "obsolete.tf"), []byte("locals {}\n"), 0o644)
+	require.NoError(t, err)
+
+	// A git-style patch with a hunk at the wrong line number, a new file, and a deleted file
+	patch := `diff --git a/main.tf b/main.tf
+--- a/main.tf
++++ b/main.tf
+@@ -5,3 +5,3 @@ resource "null_resource" "this" {
+ resource "null_resource" "this" {
+-  count = 1
++  count = 2
+ }
+diff --git a/versions.tf b/versions.tf
+new file mode 100644
+--- /dev/null
++++ b/versions.tf
+@@ -0,0 +1,3 @@
++terraform {
++  required_version = ">= 1.0.0"
++}
+\ No newline at end of file
+diff --git a/obsolete.tf b/obsolete.tf
+deleted file mode 100644
+--- a/obsolete.tf
++++ /dev/null
+@@ -1 +0,0 @@
+-locals {}
+`
+	patchFile := filepath.Join(t.TempDir(), "fix.patch")
+	err = os.WriteFile(patchFile, []byte(patch), 0o644)
+	require.NoError(t, err)
+
+	changed, err := applyVendorPatches(root, []string{patchFile})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"main.tf", "versions.tf", "obsolete.tf"}, changed)
+
+	content, err := os.ReadFile(filepath.Join(root, "main.tf"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "count = 2\n")
+	assert.Contains(t, string(content), "output \"id\"")
+
+	content, err = os.ReadFile(filepath.Join(root, "versions.tf"))
+	require.NoError(t, err)
+	assert.Equal(t, "terraform {\n  required_version = \">= 1.0.0\"\n}", string(content))
+
+	assert.NoFileExists(t, filepath.Join(root, "obsolete.tf"))
+
+	// The patch no longer applies to the patched file
+	_, err = applyVendorPatches(root, []string{patchFile})
+	assert.ErrorContains(t, err, "hunk #1")
+}
+
+func TestParseVendorPatchErrors(t *testing.T) {
+	_, err := parseVendorPatch("not a patch\n")
+	assert.Error(t, err)
+
+	_, err = parseVendorPatch("--- a/main.tf\n+++ b/main.tf\n@@ -1,2 +1,2 @@\n-a\n")
+	assert.ErrorContains(t, err, "truncated")
+
+	root := t.TempDir()
+	patches, err := parseVendorPatch("--- a/../outside.tf\n+++ b/../outside.tf\n@@ -0,0 +1 @@\n+a\n")
+	require.NoError(t, err)
+	_, err = applyVendorFilePatch(root, patches[0])
+	assert.ErrorContains(t, err, "outside of the target folder")
+}
@@ -0,0 +1,287 @@
+package exec
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/spf13/cobra"
+
+	cfg "github.com/cloudposse/atmos/pkg/config"
+	"github.com/cloudposse/atmos/pkg/schema"
+	u "github.com/cloudposse/atmos/pkg/utils"
+)
+
+// The annotations added to the OCI images published by `atmos vendor push`
+// https://github.com/opencontainers/image-spec/blob/main/annotations.md
+const (
+	ociAnnotationTitle         = "org.opencontainers.image.title"
+	ociAnnotationVersion       = "org.opencontainers.image.version"
+	ociAnnotationRevision      = "org.opencontainers.image.revision"
+	ociAnnotationComponent     = "com.cloudposse.atmos.component"
+	ociAnnotationComponentType = "com.cloudposse.atmos.component.type"
+)
+
+// ExecuteVendorPushCommand executes `atmos vendor push` commands
+func ExecuteVendorPushCommand(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return errors.New("invalid arguments. The command requires one argument `component`")
+	}
+
+	component := args[0]
+
+	atmosConfig, err := cfg.InitCliConfig(schema.ConfigAndStacksInfo{}, false)
+	if err != nil {
+		return fmt.Errorf("failed to initialize CLI config: %w", err)
+	}
+
+	flags := cmd.Flags()
+
+	ref, err := flags.GetString("ref")
+	if err != nil {
+		return err
+	}
+
+	if ref == "" {
+		return fmt.Errorf("the '--ref' flag needs to be specified.\n" +
+			"Example: atmos vendor push <component> --ref <registry>/<repository>:<tag>")
+	}
+
+	componentType, err := flags.GetString("type")
+	if err != nil {
+		return err
+	}
+
+	if componentType == "" {
+		componentType = "terraform"
+	}
+
+	version, err := flags.GetString("version")
+	if err != nil {
+		return err
+	}
+
+	var componentBasePath string
+	if componentType == "terraform" {
+		componentBasePath = atmosConfig.Components.Terraform.BasePath
+	} else if componentType == "helmfile" {
+		componentBasePath = atmosConfig.Components.Helmfile.BasePath
+	} else {
+		return fmt.Errorf("type '%s' is not supported. Valid types are 'terraform' and 'helmfile'", componentType)
+	}
+
+	componentPath := filepath.Join(atmosConfig.BasePath, componentBasePath, component)
+
+	// If the component was vendored, use the 'included_paths' and 'excluded_paths' from its 'component.yaml'
+	var vendorComponentSpec schema.VendorComponentSpec
+	if _, err = findComponentConfigFile(componentPath, strings.TrimSuffix(cfg.ComponentVendorConfigFileName, ".yaml")); err == nil {
+		componentConfig, _, err := ReadAndProcessComponentVendorConfigFile(atmosConfig, component, componentType)
+		if err != nil {
+			return err
+		}
+		vendorComponentSpec = componentConfig.Spec
+	}
+
+	digest, err := ExecuteVendorPushInternal(atmosConfig, component, componentType, componentPath, vendorComponentSpec, ref, version)
+	if err != nil {
+		return err
+	}
+
+	u.PrintMessage(digest)
+	return nil
+}
+
+// ExecuteVendorPushInternal packages the component folder into an OCI image and pushes it to the registry.
+// It returns the digest of the pushed image
+func ExecuteVendorPushInternal(
+	atmosConfig schema.AtmosConfiguration,
+	component string,
+	componentType string,
+	componentPath string,
+	vendorComponentSpec schema.VendorComponentSpec,
+	ref string,
+	version string,
+) (string, error) {
+	dirExists, err := u.IsDirectory(componentPath)
+	if err != nil || !dirExists {
+		return "", fmt.Errorf("folder '%s' does not exist", componentPath)
+	}
+
+	imageRef, err := name.ParseReference(ref)
+	if err != nil {
+		return "", fmt.Errorf("cannot parse reference of the image '%s'. Error: %v", ref, err)
+	}
+
+	// Default to the tag of the reference for the version
+	if tag, ok := imageRef.(name.Tag); ok && version == "" && tag.TagStr() != "latest" {
+		version = tag.TagStr()
+	}
+
+	annotations := map[string]string{
+		ociAnnotationTitle:         component,
+		ociAnnotationComponent:     component,
+		ociAnnotationComponentType: componentType,
+	}
+	if version != "" {
+		annotations[ociAnnotationVersion] = version
+	}
+	if revision := getVendorPushRevision(componentPath); revision != "" {
+		annotations[ociAnnotationRevision] = revision
+	}
+
+	image, files, err := buildVendorComponentImage(componentPath, vendorComponentSpec, annotations)
+	if err != nil {
+		return "", err
+	}
+
+	if files == 0 {
+		return "", fmt.Errorf("no files to push in the folder '%s'. Check the 'included_paths' and 'excluded_paths'", componentPath)
+	}
+
+	u.LogInfo(fmt.Sprintf("Pushing %d files from '%s' to '%s'", files, componentPath, imageRef.Name()))
+
+	if err = remote.Write(imageRef, image, remote.WithAuthFromKeychain(authn.DefaultKeychain)); err != nil {
+		return "", fmt.Errorf("cannot push image '%s'. Error: %v", imageRef.Name(), err)
+	}
+
+	digest, err := image.Digest()
+	if err != nil {
+		return "", err
+	}
+
+	u.LogInfo(fmt.Sprintf("Pushed component '%s' to '%s@%s'", component, imageRef.Context().Name(), digest.String()))
+
+	return digest.String(), nil
+}
+
+// buildVendorComponentImage creates an OCI image with a layer containing the files of the component folder.
+// The files are filtered with the same 'included_paths' and 'excluded_paths' rules as `atmos vendor pull`,
+// and the files generated by Atmos and Terraform are never included.
+// It returns the image and the number of files in the layer
+func buildVendorComponentImage(
+	componentPath string,
+	vendorComponentSpec schema.VendorComponentSpec,
+	annotations map[string]string,
+) (v1.Image, int, error) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	skip := generateComponentSkipFunction(componentPath, vendorComponentSpec)
+	files := 0
+
+	err := filepath.WalkDir(componentPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if path == componentPath {
+			return nil
+		}
+
+		rel, err := filepath.Rel(componentPath, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		skipped, err := skip(info, path, "")
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if skipped || isVendorDiffIgnored(rel+"/") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if skipped || isVendorDiffIgnored(rel) || !info.Mode().IsRegular() {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		// Zero timestamps and owners make the image digest depend only on the content of the files
+		header := &tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     rel,
+			Mode:     int64(info.Mode().Perm()),
+			Size:     int64(len(content)),
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := tw.Write(content); err != nil {
+			return err
+		}
+
+		files++
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err = tw.Close(); err != nil {
+		return nil, 0, err
+	}
+
+	layer, err := tarball.LayerFromOpener(func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(buf.Bytes())), nil
+	}, tarball.WithMediaType(types.OCILayer))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	image, err := mutate.AppendLayers(empty.Image, layer)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	image = mutate.MediaType(image, types.OCIManifestSchema1)
+	image = mutate.ConfigMediaType(image, types.OCIConfigJSON)
+
+	return mutate.Annotations(image, annotations).(v1.Image), files, nil
+}
+
+// getVendorPushRevision returns the commit SHA of the Git repository containing the component folder,
+// or an empty string if the folder is not in a Git repository
+func getVendorPushRevision(componentPath string) string {
+	repo, err := git.PlainOpenWithOptions(componentPath, &git.PlainOpenOptions{
+		DetectDotGit:          true,
+		EnableDotGitCommonDir: false,
+	})
+	if err != nil {
+		return ""
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return ""
+	}
+
+	return head.Hash().String()
+}
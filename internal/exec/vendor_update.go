@@ -0,0 +1,440 @@
+package exec
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	cfg "github.com/cloudposse/atmos/pkg/config"
+	"github.com/cloudposse/atmos/pkg/schema"
+	u "github.com/cloudposse/atmos/pkg/utils"
+)
+
+// vendorUpdate describes a newer upstream version found for a vendor source
+type vendorUpdate struct {
+	name           string
+	file           string
+	currentVersion string
+	latestVersion  string
+	constraints    string
+}
+
+// ExecuteVendorUpdateCommand executes `atmos vendor update` commands
+func ExecuteVendorUpdateCommand(cmd *cobra.Command, args []string) error {
+	info, err := ProcessCommandLineArgs("terraform", cmd, args, nil)
+	if err != nil {
+		return err
+	}
+
+	atmosConfig, err := cfg.InitCliConfig(info, false)
+	if err != nil {
+		return fmt.Errorf("failed to initialize CLI config: %w", err)
+	}
+
+	flags := cmd.Flags()
+
+	dryRun, err := flags.GetBool("dry-run")
+	if err != nil {
+		return err
+	}
+
+	component, err := flags.GetString("component")
+	if err != nil {
+		return err
+	}
+
+	tagsCsv, err := flags.GetString("tags")
+	if err != nil {
+		return err
+	}
+
+	var tags []string
+	if tagsCsv != "" {
+		tags = strings.Split(tagsCsv, ",")
+	}
+
+	if component != "" && len(tags) > 0 {
+		return fmt.Errorf("either '--component' or '--tags' flag can be provided, but not both")
+	}
+
+	format, err := flags.GetString("format")
+	if err != nil {
+		return err
+	}
+
+	if format != "" && format != "table" && format != "markdown" {
+		return fmt.Errorf("invalid '--format' flag '%s'. Valid values are 'table' (default) and 'markdown'", format)
+	}
+
+	vendorConfig, vendorConfigExists, foundVendorConfigFile, err := ReadAndProcessVendorConfigFile(atmosConfig, cfg.AtmosVendorConfigFileName, true)
+	if err != nil {
+		return err
+	}
+	if !vendorConfigExists {
+		return fmt.Errorf("the vendor config file '%s' does not exist", cfg.AtmosVendorConfigFileName)
+	}
+
+	updates, err := ExecuteVendorUpdateInternal(atmosConfig, foundVendorConfigFile, vendorConfig.Spec, component, tags, dryRun)
+	if err != nil {
+		return err
+	}
+
+	if format == "markdown" {
+		u.PrintMessage(formatVendorUpdatesMarkdown(updates, dryRun))
+	} else {
+		u.PrintMessage(formatVendorUpdatesTable(updates, dryRun))
+	}
+
+	return nil
+}
+
+// ExecuteVendorUpdateInternal finds the newest upstream versions of the vendor sources that satisfy the `constraints`,
+// and updates the `version` fields in the vendor config files (unless `dryRun` is set).
+// It returns the found updates
+func ExecuteVendorUpdateInternal(
+	atmosConfig schema.AtmosConfiguration,
+	vendorConfigFileName string,
+	atmosVendorSpec schema.AtmosVendorSpec,
+	component string,
+	tags []string,
+	dryRun bool,
+) ([]vendorUpdate, error) {
+	sources, _, err := processVendorImports(
+		atmosConfig,
+		vendorConfigFileName,
+		atmosVendorSpec.Imports,
+		atmosVendorSpec.Sources,
+		[]string{vendorConfigFileName},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var updates []vendorUpdate
+
+	for indexSource, s := range sources {
+		if shouldSkipSource(&s, component, tags) {
+			continue
+		}
+
+		if err := validateSourceFields(&s, vendorConfigFileName); err != nil {
+			return nil, err
+		}
+
+		name := s.Component
+		if name == "" {
+			name = s.Source
+		}
+
+		// Only the sources that use the version in the 'source' template can be updated
+		if s.Version == "" || !strings.Contains(s.Source, ".Version") {
+			u.LogDebug(fmt.Sprintf("Skipping '%s' since it does not use 'version' in the 'source' template", name))
+			continue
+		}
+
+		tmplData := struct {
+			Component string
+			Version   string
+		}{s.Component, s.Version}
+
+		uri, err := ProcessTmpl(fmt.Sprintf("source-%d", indexSource), s.Source, tmplData, false)
+		if err != nil {
+			return nil, err
+		}
+
+		versions, err := listVendorSourceVersions(uri)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the versions of '%s': %w", name, err)
+		}
+
+		latest, err := selectVendorUpdateVersion(s.Version, s.Constraints, versions)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version or constraints for '%s': %w", name, err)
+		}
+
+		if latest == "" {
+			u.LogDebug(fmt.Sprintf("'%s' is up to date (%s)", name, s.Version))
+			continue
+		}
+
+		file, err := findVendorSourceFile(atmosConfig, s.File)
+		if err != nil {
+			return nil, err
+		}
+
+		if !dryRun {
+			if err := updateVendorConfigFileVersion(file, s, latest); err != nil {
+				return nil, err
+			}
+		}
+
+		updates = append(updates, vendorUpdate{
+			name:           name,
+			file:           file,
+			currentVersion: s.Version,
+			latestVersion:  latest,
+			constraints:    s.Constraints,
+		})
+	}
+
+	return updates, nil
+}
+
+// listVendorSourceVersions returns the tags of the Git repository or the OCI repository of the source.
+// Other sources do not support listing versions
+func listVendorSourceVersions(uri string) ([]string, error) {
+	if strings.HasPrefix(uri, "oci://") {
+		return listOciImageTags(strings.TrimPrefix(uri, "oci://"))
+	}
+
+	repoURL, _, isGit, err := parseGitSource(uri)
+	if err != nil {
+		return nil, err
+	}
+	if !isGit {
+		return nil, fmt.Errorf("listing versions is only supported for Git and OCI sources")
+	}
+
+	refs, err := gitLsRemote("--tags", "--refs", repoURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []string
+	for _, r := range refs {
+		tags = append(tags, strings.TrimPrefix(r[1], "refs/tags/"))
+	}
+
+	return tags, nil
+}
+
+// selectVendorUpdateVersion returns the newest version that satisfies the constraints and is newer than the current version.
+// It returns an empty string if there is no newer version.
+// If the constraints are not specified, the newest stable version is selected.
+// The version is returned with the same `v` prefix style as the current version, since the source templates
+// (e.g. `ref=v{{.Version}}`) depend on it
+func selectVendorUpdateVersion(current string, constraints string, versions []string) (string, error) {
+	currentVersion, err := semver.NewVersion(current)
+	if err != nil {
+		return "", err
+	}
+
+	var constraint *semver.Constraints
+	if constraints != "" {
+		constraint, err = semver.NewConstraint(constraints)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	latest := ""
+	latestVersion := currentVersion
+
+	for _, v := range versions {
+		version, err := semver.NewVersion(v)
+		if err != nil {
+			// Skip the tags that are not semantic versions
+			continue
+		}
+		if constraint != nil && !constraint.Check(version) {
+			continue
+		}
+		if constraint == nil && version.Prerelease() != "" {
+			continue
+		}
+		if version.GreaterThan(latestVersion) {
+			latest = v
+			latestVersion = version
+		}
+	}
+
+	if latest == "" {
+		return "", nil
+	}
+
+	latest = strings.TrimPrefix(latest, "v")
+	if strings.HasPrefix(current, "v") {
+		latest = "v" + latest
+	}
+
+	return latest, nil
+}
+
+// findVendorSourceFile finds the vendor config file where the source is defined
+func findVendorSourceFile(atmosConfig schema.AtmosConfiguration, file string) (string, error) {
+	if u.FileExists(file) {
+		return file, nil
+	}
+	if found, ok := u.SearchConfigFile(file); ok {
+		return found, nil
+	}
+	if found, ok := u.SearchConfigFile(filepath.Join(atmosConfig.BasePath, file)); ok {
+		return found, nil
+	}
+	return "", fmt.Errorf("the vendor config file '%s' does not exist", file)
+}
+
+// updateVendorConfigFileVersion replaces the value of the `version` field of the source in the vendor config file.
+// The file is edited in place, so the comments and the formatting are preserved
+func updateVendorConfigFileVersion(file string, source schema.AtmosVendorSource, version string) error {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(content, &root); err != nil {
+		return fmt.Errorf("invalid vendor config file '%s': %w", file, err)
+	}
+
+	versionNode := findVendorSourceVersionNode(&root, source)
+	if versionNode == nil {
+		return fmt.Errorf("the 'version' of the source '%s' is not found in the vendor config file '%s'", source.Source, file)
+	}
+
+	lines := strings.Split(string(content), "\n")
+	lineIndex := versionNode.Line - 1
+	columnIndex := versionNode.Column - 1
+
+	if lineIndex < 0 || lineIndex >= len(lines) || columnIndex < 0 || columnIndex > len(lines[lineIndex]) {
+		return fmt.Errorf("failed to update the 'version' of the source '%s' in the vendor config file '%s'", source.Source, file)
+	}
+
+	line := lines[lineIndex]
+
+	var token, replacement string
+	switch versionNode.Style {
+	case yaml.DoubleQuotedStyle:
+		token = `"` + versionNode.Value + `"`
+		replacement = `"` + version + `"`
+	case yaml.SingleQuotedStyle:
+		token = `'` + versionNode.Value + `'`
+		replacement = `'` + version + `'`
+	default:
+		token = versionNode.Value
+		replacement = version
+	}
+
+	if !strings.HasPrefix(line[columnIndex:], token) {
+		return fmt.Errorf("failed to update the 'version' of the source '%s' in the vendor config file '%s'", source.Source, file)
+	}
+
+	lines[lineIndex] = line[:columnIndex] + replacement + line[columnIndex+len(token):]
+
+	fileInfo, err := os.Stat(file)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, []byte(strings.Join(lines, "\n")), fileInfo.Mode())
+}
+
+// findVendorSourceVersionNode finds the YAML node of the `version` field of the source in `spec.sources`.
+// The source is matched by the `component` name, or by the `source` if the component name is not specified
+func findVendorSourceVersionNode(root *yaml.Node, source schema.AtmosVendorSource) *yaml.Node {
+	if root.Kind != yaml.DocumentNode || len(root.Content) == 0 {
+		return nil
+	}
+
+	spec := getYamlMappingValue(root.Content[0], "spec")
+	if spec == nil {
+		return nil
+	}
+
+	sources := getYamlMappingValue(spec, "sources")
+	if sources == nil || sources.Kind != yaml.SequenceNode {
+		return nil
+	}
+
+	for _, s := range sources.Content {
+		if source.Component != "" {
+			if c := getYamlMappingValue(s, "component"); c == nil || c.Value != source.Component {
+				continue
+			}
+		} else {
+			if src := getYamlMappingValue(s, "source"); src == nil || src.Value != source.Source {
+				continue
+			}
+		}
+		return getYamlMappingValue(s, "version")
+	}
+
+	return nil
+}
+
+// getYamlMappingValue returns the value node of the key in the YAML mapping node
+func getYamlMappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// formatVendorUpdatesTable formats the updates as a plain text table
+func formatVendorUpdatesTable(updates []vendorUpdate, dryRun bool) string {
+	if len(updates) == 0 {
+		return "All vendored components are up to date."
+	}
+
+	nameWidth := len("COMPONENT")
+	currentWidth := len("CURRENT")
+	for _, update := range updates {
+		nameWidth = max(nameWidth, len(update.name))
+		currentWidth = max(currentWidth, len(update.currentVersion))
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%-*s  %-*s  %s\n", nameWidth, "COMPONENT", currentWidth, "CURRENT", "LATEST"))
+	for _, update := range updates {
+		sb.WriteString(fmt.Sprintf("%-*s  %-*s  %s\n", nameWidth, update.name, currentWidth, update.currentVersion, update.latestVersion))
+	}
+
+	if dryRun {
+		sb.WriteString("\nDry run completed. No vendor config files updated.")
+	} else {
+		sb.WriteString(fmt.Sprintf("\nUpdated %d components.", len(updates)))
+	}
+
+	return sb.String()
+}
+
+// formatVendorUpdatesMarkdown formats the updates as a Markdown document suitable for a pull request body
+func formatVendorUpdatesMarkdown(updates []vendorUpdate, dryRun bool) string {
+	if len(updates) == 0 {
+		return "All vendored components are up to date."
+	}
+
+	var sb strings.Builder
+	if dryRun {
+		sb.WriteString("## Available vendored component updates\n\n")
+	} else {
+		sb.WriteString("## Vendored component updates\n\n")
+	}
+	sb.WriteString("| Component | Current | Latest | Constraints | File |\n")
+	sb.WriteString("|:----------|:--------|:-------|:------------|:-----|\n")
+	for _, update := range updates {
+		constraints := update.constraints
+		if constraints == "" {
+			constraints = "-"
+		}
+		sb.WriteString(fmt.Sprintf("| `%s` | `%s` | `%s` | `%s` | `%s` |\n",
+			update.name,
+			update.currentVersion,
+			update.latestVersion,
+			constraints,
+			filepath.ToSlash(update.file),
+		))
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,97 @@
+package exec
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudposse/atmos/pkg/schema"
+)
+
+func TestSelectVendorUpdateVersion(t *testing.T) {
+	tags := []string{"1.0.0", "1.1.0", "v1.2.0", "1.3.0-rc1", "2.0.0", "latest"}
+
+	tests := []struct {
+		name        string
+		current     string
+		constraints string
+		expected    string
+	}{
+		{name: "newest stable version", current: "1.0.0", constraints: "", expected: "2.0.0"},
+		{name: "newest version within constraints", current: "1.0.0", constraints: "~1", expected: "1.2.0"},
+		{name: "same prefix style as current version", current: "v1.0.0", constraints: "~1", expected: "v1.2.0"},
+		{name: "prefix added to tag without prefix", current: "v1.0.0", constraints: "", expected: "v2.0.0"},
+		{name: "prerelease allowed by constraints", current: "1.0.0", constraints: "~1.3.0-0", expected: "1.3.0-rc1"},
+		{name: "up to date", current: "2.0.0", constraints: "", expected: ""},
+		{name: "no version satisfies constraints", current: "1.0.0", constraints: "~3", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			latest, err := selectVendorUpdateVersion(tt.current, tt.constraints, tags)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, latest)
+		})
+	}
+
+	_, err := selectVendorUpdateVersion("1.0.0", "not a constraint", tags)
+	assert.Error(t, err)
+}
+
+func TestUpdateVendorConfigFileVersion(t *testing.T) {
+	content := `apiVersion: atmos/v1
+kind: AtmosVendorConfig
+spec:
+  sources:
+    # VPC component
+    - component: "vpc"
+      source: "github.com/cloudposse/terraform-aws-components.git//modules/vpc?ref={{.Version}}"
+      version: "1.0.0" # pinned
+      constraints: "~1"
+      targets:
+        - "components/terraform/vpc"
+    - component: "vpc-flow-logs-bucket"
+      source: "github.com/cloudposse/terraform-aws-components.git//modules/vpc-flow-logs-bucket?ref={{.Version}}"
+      version: 1.0.0
+      targets:
+        - "components/terraform/vpc-flow-logs-bucket"
+`
+
+	file := filepath.Join(t.TempDir(), "vendor.yaml")
+	err := os.WriteFile(file, []byte(content), 0o644)
+	require.NoError(t, err)
+
+	err = updateVendorConfigFileVersion(file, schema.AtmosVendorSource{Component: "vpc"}, "1.2.0")
+	require.NoError(t, err)
+
+	err = updateVendorConfigFileVersion(file, schema.AtmosVendorSource{Component: "vpc-flow-logs-bucket"}, "2.0.0")
+	require.NoError(t, err)
+
+	updated, err := os.ReadFile(file)
+	require.NoError(t, err)
+
+	expected := `apiVersion: atmos/v1
+kind: AtmosVendorConfig
+spec:
+  sources:
+    # VPC component
+    - component: "vpc"
+      source: "github.com/cloudposse/terraform-aws-components.git//modules/vpc?ref={{.Version}}"
+      version: "1.2.0" # pinned
+      constraints: "~1"
+      targets:
+        - "components/terraform/vpc"
+    - component: "vpc-flow-logs-bucket"
+      source: "github.com/cloudposse/terraform-aws-components.git//modules/vpc-flow-logs-bucket?ref={{.Version}}"
+      version: 2.0.0
+      targets:
+        - "components/terraform/vpc-flow-logs-bucket"
+`
+	assert.Equal(t, expected, string(updated))
+
+	err = updateVendorConfigFileVersion(file, schema.AtmosVendorSource{Component: "unknown"}, "2.0.0")
+	assert.Error(t, err)
+}
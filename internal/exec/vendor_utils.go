@@ -1,6 +1,7 @@
 package exec
 
 import (
+	"errors"
 	"fmt"
 	"net/url"
 	"os"
@@ -46,6 +47,15 @@ func ExecuteVendorPullCommand(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	frozen, err := flags.GetBool("frozen")
+	if err != nil {
+		return err
+	}
+
+	if dryRun && frozen {
+		return fmt.Errorf("either '--dry-run' or '--frozen' flag can be provided, but not both")
+	}
+
 	component, err := flags.GetString("component")
 	if err != nil {
 		return err
@@ -105,8 +115,12 @@ func ExecuteVendorPullCommand(cmd *cobra.Command, args []string) error {
 	}
 	if vendorConfigExists {
 		// Process `vendor.yaml`
-		return ExecuteAtmosVendorInternal(atmosConfig, foundVendorConfigFile, vendorConfig.Spec, component, tags, dryRun)
+		return ExecuteAtmosVendorInternal(atmosConfig, foundVendorConfigFile, vendorConfig.Spec, component, tags, dryRun, frozen)
 	} else {
+		if frozen {
+			return fmt.Errorf("the '--frozen' flag requires the vendor config file '%s'", cfg.AtmosVendorConfigFileName)
+		}
+
 		// Check and process `component.yaml`
 		if component != "" {
 			// Process component vendoring
@@ -230,6 +244,10 @@ func ReadAndProcessVendorConfigFile(
 
 		// Merge sources, checking for duplicates
 		for _, source := range currentConfig.Spec.Sources {
+			// Record the file where the source is defined
+			if source.File == "" {
+				source.File = configFile
+			}
 			if source.Component != "" {
 				if sourceMap[source.Component] {
 					return vendorConfig, false, "", fmt.Errorf("duplicate component '%s' found in config file '%s'",
@@ -266,9 +284,11 @@ func ExecuteAtmosVendorInternal(
 	component string,
 	tags []string,
 	dryRun bool,
+	frozen bool,
 ) error {
 	var err error
 	vendorConfigFilePath := filepath.Dir(vendorConfigFileName)
+	lockFile := getVendorLockFilePath(vendorConfigFileName)
 
 	logInitialMessage(atmosConfig, vendorConfigFileName, tags)
 
@@ -341,77 +361,22 @@ func ExecuteAtmosVendorInternal(
 	//	)
 	//}
 
-	// Process sources
-	var packages []pkgAtmosVendor
-	for indexSource, s := range sources {
-		if shouldSkipSource(&s, component, tags) {
-			continue
-		}
-
-		if err := validateSourceFields(&s, vendorConfigFileName); err != nil {
-			return err
-		}
-
-		tmplData := struct {
-			Component string
-			Version   string
-		}{s.Component, s.Version}
-
-		// Parse 'source' template
-		uri, err := ProcessTmpl(fmt.Sprintf("source-%d", indexSource), s.Source, tmplData, false)
-		if err != nil {
-			return err
-		}
+	packages, err := getAtmosVendorPackages(vendorConfigFilePath, vendorConfigFileName, sources, component, tags)
+	if err != nil {
+		return err
+	}
 
-		useOciScheme, useLocalFileSystem, sourceIsLocalFile, err := determineSourceType(&uri, vendorConfigFilePath)
+	if frozen {
+		lock, lockExists, err := ReadVendorLockFile(lockFile)
 		if err != nil {
 			return err
 		}
-		if !useLocalFileSystem {
-			err = ValidateURI(uri)
-			if err != nil {
-				if strings.Contains(uri, "..") {
-					return fmt.Errorf("invalid URI for component %s: %w: Please ensure the source is a valid local path", s.Component, err)
-				}
-				return fmt.Errorf("invalid URI for component %s: %w", s.Component, err)
-			}
-		}
-
-		// Determine package type
-		var pType pkgType
-		if useOciScheme {
-			pType = pkgTypeOci
-		} else if useLocalFileSystem {
-			pType = pkgTypeLocal
-		} else {
-			pType = pkgTypeRemote
+		if !lockExists {
+			return fmt.Errorf("the '--frozen' flag is set, but the vendor lock file '%s' does not exist", lockFile)
 		}
-
-		// Process each target within the source
-		for indexTarget, tgt := range s.Targets {
-			target, err := ProcessTmpl(fmt.Sprintf("target-%d-%d", indexSource, indexTarget), tgt, tmplData, false)
-			if err != nil {
-				return err
-			}
-			targetPath := filepath.Join(filepath.ToSlash(vendorConfigFilePath), filepath.ToSlash(target))
-			pkgName := s.Component
-			if pkgName == "" {
-				pkgName = uri
-			}
-			// Create package struct
-			p := pkgAtmosVendor{
-				uri:               uri,
-				name:              pkgName,
-				targetPath:        targetPath,
-				sourceIsLocalFile: sourceIsLocalFile,
-				pkgType:           pType,
-				version:           s.Version,
-				atmosVendorSource: s,
-			}
-
-			packages = append(packages, p)
-
-			// Log the action (handled in downloadAndInstall)
+		for i := range packages {
+			packages[i].frozen = true
+			packages[i].lockedSource = findVendorLockSource(lock, &packages[i])
 		}
 	}
 
@@ -431,11 +396,41 @@ func ExecuteAtmosVendorInternal(
 		if _, err := tea.NewProgram(&model, opts...).Run(); err != nil {
 			return fmt.Errorf("failed to execute vendor operation in TUI mode: %w (check terminal state)", err)
 		}
+
+		if frozen {
+			if err := getFrozenVendorError(&model, lockFile); err != nil {
+				return err
+			}
+		}
+
+		// Record the resolved revisions and the hashes of the vendored files in the vendor lock file
+		if !dryRun && !frozen && len(model.lockSources) > 0 {
+			if err := updateVendorLockFile(lockFile, model.lockSources); err != nil {
+				return fmt.Errorf("failed to write the vendor lock file '%s': %w", lockFile, err)
+			}
+		}
 	}
 
 	return nil
 }
 
+// getFrozenVendorError returns the error for the packages that failed in the `--frozen` mode.
+// The packages that do not match the vendor lock file are reported separately from the packages that failed to download or install
+func getFrozenVendorError(model *modelVendor, lockFile string) error {
+	var errs []error
+
+	if len(model.lockMismatchPkgs) > 0 {
+		errs = append(errs, fmt.Errorf("failed to vendor %d components: the upstream sources do not match the vendor lock file '%s': %s",
+			len(model.lockMismatchPkgs), lockFile, strings.Join(model.lockMismatchPkgs, ", ")))
+	}
+
+	if failed := lo.Without(model.failedPkgs, model.lockMismatchPkgs...); len(failed) > 0 {
+		errs = append(errs, fmt.Errorf("failed to vendor %d components: %s", len(failed), strings.Join(failed, ", ")))
+	}
+
+	return errors.Join(errs...)
+}
+
 // processVendorImports processes all imports recursively and returns a list of sources
 func processVendorImports(
 	atmosConfig schema.AtmosConfiguration,
@@ -484,6 +479,96 @@ func processVendorImports(
 	return append(mergedSources, sources...), allImports, nil
 }
 
+// getAtmosVendorPackages converts the vendor sources into the packages to download and install into the targets
+func getAtmosVendorPackages(
+	vendorConfigFilePath string,
+	vendorConfigFileName string,
+	sources []schema.AtmosVendorSource,
+	component string,
+	tags []string,
+) ([]pkgAtmosVendor, error) {
+	// Process sources
+	var packages []pkgAtmosVendor
+	for indexSource, s := range sources {
+		if shouldSkipSource(&s, component, tags) {
+			continue
+		}
+
+		if err := validateSourceFields(&s, vendorConfigFileName); err != nil {
+			return nil, err
+		}
+
+		tmplData := struct {
+			Component string
+			Version   string
+		}{s.Component, s.Version}
+
+		// Parse 'source' template
+		uri, err := ProcessTmpl(fmt.Sprintf("source-%d", indexSource), s.Source, tmplData, false)
+		if err != nil {
+			return nil, err
+		}
+
+		useOciScheme, useLocalFileSystem, sourceIsLocalFile, err := determineSourceType(&uri, vendorConfigFilePath)
+		if err != nil {
+			return nil, err
+		}
+		if !useLocalFileSystem {
+			err = ValidateURI(uri)
+			if err != nil {
+				if strings.Contains(uri, "..") {
+					return nil, fmt.Errorf("invalid URI for component %s: %w: Please ensure the source is a valid local path", s.Component, err)
+				}
+				return nil, fmt.Errorf("invalid URI for component %s: %w", s.Component, err)
+			}
+		}
+
+		// Determine package type
+		var pType pkgType
+		if useOciScheme {
+			pType = pkgTypeOci
+		} else if useLocalFileSystem {
+			pType = pkgTypeLocal
+		} else {
+			pType = pkgTypeRemote
+		}
+
+		if s.Signature != nil && pType != pkgTypeOci {
+			return nil, fmt.Errorf("'signature' is only supported for the 'oci://' sources, but the source '%s' in the vendor config file '%s' is not an OCI image", s.Source, s.File)
+		}
+
+		// Process each target within the source
+		for indexTarget, tgt := range s.Targets {
+			target, err := ProcessTmpl(fmt.Sprintf("target-%d-%d", indexSource, indexTarget), tgt, tmplData, false)
+			if err != nil {
+				return nil, err
+			}
+			targetPath := filepath.Join(filepath.ToSlash(vendorConfigFilePath), filepath.ToSlash(target))
+			pkgName := s.Component
+			if pkgName == "" {
+				pkgName = uri
+			}
+			// Create package struct
+			p := pkgAtmosVendor{
+				uri:                  uri,
+				name:                 pkgName,
+				targetPath:           targetPath,
+				sourceIsLocalFile:    sourceIsLocalFile,
+				pkgType:              pType,
+				version:              s.Version,
+				atmosVendorSource:    s,
+				vendorConfigFilePath: vendorConfigFilePath,
+			}
+
+			packages = append(packages, p)
+
+			// Log the action (handled in downloadAndInstall)
+		}
+	}
+
+	return packages, nil
+}
+
 func logInitialMessage(atmosConfig schema.AtmosConfiguration, vendorConfigFileName string, tags []string) {
 	logMessage := fmt.Sprintf("Vendoring from '%s'", vendorConfigFileName)
 	if len(tags) > 0 {
@@ -503,6 +588,13 @@ func validateSourceFields(s *schema.AtmosVendorSource, vendorConfigFileName stri
 	if len(s.Targets) == 0 {
 		return fmt.Errorf("'targets' must be specified for the source '%s' in the vendor config file '%s'", s.Source, s.File)
 	}
+	if s.Checksum != "" {
+		checksum, err := parseVendorChecksum(s.Checksum)
+		if err != nil {
+			return fmt.Errorf("%w for the source '%s' in the vendor config file '%s'", err, s.Source, s.File)
+		}
+		s.Checksum = checksum
+	}
 	return nil
 }
 
@@ -529,7 +621,7 @@ func determineSourceType(uri *string, vendorConfigFilePath string) (bool, bool,
 			return useOciScheme, useLocalFileSystem, sourceIsLocalFile, fmt.Errorf("invalid source path '%s': %w", *uri, err)
 		}
 		if err == nil {
-			uri = &absPath
+			*uri = absPath
 			useLocalFileSystem = true
 			sourceIsLocalFile = u.FileExists(*uri)
 		}
@@ -560,11 +652,7 @@ func copyToTarget(atmosConfig schema.AtmosConfiguration, tempDir, targetPath str
 	}
 
 	// Adjust the target path if it's a local file with no extension
-	if sourceIsLocalFile && filepath.Ext(targetPath) == "" {
-		// Sanitize the URI for safe filenames, especially on Windows
-		sanitizedBase := SanitizeFileName(uri)
-		targetPath = filepath.Join(targetPath, sanitizedBase)
-	}
+	targetPath = getVendorTargetPath(targetPath, sourceIsLocalFile, uri)
 
 	return cp.Copy(tempDir, targetPath, copyOptions)
 }
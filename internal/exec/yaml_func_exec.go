@@ -21,15 +21,28 @@ func processTagExec(
 		u.LogErrorAndExit(err)
 	}
 
-	res, err := ExecuteShellAndReturnOutput(atmosConfig, str, input, ".", nil, false)
+	// The commands can have side effects or depend on the order of execution,
+	// so they are executed sequentially for each occurrence of the function, and not concurrently like the functions that read data
+	res, err := executeYamlFuncExec(atmosConfig, str, input)
 	if err != nil {
 		u.LogErrorAndExit(err)
 	}
 
+	return res
+}
+
+// executeYamlFuncExec executes the shell command of the `!exec` YAML function.
+// If the output of the command is JSON, it's decoded
+func executeYamlFuncExec(atmosConfig schema.AtmosConfiguration, command string, input string) (any, error) {
+	res, err := ExecuteShellAndReturnOutput(atmosConfig, command, input, ".", nil, false)
+	if err != nil {
+		return nil, err
+	}
+
 	var decoded any
 	if err = json.Unmarshal([]byte(res), &decoded); err != nil {
-		return res
+		return res, nil
 	}
 
-	return decoded
+	return decoded, nil
 }
@@ -0,0 +1,173 @@
+package exec
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/samber/lo"
+
+	"github.com/cloudposse/atmos/pkg/schema"
+	u "github.com/cloudposse/atmos/pkg/utils"
+)
+
+// yamlFuncMaxConcurrency is the maximum number of the Atmos YAML function targets resolved concurrently in a component
+const yamlFuncMaxConcurrency = 8
+
+// yamlFuncResult is the result of the resolved target of the Atmos YAML functions
+type yamlFuncResult struct {
+	value any
+	err   error
+}
+
+// yamlFuncResults are the results of the Atmos YAML function targets resolved concurrently, by the target key
+type yamlFuncResults map[string]yamlFuncResult
+
+// yamlFuncTarget is the target of the Atmos YAML functions that read the data from external sources (`!terraform.output` and `!store`).
+// The function calls with the same target (e.g. `!terraform.output` calls for the different outputs of the same component in the same stack)
+// are resolved once
+type yamlFuncTarget struct {
+	key     string
+	resolve func() (any, error)
+}
+
+// resolveYamlFuncs collects the calls of the Atmos YAML functions that read the data from external sources in the sections,
+// dedupes them by target, and resolves the targets concurrently.
+// The errors are returned in the results, and reported when the results are substituted into the sections
+func resolveYamlFuncs(
+	atmosConfig schema.AtmosConfiguration,
+	data map[string]any,
+	currentStack string,
+	skip []string,
+) yamlFuncResults {
+	targets := collectYamlFuncTargets(atmosConfig, data, currentStack, skip)
+	if len(targets) < 2 {
+		// The functions are executed sequentially when the sections are processed
+		return nil
+	}
+
+	u.LogTrace(fmt.Sprintf("Resolving %d Atmos YAML function targets concurrently in the stack '%s'", len(targets), currentStack))
+
+	return resolveYamlFuncTargets(targets, yamlFuncMaxConcurrency)
+}
+
+// collectYamlFuncTargets returns the unique targets of the Atmos YAML function calls in the sections, in the sorted order of the keys
+func collectYamlFuncTargets(
+	atmosConfig schema.AtmosConfiguration,
+	data map[string]any,
+	currentStack string,
+	skip []string,
+) []yamlFuncTarget {
+	var targets []yamlFuncTarget
+	seen := map[string]bool{}
+
+	var walk func(any)
+	walk = func(node any) {
+		switch v := node.(type) {
+		case string:
+			target, ok := getYamlFuncTarget(atmosConfig, v, currentStack, skip)
+			if ok && !seen[target.key] {
+				seen[target.key] = true
+				targets = append(targets, target)
+			}
+
+		case map[string]any:
+			keys := lo.Keys(v)
+			sort.Strings(keys)
+			for _, k := range keys {
+				walk(v[k])
+			}
+
+		case []any:
+			for _, val := range v {
+				walk(val)
+			}
+		}
+	}
+
+	walk(data)
+
+	return targets
+}
+
+// getYamlFuncTarget returns the target of the Atmos YAML function call.
+// It returns `false` if the function does not read the data from external sources, or if the call is invalid,
+// in which case the call is processed (and the error is reported) when the sections are processed.
+// The `!exec` function is not resolved concurrently, since the commands can have side effects or depend on the order of execution
+func getYamlFuncTarget(
+	atmosConfig schema.AtmosConfiguration,
+	input string,
+	currentStack string,
+	skip []string,
+) (yamlFuncTarget, bool) {
+	switch {
+	case strings.HasPrefix(input, u.AtmosYamlFuncTerraformOutput) && !skipFunc(skip, u.AtmosYamlFuncTerraformOutput):
+		component, stack, _, err := parseTagTerraformOutput(input, currentStack)
+		if err != nil {
+			return yamlFuncTarget{}, false
+		}
+		if _, found := terraformOutputsCache.Load(fmt.Sprintf("%s-%s", stack, component)); found {
+			return yamlFuncTarget{}, false
+		}
+		return yamlFuncTarget{
+			key: terraformOutputYamlFuncKey(component, stack),
+			resolve: func() (any, error) {
+				// The outputs are cached in memory and read from the cache when the function is processed
+				_, _, err := fetchTerraformOutputs(&atmosConfig, component, stack)
+				return nil, err
+			},
+		}, true
+
+	case strings.HasPrefix(input, u.AtmosYamlFuncStore) && !skipFunc(skip, u.AtmosYamlFuncStore):
+		p, err := parseTagStore(input, currentStack)
+		if err != nil {
+			return yamlFuncTarget{}, false
+		}
+		store := atmosConfig.Stores[p.storeName]
+		if store == nil {
+			return yamlFuncTarget{}, false
+		}
+		return yamlFuncTarget{
+			key: storeYamlFuncKey(p),
+			resolve: func() (any, error) {
+				return store.Get(p.stack, p.component, p.key)
+			},
+		}, true
+
+	default:
+		return yamlFuncTarget{}, false
+	}
+}
+
+// resolveYamlFuncTargets resolves the targets with up to `maxConcurrency` workers
+func resolveYamlFuncTargets(targets []yamlFuncTarget, maxConcurrency int) yamlFuncResults {
+	results := make([]yamlFuncResult, len(targets))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < min(maxConcurrency, len(targets)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				value, err := targets[i].resolve()
+				results[i] = yamlFuncResult{value: value, err: err}
+			}
+		}()
+	}
+
+	for i := range targets {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	resolved := make(yamlFuncResults, len(targets))
+	for i, target := range targets {
+		resolved[target.key] = results[i]
+	}
+
+	return resolved
+}
@@ -0,0 +1,148 @@
+package exec
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudposse/atmos/pkg/schema"
+	"github.com/cloudposse/atmos/pkg/store"
+)
+
+func TestCollectYamlFuncTargets(t *testing.T) {
+	s := miniredis.RunT(t)
+	redisURL := fmt.Sprintf("redis://%s", s.Addr())
+	redisStore, err := store.NewRedisStore(store.RedisStoreOptions{URL: &redisURL})
+	require.NoError(t, err)
+
+	atmosConfig := schema.AtmosConfiguration{Stores: map[string]store.Store{"redis": redisStore}}
+
+	data := map[string]any{
+		"vars": map[string]any{
+			"b_cidr":   "!store redis vpc cidr",
+			"a_cidr":   "!store redis dev vpc cidr | default 10.0.0.0/16",
+			"prod":     "!store redis prod vpc cidr",
+			"missing":  "!store unknown vpc cidr",
+			"invalid":  "!terraform.output vpc",
+			"region":   "!env AWS_REGION",
+			"commands": []any{"!exec echo 1", "!exec echo 1", "plain"},
+		},
+	}
+
+	targets := collectYamlFuncTargets(atmosConfig, data, "dev", nil)
+
+	keys := make([]string, 0, len(targets))
+	for _, target := range targets {
+		keys = append(keys, target.key)
+	}
+
+	// The calls are deduped by target in the sorted order of the keys.
+	// The calls with invalid arguments or unknown stores, and the `!exec` calls are processed sequentially
+	assert.Equal(t, []string{
+		storeYamlFuncKey(params{storeName: "redis", stack: "dev", component: "vpc", key: "cidr"}),
+		storeYamlFuncKey(params{storeName: "redis", stack: "prod", component: "vpc", key: "cidr"}),
+	}, keys)
+
+	assert.Empty(t, collectYamlFuncTargets(atmosConfig, data, "dev", []string{"store"}))
+}
+
+func TestResolveYamlFuncTargets(t *testing.T) {
+	var running, maxRunning atomic.Int32
+
+	var targets []yamlFuncTarget
+	for i := 0; i < 10; i++ {
+		targets = append(targets, yamlFuncTarget{
+			key: fmt.Sprintf("target-%d", i),
+			resolve: func() (any, error) {
+				n := running.Add(1)
+				defer running.Add(-1)
+				for {
+					m := maxRunning.Load()
+					if n <= m || maxRunning.CompareAndSwap(m, n) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+				if i == 7 {
+					return nil, errors.New("failed")
+				}
+				return i, nil
+			},
+		})
+	}
+
+	resolved := resolveYamlFuncTargets(targets, 3)
+	assert.Len(t, resolved, 10)
+	assert.LessOrEqual(t, maxRunning.Load(), int32(3))
+	assert.Equal(t, 4, resolved["target-4"].value)
+	assert.EqualError(t, resolved["target-7"].err, "failed")
+}
+
+func TestProcessNodesConcurrently(t *testing.T) {
+	s := miniredis.RunT(t)
+	redisURL := fmt.Sprintf("redis://%s", s.Addr())
+	redisStore, err := store.NewRedisStore(store.RedisStoreOptions{URL: &redisURL})
+	require.NoError(t, err)
+
+	require.NoError(t, redisStore.Set("dev", "vpc", "cidr", "10.0.0.0/16"))
+	require.NoError(t, redisStore.Set("prod", "vpc", "cidr", "172.16.0.0/16"))
+
+	atmosConfig := schema.AtmosConfiguration{Stores: map[string]store.Store{"redis": redisStore}}
+
+	data := map[string]any{
+		"vars": map[string]any{
+			"dev_cidr":     "!store redis vpc cidr",
+			"prod_cidr":    "!store redis prod vpc cidr",
+			"staging_cidr": "!store redis staging vpc cidr | default 10.1.0.0/16",
+			"config":       `!exec echo '{"enabled": true}'`,
+			"cidrs":        []any{"!store redis vpc cidr", "!store redis prod vpc cidr"},
+		},
+	}
+
+	result := processNodes(atmosConfig, data, "dev", nil)
+	assert.Equal(t, map[string]any{
+		"vars": map[string]any{
+			"dev_cidr":     "10.0.0.0/16",
+			"prod_cidr":    "172.16.0.0/16",
+			"staging_cidr": "10.1.0.0/16",
+			"config":       map[string]any{"enabled": true},
+			"cidrs":        []any{"10.0.0.0/16", "172.16.0.0/16"},
+		},
+	}, result)
+}
+
+func TestProcessNodesExecSequentially(t *testing.T) {
+	s := miniredis.RunT(t)
+	redisURL := fmt.Sprintf("redis://%s", s.Addr())
+	redisStore, err := store.NewRedisStore(store.RedisStoreOptions{URL: &redisURL})
+	require.NoError(t, err)
+
+	require.NoError(t, redisStore.Set("dev", "vpc", "cidr", "10.0.0.0/16"))
+	require.NoError(t, redisStore.Set("prod", "vpc", "cidr", "172.16.0.0/16"))
+
+	atmosConfig := schema.AtmosConfiguration{Stores: map[string]store.Store{"redis": redisStore}}
+
+	// The identical commands are executed for each occurrence, in the order of the sections
+	counter := filepath.Join(t.TempDir(), "counter")
+	command := fmt.Sprintf("!exec echo x >> %s && wc -l < %s", counter, counter)
+
+	data := map[string]any{
+		"vars": map[string]any{
+			"dev_cidr":  "!store redis vpc cidr",
+			"prod_cidr": "!store redis prod vpc cidr",
+			"runs":      []any{command, command, command},
+		},
+	}
+
+	result := processNodes(atmosConfig, data, "dev", nil)
+	vars := result["vars"].(map[string]any)
+	assert.Equal(t, "10.0.0.0/16", vars["dev_cidr"])
+	assert.Equal(t, []any{float64(1), float64(2), float64(3)}, vars["runs"])
+}
@@ -1,6 +1,7 @@
 package exec
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -18,14 +19,58 @@ type params struct {
 	defaultValue *string
 }
 
-func processTagStore(atmosConfig schema.AtmosConfiguration, input string, currentStack string) any {
+func processTagStore(atmosConfig schema.AtmosConfiguration, input string, currentStack string, resolved yamlFuncResults) any {
 	log.Debug("Executing Atmos YAML function store", "input", input)
 
-	str, err := getStringAfterTag(input, u.AtmosYamlFuncStore)
+	retParams, err := parseTagStore(input, currentStack)
 	if err != nil {
+		var invalidErr invalidStoreYamlFuncError
+		if errors.As(err, &invalidErr) {
+			log.Error(invalidErr.Error())
+			return invalidErr.Error()
+		}
 		u.LogErrorAndExit(err)
 	}
 
+	// Retrieve the store from atmosConfig
+	store := atmosConfig.Stores[retParams.storeName]
+
+	if store == nil {
+		u.LogErrorAndExit(fmt.Errorf("invalid Atmos Store YAML function execution:: %s\nstore '%s' not found", input, retParams.storeName))
+	}
+
+	// Retrieve the value from the store, unless it was retrieved concurrently
+	var value any
+	if res, ok := resolved[storeYamlFuncKey(retParams)]; ok {
+		value, err = res.value, res.err
+	} else {
+		value, err = store.Get(retParams.stack, retParams.component, retParams.key)
+	}
+	if err != nil {
+		if retParams.defaultValue != nil {
+			return *retParams.defaultValue
+		}
+		u.LogErrorAndExit(fmt.Errorf("failed to get key: %s", err))
+	}
+
+	return value
+}
+
+// invalidStoreYamlFuncError is returned for invalid arguments of the `!store` YAML function.
+// The error message is returned as the result of the function
+type invalidStoreYamlFuncError string
+
+func (e invalidStoreYamlFuncError) Error() string {
+	return string(e)
+}
+
+// parseTagStore returns the arguments of the `!store` YAML function
+func parseTagStore(input string, currentStack string) (params, error) {
+	str, err := getStringAfterTag(input, u.AtmosYamlFuncStore)
+	if err != nil {
+		return params{}, err
+	}
+
 	// Split the input on the pipe symbol to separate the store parameters and default value
 	parts := strings.Split(str, "|")
 	storePart := strings.TrimSpace(parts[0])
@@ -35,8 +80,7 @@ func processTagStore(atmosConfig schema.AtmosConfiguration, input string, curren
 		// Expecting the format: default <value>
 		defaultParts := strings.Fields(strings.TrimSpace(parts[1]))
 		if len(defaultParts) != 2 || defaultParts[0] != "default" {
-			log.Error(fmt.Sprintf("invalid default value format in: %s", str))
-			return fmt.Sprintf("invalid default value format in: %s", str)
+			return params{}, invalidStoreYamlFuncError(fmt.Sprintf("invalid default value format in: %s", str))
 		}
 		val := strings.Trim(defaultParts[1], `"'`) // Remove surrounding quotes if present
 		defaultValue = &val
@@ -46,7 +90,7 @@ func processTagStore(atmosConfig schema.AtmosConfiguration, input string, curren
 	storeParts := strings.Fields(storePart)
 	partsLength := len(storeParts)
 	if partsLength != 3 && partsLength != 4 {
-		return fmt.Sprintf("invalid Atmos Store YAML function execution:: %s\ninvalid parameters: store_name, {stack}, component, key", input)
+		return params{}, invalidStoreYamlFuncError(fmt.Sprintf("invalid Atmos Store YAML function execution:: %s\ninvalid parameters: store_name, {stack}, component, key", input))
 	}
 
 	retParams := params{
@@ -64,21 +108,10 @@ func processTagStore(atmosConfig schema.AtmosConfiguration, input string, curren
 		retParams.key = strings.TrimSpace(storeParts[2])
 	}
 
-	// Retrieve the store from atmosConfig
-	store := atmosConfig.Stores[retParams.storeName]
-
-	if store == nil {
-		u.LogErrorAndExit(fmt.Errorf("invalid Atmos Store YAML function execution:: %s\nstore '%s' not found", input, retParams.storeName))
-	}
-
-	// Retrieve the value from the store
-	value, err := store.Get(retParams.stack, retParams.component, retParams.key)
-	if err != nil {
-		if retParams.defaultValue != nil {
-			return *retParams.defaultValue
-		}
-		u.LogErrorAndExit(fmt.Errorf("failed to get key: %s", err))
-	}
+	return retParams, nil
+}
 
-	return value
+// storeYamlFuncKey returns the target of the `!store` YAML function
+func storeYamlFuncKey(p params) string {
+	return strings.Join([]string{u.AtmosYamlFuncStore, p.storeName, p.stack, p.component, p.key}, "\x00")
 }
@@ -13,17 +13,30 @@ func processTagTerraformOutput(
 	atmosConfig schema.AtmosConfiguration,
 	input string,
 	currentStack string,
+	resolved yamlFuncResults,
 ) any {
 	u.LogTrace(fmt.Sprintf("Executing Atmos YAML function: %s", input))
 
-	str, err := getStringAfterTag(input, config.AtmosYamlFuncTerraformOutput)
+	component, stack, output, err := parseTagTerraformOutput(input, currentStack)
 	if err != nil {
 		u.LogErrorAndExit(err)
 	}
 
-	var component string
-	var stack string
-	var output string
+	// If the outputs of the component were fetched concurrently, they are in the cache
+	if res, ok := resolved[terraformOutputYamlFuncKey(component, stack)]; ok && res.err != nil {
+		u.LogErrorAndExit(res.err)
+	}
+
+	value := GetTerraformOutput(&atmosConfig, stack, component, output, false)
+	return value
+}
+
+// parseTagTerraformOutput returns the component, stack and output arguments of the `!terraform.output` YAML function
+func parseTagTerraformOutput(input string, currentStack string) (component string, stack string, output string, err error) {
+	str, err := getStringAfterTag(input, config.AtmosYamlFuncTerraformOutput)
+	if err != nil {
+		return "", "", "", err
+	}
 
 	// Split the string into slices based on any whitespace (one or more spaces, tabs, or newlines),
 	// while also ignoring leading and trailing whitespace
@@ -41,10 +54,14 @@ func processTagTerraformOutput(
 		u.LogTrace(fmt.Sprintf("Atmos YAML function `%s` is called with two parameters 'component' and 'output'. "+
 			"Using the current stack '%s' as the 'stack' parameter", input, currentStack))
 	} else {
-		err := fmt.Errorf("invalid number of arguments in the Atmos YAML function: %s", input)
-		u.LogErrorAndExit(err)
+		return "", "", "", fmt.Errorf("invalid number of arguments in the Atmos YAML function: %s", input)
 	}
 
-	value := GetTerraformOutput(&atmosConfig, stack, component, output, false)
-	return value
+	return component, stack, output, nil
+}
+
+// terraformOutputYamlFuncKey returns the target of the `!terraform.output` YAML function.
+// All the outputs of a component in a stack are fetched at once
+func terraformOutputYamlFuncKey(component string, stack string) string {
+	return strings.Join([]string{config.AtmosYamlFuncTerraformOutput, stack, component}, "\x00")
 }
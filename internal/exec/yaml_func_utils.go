@@ -2,8 +2,11 @@ package exec
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
+	"github.com/samber/lo"
+
 	"github.com/cloudposse/atmos/pkg/schema"
 	u "github.com/cloudposse/atmos/pkg/utils"
 )
@@ -23,18 +26,24 @@ func processNodes(
 	currentStack string,
 	skip []string,
 ) map[string]any {
+	// Resolve the functions that read the data from external sources concurrently before the results are substituted
+	resolved := resolveYamlFuncs(atmosConfig, data, currentStack, skip)
+
 	newMap := make(map[string]any)
 	var recurse func(any) any
 
 	recurse = func(node any) any {
 		switch v := node.(type) {
 		case string:
-			return processCustomTags(atmosConfig, v, currentStack, skip)
+			return processCustomTags(atmosConfig, v, currentStack, skip, resolved)
 
 		case map[string]any:
+			// Process the keys in the sorted order, so the first error is reported deterministically
 			newNestedMap := make(map[string]any)
-			for k, val := range v {
-				newNestedMap[k] = recurse(val)
+			keys := lo.Keys(v)
+			sort.Strings(keys)
+			for _, k := range keys {
+				newNestedMap[k] = recurse(v[k])
 			}
 			return newNestedMap
 
@@ -50,8 +59,10 @@ func processNodes(
 		}
 	}
 
-	for k, v := range data {
-		newMap[k] = recurse(v)
+	keys := lo.Keys(data)
+	sort.Strings(keys)
+	for _, k := range keys {
+		newMap[k] = recurse(data[k])
 	}
 
 	return newMap
@@ -62,6 +73,7 @@ func processCustomTags(
 	input string,
 	currentStack string,
 	skip []string,
+	resolved yamlFuncResults,
 ) any {
 	switch {
 	case strings.HasPrefix(input, u.AtmosYamlFuncTemplate) && !skipFunc(skip, u.AtmosYamlFuncTemplate):
@@ -69,9 +81,9 @@ func processCustomTags(
 	case strings.HasPrefix(input, u.AtmosYamlFuncExec) && !skipFunc(skip, u.AtmosYamlFuncExec):
 		return processTagExec(atmosConfig, input, currentStack)
 	case strings.HasPrefix(input, u.AtmosYamlFuncStore) && !skipFunc(skip, u.AtmosYamlFuncStore):
-		return processTagStore(atmosConfig, input, currentStack)
+		return processTagStore(atmosConfig, input, currentStack, resolved)
 	case strings.HasPrefix(input, u.AtmosYamlFuncTerraformOutput) && !skipFunc(skip, u.AtmosYamlFuncTerraformOutput):
-		return processTagTerraformOutput(atmosConfig, input, currentStack)
+		return processTagTerraformOutput(atmosConfig, input, currentStack, resolved)
 	case strings.HasPrefix(input, u.AtmosYamlFuncEnv) && !skipFunc(skip, u.AtmosYamlFuncEnv):
 		return processTagEnv(atmosConfig, input, currentStack)
 	case strings.HasPrefix(input, u.AtmosYamlFuncIncludeGoGetter) && !skipFunc(skip, u.AtmosYamlFuncInclude):
@@ -35,6 +35,20 @@ func GetCacheFilePath() (string, error) {
 	return filepath.Join(cacheDir, "cache.yaml"), nil
 }
 
+// GetAtmosCacheDir returns the path to the Atmos cache directory with the subdirectories, e.g. `$XDG_CACHE_HOME/atmos/vendor`.
+// If `XDG_CACHE_HOME` is not set, the user cache directory is used (e.g. `~/.cache/atmos/vendor` on Linux)
+func GetAtmosCacheDir(elem ...string) (string, error) {
+	cacheHome := os.Getenv("XDG_CACHE_HOME")
+	if cacheHome == "" {
+		userCacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to find the user cache directory: %w", err)
+		}
+		cacheHome = userCacheDir
+	}
+	return filepath.Join(append([]string{cacheHome, "atmos"}, elem...)...), nil
+}
+
 func withCacheFileLock(cacheFile string, fn func() error) error {
 	lock := flock.New(cacheFile)
 	err := lock.Lock()
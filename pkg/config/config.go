@@ -45,6 +45,10 @@ var (
 				InitRunReconfigure:      true,
 				AutoGenerateBackendFile: true,
 				AppendUserAgent:         fmt.Sprintf("Atmos/%s (Cloud Posse; +https://atmos.tools)", version.Version),
+				OutputsCache: schema.TerraformOutputsCache{
+					Enabled: false,
+					TTL:     DefaultTerraformOutputsTTL,
+				},
 			},
 			Helmfile: schema.Helmfile{
 				BasePath:              "components/helmfile",
@@ -74,6 +78,11 @@ var (
 		Workflows: schema.Workflows{
 			BasePath: "stacks/workflows",
 		},
+		Audit: schema.Audit{
+			Enabled:    false,
+			MaxSize:    DefaultAuditMaxSize,
+			MaxBackups: DefaultAuditMaxBackups,
+		},
 		Logs: schema.Logs{
 			File:  "/dev/stderr",
 			Level: "Info",
@@ -133,6 +142,11 @@ func InitCliConfig(configAndStacksInfo schema.ConfigAndStacksInfo, processStacks
 	v.SetDefault("components.helmfile.use_eks", true)
 	v.SetDefault("components.terraform.append_user_agent", fmt.Sprintf("Atmos/%s (Cloud Posse; +https://atmos.tools)", version.Version))
 	v.SetDefault("settings.inject_github_token", true)
+	v.SetDefault("components.terraform.outputs_cache.ttl", DefaultTerraformOutputsTTL)
+	v.SetDefault("vendor.concurrency", DefaultVendorConcurrency)
+	v.SetDefault("vendor.retries", DefaultVendorRetries)
+	v.SetDefault("audit.max_size", DefaultAuditMaxSize)
+	v.SetDefault("audit.max_backups", DefaultAuditMaxBackups)
 
 	v.SetDefault("logs.file", "/dev/stderr")
 	v.SetDefault("logs.level", "Info")
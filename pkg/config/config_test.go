@@ -81,3 +81,52 @@ func TestInitCliConfig(t *testing.T) {
 		t.Errorf("Cli config path should be a directory, got %s", atmosConfig.CliConfigPath)
 	}
 }
+
+func TestGetAtmosCacheDir(t *testing.T) {
+	cacheHome := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cacheHome)
+
+	dir, err := GetAtmosCacheDir("vendor")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(cacheHome, "atmos", "vendor"), dir)
+
+	t.Setenv("XDG_CACHE_HOME", "")
+	userCacheDir, err := os.UserCacheDir()
+	if err != nil {
+		t.Skip("the user cache directory is not available")
+	}
+
+	dir, err = GetAtmosCacheDir("terraform-outputs", "abc")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(userCacheDir, "atmos", "terraform-outputs", "abc"), dir)
+}
+
+func TestInitCliConfigOutputsCacheDefaultTTL(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := "base_path: ./\ncomponents:\n  terraform:\n    outputs_cache:\n      enabled: true\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "atmos.yaml"), []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write atmos.yaml file: %v", err)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current working directory: %v", err)
+	}
+	defer func() {
+		if err := os.Chdir(cwd); err != nil {
+			t.Errorf("Failed to change directory back: %v", err)
+		}
+	}()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("Failed to change to temp directory: %v", err)
+	}
+
+	atmosConfig, err := InitCliConfig(schema.ConfigAndStacksInfo{}, false)
+	if err != nil {
+		t.Fatalf("Failed to initialize atmos config: %v", err)
+	}
+
+	// The `ttl` defaults to `1h` if only `enabled` is configured
+	assert.True(t, atmosConfig.Components.Terraform.OutputsCache.Enabled)
+	assert.Equal(t, DefaultTerraformOutputsTTL, atmosConfig.Components.Terraform.OutputsCache.TTL)
+}
@@ -40,12 +40,21 @@ const (
 
 	ComponentVendorConfigFileName = "component.yaml"
 	AtmosVendorConfigFileName     = "vendor"
+	AtmosVendorLockFileName       = "vendor.lock.yaml"
+	AtmosStacksLockFileName       = "stacks.lock.yaml"
+	DefaultVendorConcurrency      = 4
+	DefaultVendorRetries          = 2
+	DefaultTerraformConcurrency   = 4
+	DefaultTerraformOutputsTTL    = "1h"
+	DefaultAuditMaxSize           = 10
+	DefaultAuditMaxBackups        = 5
 
 	ImportSectionName                 = "import"
 	OverridesSectionName              = "overrides"
 	ProvidersSectionName              = "providers"
 	HooksSectionName                  = "hooks"
 	VarsSectionName                   = "vars"
+	LocalsSectionName                 = "locals"
 	SettingsSectionName               = "settings"
 	EnvSectionName                    = "env"
 	BackendSectionName                = "backend"
@@ -70,6 +79,25 @@ const (
 
 	QueryFlag = "--query"
 
+	AllFlag            = "--all"
+	ComponentsFlag     = "--components"
+	MaxConcurrencyFlag = "--max-concurrency"
+
+	AffectedFlag          = "--affected"
+	RefFlag               = "--ref"
+	SHAFlag               = "--sha"
+	RepoPathFlag          = "--repo-path"
+	SSHKeyFlag            = "--ssh-key"
+	SSHKeyPasswordFlag    = "--ssh-key-password"
+	CloneTargetRefFlag    = "--clone-target-ref"
+	IncludeDependentsFlag = "--include-dependents"
+
+	PlanSummaryFlag       = "--summary"
+	PlanSummaryFormatFlag = "--summary-format"
+	PlanSummaryFileFlag   = "--summary-file"
+
+	WorkspaceLockTimeoutFlag = "--workspace-lock-timeout"
+
 	SettingsListMergeStrategyFlag = "--settings-list-merge-strategy"
 
 	// Atmos Pro
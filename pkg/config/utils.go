@@ -196,6 +196,42 @@ func processEnvVars(atmosConfig *schema.AtmosConfiguration) error {
 		atmosConfig.Vendor.BasePath = vendorBasePath
 	}
 
+	vendorConcurrency := os.Getenv("ATMOS_VENDOR_CONCURRENCY")
+	if len(vendorConcurrency) > 0 {
+		u.LogDebug(fmt.Sprintf("Found ENV var ATMOS_VENDOR_CONCURRENCY=%s", vendorConcurrency))
+		vendorConcurrencyInt, err := strconv.Atoi(vendorConcurrency)
+		if err != nil {
+			return err
+		}
+		atmosConfig.Vendor.Concurrency = vendorConcurrencyInt
+	}
+
+	vendorRetries := os.Getenv("ATMOS_VENDOR_RETRIES")
+	if len(vendorRetries) > 0 {
+		u.LogDebug(fmt.Sprintf("Found ENV var ATMOS_VENDOR_RETRIES=%s", vendorRetries))
+		vendorRetriesInt, err := strconv.Atoi(vendorRetries)
+		if err != nil {
+			return err
+		}
+		atmosConfig.Vendor.Retries = vendorRetriesInt
+	}
+
+	auditEnabled := os.Getenv("ATMOS_AUDIT_ENABLED")
+	if len(auditEnabled) > 0 {
+		u.LogDebug(fmt.Sprintf("Found ENV var ATMOS_AUDIT_ENABLED=%s", auditEnabled))
+		auditEnabledBool, err := strconv.ParseBool(auditEnabled)
+		if err != nil {
+			return err
+		}
+		atmosConfig.Audit.Enabled = auditEnabledBool
+	}
+
+	auditPath := os.Getenv("ATMOS_AUDIT_PATH")
+	if len(auditPath) > 0 {
+		u.LogDebug(fmt.Sprintf("Found ENV var ATMOS_AUDIT_PATH=%s", auditPath))
+		atmosConfig.Audit.Path = auditPath
+	}
+
 	stacksBasePath := os.Getenv("ATMOS_STACKS_BASE_PATH")
 	if len(stacksBasePath) > 0 {
 		u.LogDebug(fmt.Sprintf("Found ENV var ATMOS_STACKS_BASE_PATH=%s", stacksBasePath))
@@ -226,6 +262,16 @@ func processEnvVars(atmosConfig *schema.AtmosConfiguration) error {
 		atmosConfig.Stacks.NameTemplate = stacksNameTemplate
 	}
 
+	stacksImportsAllowUnpinned := os.Getenv("ATMOS_STACKS_IMPORTS_ALLOW_UNPINNED")
+	if len(stacksImportsAllowUnpinned) > 0 {
+		u.LogDebug(fmt.Sprintf("Found ENV var ATMOS_STACKS_IMPORTS_ALLOW_UNPINNED=%s", stacksImportsAllowUnpinned))
+		stacksImportsAllowUnpinnedBool, err := strconv.ParseBool(stacksImportsAllowUnpinned)
+		if err != nil {
+			return err
+		}
+		atmosConfig.Stacks.Imports.AllowUnpinned = stacksImportsAllowUnpinnedBool
+	}
+
 	componentsTerraformCommand := os.Getenv("ATMOS_COMPONENTS_TERRAFORM_COMMAND")
 	if len(componentsTerraformCommand) > 0 {
 		u.LogDebug(fmt.Sprintf("Found ENV var ATMOS_COMPONENTS_TERRAFORM_COMMAND=%s", componentsTerraformCommand))
@@ -278,6 +324,22 @@ func processEnvVars(atmosConfig *schema.AtmosConfiguration) error {
 		atmosConfig.Components.Terraform.AutoGenerateBackendFile = componentsTerraformAutoGenerateBackendFileBool
 	}
 
+	componentsTerraformOutputsCacheEnabled := os.Getenv("ATMOS_COMPONENTS_TERRAFORM_OUTPUTS_CACHE_ENABLED")
+	if len(componentsTerraformOutputsCacheEnabled) > 0 {
+		u.LogDebug(fmt.Sprintf("Found ENV var ATMOS_COMPONENTS_TERRAFORM_OUTPUTS_CACHE_ENABLED=%s", componentsTerraformOutputsCacheEnabled))
+		componentsTerraformOutputsCacheEnabledBool, err := strconv.ParseBool(componentsTerraformOutputsCacheEnabled)
+		if err != nil {
+			return err
+		}
+		atmosConfig.Components.Terraform.OutputsCache.Enabled = componentsTerraformOutputsCacheEnabledBool
+	}
+
+	componentsTerraformOutputsCacheTTL := os.Getenv("ATMOS_COMPONENTS_TERRAFORM_OUTPUTS_CACHE_TTL")
+	if len(componentsTerraformOutputsCacheTTL) > 0 {
+		u.LogDebug(fmt.Sprintf("Found ENV var ATMOS_COMPONENTS_TERRAFORM_OUTPUTS_CACHE_TTL=%s", componentsTerraformOutputsCacheTTL))
+		atmosConfig.Components.Terraform.OutputsCache.TTL = componentsTerraformOutputsCacheTTL
+	}
+
 	componentsHelmfileCommand := os.Getenv("ATMOS_COMPONENTS_HELMFILE_COMMAND")
 	if len(componentsHelmfileCommand) > 0 {
 		u.LogDebug(fmt.Sprintf("Found ENV var ATMOS_COMPONENTS_HELMFILE_COMMAND=%s", componentsHelmfileCommand))
@@ -50,6 +50,24 @@ func MergeWithOptions(
 			return nil, err
 		}
 
+		currentMap, ok := dataCurrent.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		// The map tagged with `!replace` replaces the maps deep-merged before it
+		if GetMapMergeDirective(currentMap) == MergeDirectiveReplace {
+			merged = map[string]any{}
+		}
+
+		// The lists and maps with merge directives (e.g. `!append`) are deep-merged according to the directives
+		// instead of the list merge strategy
+		directiveValues, err := extractMergeDirectives(merged, currentMap, nil, appendSlice, sliceDeepCopy)
+		if err != nil {
+			_, _ = theme.Colors.Error.Fprintln(color.Error, err.Error()+"\n")
+			return nil, err
+		}
+
 		var opts []func(*mergo.Config)
 		opts = append(opts, mergo.WithOverride, mergo.WithTypeCheck)
 
@@ -64,10 +82,14 @@ func MergeWithOptions(
 			opts = append(opts, mergo.WithAppendSlice)
 		}
 
-		if err = mergo.Merge(&merged, dataCurrent, opts...); err != nil {
+		if err = mergo.Merge(&merged, currentMap, opts...); err != nil {
 			_, _ = theme.Colors.Error.Fprintln(color.Error, err.Error()+"\n")
 			return nil, err
 		}
+
+		for _, v := range directiveValues {
+			setMergeDirectiveValue(merged, v.path, v.value)
+		}
 	}
 
 	return merged, nil
@@ -0,0 +1,197 @@
+package merge
+
+import (
+	"slices"
+	"strings"
+
+	u "github.com/cloudposse/atmos/pkg/utils"
+)
+
+const (
+	MergeDirectiveAppend  = "append"
+	MergeDirectivePrepend = "prepend"
+	MergeDirectiveReplace = "replace"
+	MergeDirectiveMerge   = "merge"
+)
+
+// mergeDirectiveValue is a list or a map with a merge directive, deep-merged after the other values at the path
+type mergeDirectiveValue struct {
+	path  []string
+	value any
+}
+
+// GetListMergeDirective returns the merge directive of the list (e.g. `append` for the lists tagged with `!append`)
+// and the list items without the directive marker
+func GetListMergeDirective(list []any) (string, []any) {
+	if len(list) == 0 {
+		return "", list
+	}
+	if s, ok := list[0].(string); ok {
+		if directive, ok := strings.CutPrefix(s, u.AtmosMergeDirectiveMarker+":"); ok {
+			return directive, list[1:]
+		}
+	}
+	return "", list
+}
+
+// GetMapMergeDirective returns the merge directive of the map (e.g. `replace` for the maps tagged with `!replace`)
+func GetMapMergeDirective(m map[string]any) string {
+	directive, _ := m[u.AtmosMergeDirectiveMarker].(string)
+	return directive
+}
+
+// IsUnset returns `true` if the value is tagged with `!unset`
+func IsUnset(value any) bool {
+	s, ok := value.(string)
+	return ok && s == u.AtmosUnsetMarker
+}
+
+// RemoveMergeDirectives removes the merge directive markers from the lists and maps in the value,
+// removes the keys tagged with `!unset` from the maps, and returns the value.
+// The maps are updated in place
+func RemoveMergeDirectives(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		delete(v, u.AtmosMergeDirectiveMarker)
+		for k, item := range v {
+			if IsUnset(item) {
+				delete(v, k)
+				continue
+			}
+			v[k] = RemoveMergeDirectives(item)
+		}
+		return v
+
+	case []any:
+		_, items := GetListMergeDirective(v)
+		for i, item := range items {
+			items[i] = RemoveMergeDirectives(item)
+		}
+		return items
+	}
+
+	return value
+}
+
+// extractMergeDirectives removes the lists and maps with merge directives from `src`, and returns them deep-merged with the values at
+// the same paths in `dst` according to the directives. They are set in the result after `src` is deep-merged into `dst` with the
+// list merge strategy.
+// The keys tagged with `!unset` keep the unset marker in the result, so the key is also removed when the result is deep-merged
+// with the values defined before (e.g. in the base components). The keys are removed from the final config by RemoveMergeDirectives.
+// Where a list without a directive in `src` is deep-merged by index, the directive marker of the list in `dst` is removed
+// to keep the indexes of the items aligned
+func extractMergeDirectives(
+	dst map[string]any,
+	src map[string]any,
+	path []string,
+	appendSlice bool,
+	sliceDeepCopy bool,
+) ([]mergeDirectiveValue, error) {
+	var result []mergeDirectiveValue
+
+	for k, srcValue := range src {
+		// The key was unset by `!unset`, so the value is not deep-merged with the value defined before the key was unset
+		if IsUnset(dst[k]) {
+			delete(dst, k)
+		}
+
+		dstValue := dst[k]
+		valuePath := append(slices.Clone(path), k)
+
+		switch v := srcValue.(type) {
+		case []any:
+			directive, items := GetListMergeDirective(v)
+			if directive == "" {
+				if dstList, ok := dstValue.([]any); ok && sliceDeepCopy {
+					_, dst[k] = GetListMergeDirective(dstList)
+				}
+				continue
+			}
+
+			merged, err := mergeListWithDirective(directive, dstValue, items, appendSlice, sliceDeepCopy)
+			if err != nil {
+				return nil, err
+			}
+
+			delete(src, k)
+			result = append(result, mergeDirectiveValue{
+				path:  valuePath,
+				value: append([]any{v[0]}, merged...),
+			})
+
+		case map[string]any:
+			if GetMapMergeDirective(v) == MergeDirectiveReplace {
+				delete(src, k)
+				result = append(result, mergeDirectiveValue{path: valuePath, value: v})
+				continue
+			}
+
+			dstMap, _ := dstValue.(map[string]any)
+			if dstMap == nil {
+				dstMap = map[string]any{}
+			}
+
+			nested, err := extractMergeDirectives(dstMap, v, valuePath, appendSlice, sliceDeepCopy)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, nested...)
+		}
+	}
+
+	return result, nil
+}
+
+// mergeListWithDirective deep-merges the list items into the list in `dst` according to the merge directive
+func mergeListWithDirective(directive string, dst any, items []any, appendSlice bool, sliceDeepCopy bool) ([]any, error) {
+	var dstItems []any
+	if dstList, ok := dst.([]any); ok {
+		_, dstItems = GetListMergeDirective(dstList)
+	}
+
+	switch directive {
+	case MergeDirectiveAppend:
+		return append(slices.Clone(dstItems), items...), nil
+
+	case MergeDirectivePrepend:
+		return append(slices.Clone(items), dstItems...), nil
+
+	case MergeDirectiveMerge:
+		merged := slices.Clone(dstItems)
+		for i, item := range items {
+			if i >= len(merged) {
+				merged = append(merged, item)
+				continue
+			}
+
+			dstItemMap, dstIsMap := merged[i].(map[string]any)
+			srcItemMap, srcIsMap := item.(map[string]any)
+			if !dstIsMap || !srcIsMap {
+				merged[i] = item
+				continue
+			}
+
+			mergedItem, err := MergeWithOptions([]map[string]any{dstItemMap, srcItemMap}, appendSlice, sliceDeepCopy)
+			if err != nil {
+				return nil, err
+			}
+			merged[i] = mergedItem
+		}
+		return merged, nil
+	}
+
+	return items, nil
+}
+
+// setMergeDirectiveValue sets the value at the path in the map
+func setMergeDirectiveValue(m map[string]any, path []string, value any) {
+	for _, k := range path[:len(path)-1] {
+		next, ok := m[k].(map[string]any)
+		if !ok {
+			next = map[string]any{}
+			m[k] = next
+		}
+		m = next
+	}
+	m[path[len(path)-1]] = value
+}
@@ -145,3 +145,163 @@ func TestMergeListMerge(t *testing.T) {
 	assert.Nil(t, err)
 	t.Log(yamlConfig)
 }
+
+func TestMergeDirectives(t *testing.T) {
+	atmosConfig := schema.AtmosConfiguration{
+		Settings: schema.AtmosSettings{
+			ListMergeStrategy: ListMergeStrategyReplace,
+		},
+	}
+
+	base, err := u.UnmarshalYAML[map[string]any](`
+allowed_cidrs: ["10.0.0.0/8"]
+subnets: ["a", "b"]
+zones: ["us-east-2a"]
+rules:
+  - name: ssh
+    port: 22
+tags:
+  Team: network
+  Owner: platform
+`)
+	assert.Nil(t, err)
+
+	override, err := u.UnmarshalYAML[map[string]any](`
+allowed_cidrs: !append ["172.16.0.0/12"]
+subnets: ["c"]
+zones: !prepend ["us-east-2b"]
+rules: !merge
+  - port: 2222
+  - name: https
+    port: 443
+tags: !replace
+  Team: platform
+`)
+	assert.Nil(t, err)
+
+	result, err := Merge(atmosConfig, []map[string]any{base, override})
+	assert.Nil(t, err)
+
+	result = RemoveMergeDirectives(result).(map[string]any)
+
+	assert.Equal(t, []any{"10.0.0.0/8", "172.16.0.0/12"}, result["allowed_cidrs"])
+	assert.Equal(t, []any{"c"}, result["subnets"])
+	assert.Equal(t, []any{"us-east-2b", "us-east-2a"}, result["zones"])
+	assert.Equal(t, []any{
+		map[string]any{"name": "ssh", "port": 2222},
+		map[string]any{"name": "https", "port": 443},
+	}, result["rules"])
+	assert.Equal(t, map[string]any{"Team": "platform"}, result["tags"])
+}
+
+func TestMergeDirectivesWithAppendStrategy(t *testing.T) {
+	atmosConfig := schema.AtmosConfiguration{
+		Settings: schema.AtmosSettings{
+			ListMergeStrategy: ListMergeStrategyAppend,
+		},
+	}
+
+	global, err := u.UnmarshalYAML[map[string]any](`
+vars:
+  allowed_cidrs: ["10.0.0.0/8"]
+  subnets: ["a", "b"]
+`)
+	assert.Nil(t, err)
+
+	component, err := u.UnmarshalYAML[map[string]any](`
+vars:
+  allowed_cidrs: ["172.16.0.0/12"]
+  subnets: !replace ["c"]
+`)
+	assert.Nil(t, err)
+
+	overrides, err := u.UnmarshalYAML[map[string]any](`
+vars:
+  subnets: ["d"]
+`)
+	assert.Nil(t, err)
+
+	result, err := Merge(atmosConfig, []map[string]any{global, component, overrides})
+	assert.Nil(t, err)
+
+	vars := RemoveMergeDirectives(result["vars"]).(map[string]any)
+
+	// The lists without directives are deep-merged with the list merge strategy
+	assert.Equal(t, []any{"10.0.0.0/8", "172.16.0.0/12"}, vars["allowed_cidrs"])
+	assert.Equal(t, []any{"c", "d"}, vars["subnets"])
+}
+
+func TestMergeDirectivesInvalid(t *testing.T) {
+	_, err := u.UnmarshalYAML[map[string]any](`
+tags: !append
+  Team: platform
+`)
+	assert.ErrorContains(t, err, "invalid merge directive '!append'")
+
+	_, err = u.UnmarshalYAML[map[string]any](`
+region: !replace us-east-2
+`)
+	assert.ErrorContains(t, err, "can only be used on lists and maps")
+}
+
+func TestMergeUnset(t *testing.T) {
+	atmosConfig := schema.AtmosConfiguration{
+		Settings: schema.AtmosSettings{
+			ListMergeStrategy: ListMergeStrategyAppend,
+		},
+	}
+
+	base, err := u.UnmarshalYAML[map[string]any](`
+vars:
+  flow_logs_enabled: true
+  subnets: ["a", "b"]
+  tags:
+    Team: network
+    Owner: platform
+`)
+	assert.Nil(t, err)
+
+	component, err := u.UnmarshalYAML[map[string]any](`
+vars:
+  flow_logs_enabled: !unset
+  subnets: !unset
+  tags:
+    Owner: !unset
+`)
+	assert.Nil(t, err)
+
+	result, err := Merge(atmosConfig, []map[string]any{base, component})
+	assert.Nil(t, err)
+
+	// The unset keys are kept in the deep-merged result, so they are also unset when it's deep-merged with the base components
+	inherited, err := Merge(atmosConfig, []map[string]any{base, result})
+	assert.Nil(t, err)
+
+	for _, merged := range []map[string]any{result, inherited} {
+		vars := RemoveMergeDirectives(merged["vars"]).(map[string]any)
+		assert.Equal(t, map[string]any{"tags": map[string]any{"Team": "network"}}, vars)
+	}
+
+	// The keys can be set again after they are unset
+	override, err := u.UnmarshalYAML[map[string]any](`
+vars:
+  subnets: ["c"]
+  tags:
+    Owner: security
+`)
+	assert.Nil(t, err)
+
+	result, err = Merge(atmosConfig, []map[string]any{base, component, override})
+	assert.Nil(t, err)
+
+	vars := RemoveMergeDirectives(result["vars"]).(map[string]any)
+	assert.Equal(t, []any{"c"}, vars["subnets"])
+	assert.Equal(t, map[string]any{"Team": "network", "Owner": "security"}, vars["tags"])
+	assert.NotContains(t, vars, "flow_logs_enabled")
+
+	_, err = u.UnmarshalYAML[map[string]any](`
+subnets:
+  - !unset
+`)
+	assert.ErrorContains(t, err, "can only be used on the values of map keys")
+}
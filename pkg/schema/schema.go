@@ -21,6 +21,7 @@ type AtmosConfiguration struct {
 	Settings                      AtmosSettings      `yaml:"settings,omitempty" json:"settings,omitempty" mapstructure:"settings"`
 	StoresConfig                  store.StoresConfig `yaml:"stores,omitempty" json:"stores,omitempty" mapstructure:"stores"`
 	Vendor                        Vendor             `yaml:"vendor,omitempty" json:"vendor,omitempty" mapstructure:"vendor"`
+	Audit                         Audit              `yaml:"audit,omitempty" json:"audit,omitempty" mapstructure:"audit"`
 	Initialized                   bool               `yaml:"initialized" json:"initialized" mapstructure:"initialized"`
 	StacksBaseAbsolutePath        string             `yaml:"stacksBaseAbsolutePath,omitempty" json:"stacksBaseAbsolutePath,omitempty" mapstructure:"stacksBaseAbsolutePath"`
 	IncludeStackAbsolutePaths     []string           `yaml:"includeStackAbsolutePaths,omitempty" json:"includeStackAbsolutePaths,omitempty" mapstructure:"includeStackAbsolutePaths"`
@@ -121,15 +122,26 @@ type TemplatesSettingsGomplate struct {
 }
 
 type Terraform struct {
-	BasePath                string      `yaml:"base_path" json:"base_path" mapstructure:"base_path"`
-	ApplyAutoApprove        bool        `yaml:"apply_auto_approve" json:"apply_auto_approve" mapstructure:"apply_auto_approve"`
-	AppendUserAgent         string      `yaml:"append_user_agent" json:"append_user_agent" mapstructure:"append_user_agent"`
-	DeployRunInit           bool        `yaml:"deploy_run_init" json:"deploy_run_init" mapstructure:"deploy_run_init"`
-	InitRunReconfigure      bool        `yaml:"init_run_reconfigure" json:"init_run_reconfigure" mapstructure:"init_run_reconfigure"`
-	AutoGenerateBackendFile bool        `yaml:"auto_generate_backend_file" json:"auto_generate_backend_file" mapstructure:"auto_generate_backend_file"`
-	WorkspacesEnabled       *bool       `yaml:"workspaces_enabled,omitempty" json:"workspaces_enabled,omitempty" mapstructure:"workspaces_enabled,omitempty"`
-	Command                 string      `yaml:"command" json:"command" mapstructure:"command"`
-	Shell                   ShellConfig `yaml:"shell" json:"shell" mapstructure:"shell"`
+	BasePath                string                `yaml:"base_path" json:"base_path" mapstructure:"base_path"`
+	ApplyAutoApprove        bool                  `yaml:"apply_auto_approve" json:"apply_auto_approve" mapstructure:"apply_auto_approve"`
+	AppendUserAgent         string                `yaml:"append_user_agent" json:"append_user_agent" mapstructure:"append_user_agent"`
+	DeployRunInit           bool                  `yaml:"deploy_run_init" json:"deploy_run_init" mapstructure:"deploy_run_init"`
+	InitRunReconfigure      bool                  `yaml:"init_run_reconfigure" json:"init_run_reconfigure" mapstructure:"init_run_reconfigure"`
+	AutoGenerateBackendFile bool                  `yaml:"auto_generate_backend_file" json:"auto_generate_backend_file" mapstructure:"auto_generate_backend_file"`
+	WorkspacesEnabled       *bool                 `yaml:"workspaces_enabled,omitempty" json:"workspaces_enabled,omitempty" mapstructure:"workspaces_enabled,omitempty"`
+	Command                 string                `yaml:"command" json:"command" mapstructure:"command"`
+	Shell                   ShellConfig           `yaml:"shell" json:"shell" mapstructure:"shell"`
+	OutputsCache            TerraformOutputsCache `yaml:"outputs_cache" json:"outputs_cache" mapstructure:"outputs_cache"`
+}
+
+// TerraformOutputsCache configures the on-disk cache of the Terraform outputs used by the `!terraform.output` YAML function
+// and the `atmos.Component` template function
+type TerraformOutputsCache struct {
+	Enabled bool `yaml:"enabled" json:"enabled" mapstructure:"enabled"`
+	// The time-to-live of the cached outputs as a Go duration (e.g. `30m`, `12h`)
+	TTL string `yaml:"ttl" json:"ttl" mapstructure:"ttl"`
+	// The cache directory. Defaults to `$XDG_CACHE_HOME/atmos/terraform-outputs`
+	Path string `yaml:"path,omitempty" json:"path,omitempty" mapstructure:"path"`
 }
 
 type ShellConfig struct {
@@ -151,11 +163,18 @@ type Components struct {
 }
 
 type Stacks struct {
-	BasePath      string   `yaml:"base_path" json:"base_path" mapstructure:"base_path"`
-	IncludedPaths []string `yaml:"included_paths" json:"included_paths" mapstructure:"included_paths"`
-	ExcludedPaths []string `yaml:"excluded_paths" json:"excluded_paths" mapstructure:"excluded_paths"`
-	NamePattern   string   `yaml:"name_pattern" json:"name_pattern" mapstructure:"name_pattern"`
-	NameTemplate  string   `yaml:"name_template" json:"name_template" mapstructure:"name_template"`
+	BasePath      string        `yaml:"base_path" json:"base_path" mapstructure:"base_path"`
+	IncludedPaths []string      `yaml:"included_paths" json:"included_paths" mapstructure:"included_paths"`
+	ExcludedPaths []string      `yaml:"excluded_paths" json:"excluded_paths" mapstructure:"excluded_paths"`
+	NamePattern   string        `yaml:"name_pattern" json:"name_pattern" mapstructure:"name_pattern"`
+	NameTemplate  string        `yaml:"name_template" json:"name_template" mapstructure:"name_template"`
+	Imports       StacksImports `yaml:"imports,omitempty" json:"imports,omitempty" mapstructure:"imports"`
+}
+
+// StacksImports configures the remote imports (Git repositories, OCI images and other go-getter sources) in stack manifests
+type StacksImports struct {
+	// AllowUnpinned allows the remote imports that are not pinned to a tag, a commit SHA, an image digest or a checksum
+	AllowUnpinned bool `yaml:"allow_unpinned" json:"allow_unpinned" mapstructure:"allow_unpinned"`
 }
 
 type Workflows struct {
@@ -227,6 +246,21 @@ type ArgsAndFlagsInfo struct {
 	LogsFile                  string
 	SettingsListMergeStrategy string
 	Query                     string
+	All                       bool
+	Components                []string
+	MaxConcurrency            int
+	Affected                  bool
+	Ref                       string
+	SHA                       string
+	RepoPath                  string
+	SSHKeyPath                string
+	SSHKeyPassword            string
+	CloneTargetRef            bool
+	IncludeDependents         bool
+	PlanSummary               bool
+	PlanSummaryFormat         string
+	PlanSummaryFile           string
+	WorkspaceLockTimeout      string
 }
 
 type ConfigAndStacksInfo struct {
@@ -293,6 +327,21 @@ type ConfigAndStacksInfo struct {
 	LogsFile                      string
 	SettingsListMergeStrategy     string
 	Query                         string
+	All                           bool
+	Components                    []string
+	MaxConcurrency                int
+	Affected                      bool
+	Ref                           string
+	SHA                           string
+	RepoPath                      string
+	SSHKeyPath                    string
+	SSHKeyPassword                string
+	CloneTargetRef                bool
+	IncludeDependents             bool
+	PlanSummary                   bool
+	PlanSummaryFormat             string
+	PlanSummaryFile               string
+	WorkspaceLockTimeout          string
 }
 
 // Workflows
@@ -362,6 +411,8 @@ type VendorComponentMixins struct {
 type VendorComponentSpec struct {
 	Source VendorComponentSource   `yaml:"source" json:"source" mapstructure:"source"`
 	Mixins []VendorComponentMixins `yaml:"mixins" json:"mixins" mapstructure:"mixins"`
+	// Patches are the unified diff files (relative to the component folder) applied to the component after vendoring
+	Patches []string `yaml:"patches,omitempty" json:"patches,omitempty" mapstructure:"patches"`
 }
 
 type VendorComponentMetadata struct {
@@ -611,11 +662,22 @@ type AtmosVendorSource struct {
 	Component     string   `yaml:"component" json:"component" mapstructure:"component"`
 	Source        string   `yaml:"source" json:"source" mapstructure:"source"`
 	Version       string   `yaml:"version" json:"version" mapstructure:"version"`
+	Constraints   string   `yaml:"constraints,omitempty" json:"constraints,omitempty" mapstructure:"constraints"`
 	File          string   `yaml:"file" json:"file" mapstructure:"file"`
 	Targets       []string `yaml:"targets" json:"targets" mapstructure:"targets"`
 	IncludedPaths []string `yaml:"included_paths,omitempty" json:"included_paths,omitempty" mapstructure:"included_paths"`
 	ExcludedPaths []string `yaml:"excluded_paths,omitempty" json:"excluded_paths,omitempty" mapstructure:"excluded_paths"`
 	Tags          []string `yaml:"tags" json:"tags" mapstructure:"tags"`
+	Patches       []string `yaml:"patches,omitempty" json:"patches,omitempty" mapstructure:"patches"`
+	// Checksum is the SHA-256 checksum of the downloaded archive or the tree hash of the vendored files, in the format `sha256:<hex>`
+	Checksum  string                `yaml:"checksum,omitempty" json:"checksum,omitempty" mapstructure:"checksum"`
+	Signature *AtmosVendorSignature `yaml:"signature,omitempty" json:"signature,omitempty" mapstructure:"signature"`
+}
+
+// AtmosVendorSignature configures the verification of the cosign signatures of OCI sources
+type AtmosVendorSignature struct {
+	// Key is the path to the PEM-encoded public key (relative to the vendor config file), or the PEM-encoded public key
+	Key string `yaml:"key" json:"key" mapstructure:"key"`
 }
 
 type AtmosVendorSpec struct {
@@ -635,10 +697,82 @@ type AtmosVendorConfig struct {
 	Spec       AtmosVendorSpec `yaml:"spec" json:"spec" mapstructure:"spec"`
 }
 
+// Atmos vendoring lock file (`vendor.lock.yaml` file)
+
+type AtmosVendorLockSource struct {
+	Component string `yaml:"component,omitempty" json:"component,omitempty" mapstructure:"component"`
+	Source    string `yaml:"source" json:"source" mapstructure:"source"`
+	Version   string `yaml:"version,omitempty" json:"version,omitempty" mapstructure:"version"`
+	Target    string `yaml:"target" json:"target" mapstructure:"target"`
+	// Resolved is the commit SHA of a Git source or the digest of an OCI image
+	Resolved string `yaml:"resolved,omitempty" json:"resolved,omitempty" mapstructure:"resolved"`
+	// TreeHash is the SHA-256 hash of all the files copied to the target
+	TreeHash string `yaml:"tree_hash" json:"tree_hash" mapstructure:"tree_hash"`
+	// Files maps the paths of the copied files (relative to the vendor config file) to their SHA-256 hashes
+	Files map[string]string `yaml:"files" json:"files" mapstructure:"files"`
+	// Patches maps the paths of the applied patch files (relative to the vendor config file) to their SHA-256 hashes
+	Patches map[string]string `yaml:"patches,omitempty" json:"patches,omitempty" mapstructure:"patches"`
+}
+
+type AtmosVendorLock struct {
+	ApiVersion string                  `yaml:"apiVersion" json:"apiVersion" mapstructure:"apiVersion"`
+	Kind       string                  `yaml:"kind" json:"kind" mapstructure:"kind"`
+	Sources    []AtmosVendorLockSource `yaml:"sources" json:"sources" mapstructure:"sources"`
+}
+
+// Atmos stacks lock file (`stacks.lock.yaml` file) with the revisions of the remote imports in stack manifests
+
+type AtmosStacksLockImport struct {
+	Source string `yaml:"source" json:"source" mapstructure:"source"`
+	// Resolved is the commit SHA of a Git source or the digest of an OCI image
+	Resolved string `yaml:"resolved,omitempty" json:"resolved,omitempty" mapstructure:"resolved"`
+	// Pinned is `true` if the source is pinned to a tag, a commit SHA, an image digest or a checksum
+	Pinned bool `yaml:"pinned" json:"pinned" mapstructure:"pinned"`
+}
+
+type AtmosStacksLock struct {
+	ApiVersion string                  `yaml:"apiVersion" json:"apiVersion" mapstructure:"apiVersion"`
+	Kind       string                  `yaml:"kind" json:"kind" mapstructure:"kind"`
+	Imports    []AtmosStacksLockImport `yaml:"imports" json:"imports" mapstructure:"imports"`
+}
+
 type Vendor struct {
 	// Path to vendor configuration file or directory containing vendor files
 	// If a directory is specified, all .yaml files in the directory will be processed in lexicographical order
 	BasePath string `yaml:"base_path" json:"base_path" mapstructure:"base_path"`
+	// Maximum number of sources downloaded in parallel
+	Concurrency int `yaml:"concurrency,omitempty" json:"concurrency,omitempty" mapstructure:"concurrency"`
+	// Number of times a failed download is retried (with exponential backoff)
+	Retries int `yaml:"retries,omitempty" json:"retries,omitempty" mapstructure:"retries"`
+}
+
+// Audit configures the audit log of the Terraform and Helmfile commands executed by Atmos
+type Audit struct {
+	Enabled bool `yaml:"enabled" json:"enabled" mapstructure:"enabled"`
+	// The audit log file (JSON lines). Relative paths are relative to `base_path`.
+	// Defaults to `audit.jsonl` in a subdirectory per Atmos project in `$XDG_CACHE_HOME/atmos/audit`
+	Path string `yaml:"path,omitempty" json:"path,omitempty" mapstructure:"path"`
+	// The size of the audit log file in megabytes at which it is rotated. If `0`, the file is not rotated
+	MaxSize int `yaml:"max_size,omitempty" json:"max_size,omitempty" mapstructure:"max_size"`
+	// The number of rotated audit log files to keep
+	MaxBackups int `yaml:"max_backups,omitempty" json:"max_backups,omitempty" mapstructure:"max_backups"`
+	// The optional sinks the audit records are also written to
+	Sinks AuditSinks `yaml:"sinks,omitempty" json:"sinks,omitempty" mapstructure:"sinks"`
+}
+
+type AuditSinks struct {
+	// The name of the store (from the `stores` section) to write the audit records to
+	Store string        `yaml:"store,omitempty" json:"store,omitempty" mapstructure:"store"`
+	HTTP  AuditHTTPSink `yaml:"http,omitempty" json:"http,omitempty" mapstructure:"http"`
+}
+
+// AuditHTTPSink configures the HTTP endpoint the audit records are posted to as JSON
+type AuditHTTPSink struct {
+	URL string `yaml:"url,omitempty" json:"url,omitempty" mapstructure:"url"`
+	// The headers of the requests. The values can reference environment variables (e.g. `Bearer ${AUDIT_TOKEN}`)
+	Headers map[string]string `yaml:"headers,omitempty" json:"headers,omitempty" mapstructure:"headers"`
+	// The timeout of the requests as a Go duration. Defaults to `5s`
+	Timeout string `yaml:"timeout,omitempty" json:"timeout,omitempty" mapstructure:"timeout"`
 }
 
 type MarkdownSettings struct {
@@ -25,6 +25,33 @@ const (
 	AtmosYamlFuncIncludeGoGetter  = "!include-go-getter"
 )
 
+const (
+	// Atmos YAML merge directives.
+	// They override the list merge strategy (`settings.list_merge_strategy` in `atmos.yaml`) for specific lists and maps
+	AtmosYamlMergeAppend  = "!append"
+	AtmosYamlMergePrepend = "!prepend"
+	AtmosYamlMergeReplace = "!replace"
+	AtmosYamlMergeMerge   = "!merge"
+
+	// AtmosYamlUnset removes the key from the deep-merged config
+	AtmosYamlUnset = "!unset"
+
+	// AtmosMergeDirectiveMarker marks the lists and maps with merge directives.
+	// The marker is the first item of a list (`__atmos_merge_directive__:append`),
+	// or a key in a map (`__atmos_merge_directive__: replace`)
+	AtmosMergeDirectiveMarker = "__atmos_merge_directive__"
+
+	// AtmosUnsetMarker is the value of the keys tagged with `!unset`
+	AtmosUnsetMarker = "__atmos_unset__"
+)
+
+var AtmosYamlMergeDirectives = []string{
+	AtmosYamlMergeAppend,
+	AtmosYamlMergePrepend,
+	AtmosYamlMergeReplace,
+	AtmosYamlMergeMerge,
+}
+
 var AtmosYamlTags = []string{
 	AtmosYamlFuncExec,
 	AtmosYamlFuncStore,
@@ -92,6 +119,18 @@ func processCustomTags(atmosConfig *schema.AtmosConfiguration, node *yaml.Node,
 	for i := 0; i < len(node.Content); i++ {
 		n := node.Content[i]
 
+		if n.Tag == AtmosYamlUnset {
+			if err := processUnsetTag(node, n, file); err != nil {
+				return err
+			}
+		}
+
+		if SliceContainsString(AtmosYamlMergeDirectives, n.Tag) {
+			if err := processMergeDirectiveTag(n, file); err != nil {
+				return err
+			}
+		}
+
 		if SliceContainsString(AtmosYamlTags, n.Tag) {
 			val, err := getValueWithTag(atmosConfig, n, file)
 			if err != nil {
@@ -107,6 +146,47 @@ func processCustomTags(atmosConfig *schema.AtmosConfiguration, node *yaml.Node,
 	return nil
 }
 
+// processMergeDirectiveTag replaces the merge directive tag (e.g. `!append`) on a list or a map with the merge directive marker
+func processMergeDirectiveTag(n *yaml.Node, file string) error {
+	directive := strings.TrimPrefix(n.Tag, "!")
+
+	switch {
+	case n.Kind == yaml.SequenceNode:
+		n.Tag = "!!seq"
+		marker := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: AtmosMergeDirectiveMarker + ":" + directive}
+		n.Content = append([]*yaml.Node{marker}, n.Content...)
+
+	case n.Kind == yaml.MappingNode && (n.Tag == AtmosYamlMergeReplace || n.Tag == AtmosYamlMergeMerge):
+		n.Tag = "!!map"
+		key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: AtmosMergeDirectiveMarker}
+		value := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: directive}
+		n.Content = append([]*yaml.Node{key, value}, n.Content...)
+
+	case n.Kind == yaml.MappingNode:
+		return fmt.Errorf("invalid merge directive '%s' in the file '%s' at line %d. The directive can only be used on lists. "+
+			"Use '%s' or '%s' on maps", n.Tag, file, n.Line, AtmosYamlMergeReplace, AtmosYamlMergeMerge)
+
+	default:
+		return fmt.Errorf("invalid merge directive '%s' in the file '%s' at line %d. The merge directives can only be used on lists and maps",
+			n.Tag, file, n.Line)
+	}
+
+	return nil
+}
+
+// processUnsetTag replaces the value of the key tagged with `!unset` with the unset marker
+func processUnsetTag(parent *yaml.Node, n *yaml.Node, file string) error {
+	if parent.Kind != yaml.MappingNode || n.Kind != yaml.ScalarNode {
+		return fmt.Errorf("invalid '%s' in the file '%s' at line %d. The function can only be used on the values of map keys",
+			AtmosYamlUnset, file, n.Line)
+	}
+
+	n.Tag = "!!str"
+	n.Value = AtmosUnsetMarker
+
+	return nil
+}
+
 func getNodeValue(tag string, f string, q string) string {
 	t := tag + "-local-file \"" + f + "\""
 	if q == "" {
@@ -87,3 +87,54 @@ func TestValidateComponent4(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, "'service_1_name' variable length must be greater than 10 chars", err.Error())
 }
+
+func TestValidateComponentWithCue(t *testing.T) {
+	info := schema.ConfigAndStacksInfo{}
+
+	atmosConfig, err := cfg.InitCliConfig(info, true)
+	assert.Nil(t, err)
+
+	ok, err := e.ExecuteValidateComponent(
+		atmosConfig,
+		info,
+		"infra/vpc",
+		"tenant1-ue2-dev",
+		"vpc/validate-infra-vpc-component.cue",
+		"cue",
+		nil,
+		0)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+}
+
+func TestValidateWithCue(t *testing.T) {
+	schemaText := `
+vars: {
+	region: string
+	ipv4_primary_cidr_block: =~"^([0-9]{1,3}\\.){3}[0-9]{1,3}(/([0-9]|[1-2][0-9]|3[0-2]))?$"
+	map_public_ip_on_launch: bool
+	max_subnet_count: int & <=4
+}
+`
+	data := map[string]any{
+		"vars": map[string]any{
+			"ipv4_primary_cidr_block": "10.0.0.0/33",
+			"map_public_ip_on_launch": "yes",
+			"max_subnet_count":        3,
+		},
+	}
+
+	ok, err := e.ValidateWithCue(data, "vpc.cue", schemaText)
+	assert.False(t, ok)
+	assert.Error(t, err)
+
+	// All the violations are reported with their paths
+	assert.Contains(t, err.Error(), "vars.region")
+	assert.Contains(t, err.Error(), "vars.ipv4_primary_cidr_block")
+	assert.Contains(t, err.Error(), "vars.map_public_ip_on_launch")
+	assert.NotContains(t, err.Error(), "vars.max_subnet_count")
+
+	ok, err = e.ValidateWithCue(data, "invalid.cue", "vars: {")
+	assert.False(t, ok)
+	assert.Error(t, err)
+}
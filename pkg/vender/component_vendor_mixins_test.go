@@ -0,0 +1,67 @@
+package vender
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	e "github.com/cloudposse/atmos/internal/exec"
+	"github.com/cloudposse/atmos/pkg/schema"
+)
+
+func TestVendorComponentLocalMixins(t *testing.T) {
+	testDir := t.TempDir()
+
+	atmosConfig := schema.AtmosConfiguration{
+		BasePath: testDir,
+	}
+	atmosConfig.Logs.Level = "Info"
+
+	files := map[string]string{
+		"upstream/main.tf":              "locals {}\n",
+		"mixins/v1/providers.tf":        "provider \"aws\" {}\n",
+		"mixins/shared/versions.tf":     "terraform {}\n",
+		"mixins/shared/modules/main.tf": "variable \"name\" {}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(testDir, name)
+		err := os.MkdirAll(filepath.Dir(path), 0o755)
+		assert.Nil(t, err)
+		err = os.WriteFile(path, []byte(content), 0o644)
+		assert.Nil(t, err)
+	}
+
+	componentPath := filepath.Join(testDir, "components", "terraform", "myapp")
+	err := os.MkdirAll(componentPath, 0o755)
+	assert.Nil(t, err)
+
+	vendorComponentSpec := schema.VendorComponentSpec{
+		Source: schema.VendorComponentSource{
+			Uri: "../../../upstream",
+		},
+		Mixins: []schema.VendorComponentMixins{
+			// Local file relative to the component folder, with the version template and the filename override
+			{
+				Uri:      "../../../mixins/{{.Version}}/providers.tf",
+				Version:  "v1",
+				Filename: "providers.mixin.tf",
+			},
+			// Local directory copied into the component folder
+			{
+				Uri:      filepath.Join(testDir, "mixins", "shared"),
+				Filename: ".",
+			},
+		},
+	}
+
+	err = e.ExecuteComponentVendorInternal(atmosConfig, vendorComponentSpec, "myapp", componentPath, false)
+	assert.Nil(t, err)
+
+	assert.FileExists(t, filepath.Join(componentPath, "main.tf"))
+	assert.FileExists(t, filepath.Join(componentPath, "providers.mixin.tf"))
+	assert.NoFileExists(t, filepath.Join(componentPath, "providers.tf"))
+	assert.FileExists(t, filepath.Join(componentPath, "versions.tf"))
+	assert.FileExists(t, filepath.Join(componentPath, "modules", "main.tf"))
+}
@@ -0,0 +1,95 @@
+package vender
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	e "github.com/cloudposse/atmos/internal/exec"
+	"github.com/cloudposse/atmos/pkg/schema"
+)
+
+func TestVendorDiff(t *testing.T) {
+	testDir := t.TempDir()
+
+	atmosConfig := schema.AtmosConfiguration{
+		BasePath: testDir,
+	}
+	atmosConfig.Logs.Level = "Info"
+
+	// Upstream source
+	sourcePath := filepath.Join(testDir, "upstream")
+	err := os.MkdirAll(sourcePath, 0o755)
+	assert.Nil(t, err)
+	err = os.WriteFile(filepath.Join(sourcePath, "main.tf"), []byte("resource \"null_resource\" \"this\" {}\n"), 0o644)
+	assert.Nil(t, err)
+	err = os.WriteFile(filepath.Join(sourcePath, "variables.tf"), []byte("variable \"name\" {}\n"), 0o644)
+	assert.Nil(t, err)
+	err = os.WriteFile(filepath.Join(sourcePath, "README.md"), []byte("# upstream\n"), 0o644)
+	assert.Nil(t, err)
+
+	// Vendored target
+	targetPath := filepath.Join(testDir, "components", "terraform", "myapp")
+	err = os.MkdirAll(targetPath, 0o755)
+	assert.Nil(t, err)
+	err = os.WriteFile(filepath.Join(targetPath, "main.tf"), []byte("resource \"null_resource\" \"this\" {}\n"), 0o644)
+	assert.Nil(t, err)
+	err = os.WriteFile(filepath.Join(targetPath, "variables.tf"), []byte("variable \"name\" {}\n"), 0o644)
+	assert.Nil(t, err)
+	err = os.WriteFile(filepath.Join(targetPath, "backend.tf.json"), []byte("{}\n"), 0o644)
+	assert.Nil(t, err)
+
+	vendorConfigFile := filepath.Join(testDir, "vendor.yaml")
+	spec := schema.AtmosVendorSpec{
+		Sources: []schema.AtmosVendorSource{
+			{
+				Component:     "myapp",
+				Source:        "upstream",
+				Targets:       []string{"components/terraform/myapp"},
+				IncludedPaths: []string{"**/*.tf"},
+			},
+		},
+	}
+
+	t.Run("no drift", func(t *testing.T) {
+		drifted, err := e.ExecuteAtmosVendorDiffInternal(atmosConfig, vendorConfigFile, spec, "", nil, false)
+		assert.Nil(t, err)
+		assert.False(t, drifted)
+	})
+
+	t.Run("modified file", func(t *testing.T) {
+		err := os.WriteFile(filepath.Join(targetPath, "main.tf"), []byte("resource \"null_resource\" \"edited\" {}\n"), 0o644)
+		assert.Nil(t, err)
+
+		drifted, err := e.ExecuteAtmosVendorDiffInternal(atmosConfig, vendorConfigFile, spec, "myapp", nil, true)
+		assert.Nil(t, err)
+		assert.True(t, drifted)
+	})
+
+	t.Run("added and missing files", func(t *testing.T) {
+		err := os.WriteFile(filepath.Join(targetPath, "main.tf"), []byte("resource \"null_resource\" \"this\" {}\n"), 0o644)
+		assert.Nil(t, err)
+		err = os.Remove(filepath.Join(targetPath, "variables.tf"))
+		assert.Nil(t, err)
+
+		drifted, err := e.ExecuteAtmosVendorDiffInternal(atmosConfig, vendorConfigFile, spec, "", nil, false)
+		assert.Nil(t, err)
+		assert.True(t, drifted)
+
+		err = os.WriteFile(filepath.Join(targetPath, "variables.tf"), []byte("variable \"name\" {}\n"), 0o644)
+		assert.Nil(t, err)
+		err = os.WriteFile(filepath.Join(targetPath, "extra.tf"), []byte("locals {}\n"), 0o644)
+		assert.Nil(t, err)
+
+		drifted, err = e.ExecuteAtmosVendorDiffInternal(atmosConfig, vendorConfigFile, spec, "", nil, false)
+		assert.Nil(t, err)
+		assert.True(t, drifted)
+	})
+
+	t.Run("unknown component", func(t *testing.T) {
+		_, err := e.ExecuteAtmosVendorDiffInternal(atmosConfig, vendorConfigFile, spec, "unknown", nil, false)
+		assert.NotNil(t, err)
+	})
+}
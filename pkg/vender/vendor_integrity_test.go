@@ -0,0 +1,237 @@
+package vender
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/stretchr/testify/assert"
+
+	e "github.com/cloudposse/atmos/internal/exec"
+	cfg "github.com/cloudposse/atmos/pkg/config"
+	"github.com/cloudposse/atmos/pkg/schema"
+)
+
+func TestVendorChecksum(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	testDir := t.TempDir()
+
+	atmosConfig := schema.AtmosConfiguration{
+		BasePath: testDir,
+	}
+	atmosConfig.Logs.Level = "Info"
+
+	sourcePath := filepath.Join(testDir, "upstream")
+	err := os.MkdirAll(sourcePath, 0o755)
+	assert.Nil(t, err)
+	err = os.WriteFile(filepath.Join(sourcePath, "main.tf"), []byte("locals {}\n"), 0o644)
+	assert.Nil(t, err)
+
+	vendorConfigFile := filepath.Join(testDir, "vendor.yaml")
+	targetFile := filepath.Join(testDir, "components", "terraform", "myapp", "main.tf")
+
+	source := schema.AtmosVendorSource{
+		Component: "myapp",
+		Source:    "upstream",
+		Targets:   []string{"components/terraform/myapp"},
+	}
+
+	// Get the tree hash from the lock file
+	err = e.ExecuteAtmosVendorInternal(atmosConfig, vendorConfigFile, schema.AtmosVendorSpec{Sources: []schema.AtmosVendorSource{source}}, "", nil, false, false)
+	assert.Nil(t, err)
+
+	lock, _, err := e.ReadVendorLockFile(filepath.Join(testDir, cfg.AtmosVendorLockFileName))
+	assert.Nil(t, err)
+	assert.Len(t, lock.Sources, 1)
+
+	source.Checksum = strings.ToUpper(strings.TrimPrefix(lock.Sources[0].TreeHash, "sha256:"))
+	spec := schema.AtmosVendorSpec{Sources: []schema.AtmosVendorSource{source}}
+
+	err = e.ExecuteAtmosVendorInternal(atmosConfig, vendorConfigFile, spec, "", nil, false, false)
+	assert.NotNil(t, err)
+
+	source.Checksum = "sha256:" + source.Checksum
+	spec = schema.AtmosVendorSpec{Sources: []schema.AtmosVendorSource{source}}
+
+	t.Run("pull verifies the tree hash", func(t *testing.T) {
+		err := os.WriteFile(targetFile, []byte("# modified\n"), 0o644)
+		assert.Nil(t, err)
+
+		err = e.ExecuteAtmosVendorInternal(atmosConfig, vendorConfigFile, spec, "", nil, false, false)
+		assert.Nil(t, err)
+
+		content, err := os.ReadFile(targetFile)
+		assert.Nil(t, err)
+		assert.Equal(t, "locals {}\n", string(content))
+	})
+
+	t.Run("pull fails when the checksum does not match", func(t *testing.T) {
+		err := os.WriteFile(filepath.Join(sourcePath, "main.tf"), []byte("locals { a = 1 }\n"), 0o644)
+		assert.Nil(t, err)
+
+		err = e.ExecuteAtmosVendorInternal(atmosConfig, vendorConfigFile, spec, "", nil, false, false)
+		assert.Nil(t, err)
+
+		content, err := os.ReadFile(targetFile)
+		assert.Nil(t, err)
+		assert.Equal(t, "locals {}\n", string(content))
+	})
+
+	t.Run("pull verifies the checksum of archives", func(t *testing.T) {
+		var archive bytes.Buffer
+		gw := gzip.NewWriter(&archive)
+		tw := tar.NewWriter(gw)
+		content := []byte("locals { archive = true }\n")
+		err := tw.WriteHeader(&tar.Header{Name: "main.tf", Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		assert.Nil(t, err)
+		_, err = tw.Write(content)
+		assert.Nil(t, err)
+		assert.Nil(t, tw.Close())
+		assert.Nil(t, gw.Close())
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write(archive.Bytes())
+		}))
+		defer server.Close()
+
+		archiveHash := sha256.Sum256(archive.Bytes())
+		archiveTarget := filepath.Join(testDir, "components", "terraform", "archive", "main.tf")
+
+		archiveSource := schema.AtmosVendorSource{
+			Component: "archive",
+			Source:    server.URL + "/myapp.tar.gz",
+			Targets:   []string{"components/terraform/archive"},
+			Checksum:  "sha256:" + strings.Repeat("0", 64),
+		}
+
+		err = e.ExecuteAtmosVendorInternal(atmosConfig, vendorConfigFile, schema.AtmosVendorSpec{Sources: []schema.AtmosVendorSource{archiveSource}}, "", nil, false, false)
+		assert.Nil(t, err)
+		assert.NoFileExists(t, archiveTarget)
+
+		archiveSource.Checksum = "sha256:" + hex.EncodeToString(archiveHash[:])
+		err = e.ExecuteAtmosVendorInternal(atmosConfig, vendorConfigFile, schema.AtmosVendorSpec{Sources: []schema.AtmosVendorSource{archiveSource}}, "", nil, false, false)
+		assert.Nil(t, err)
+		assert.FileExists(t, archiveTarget)
+	})
+}
+
+func TestVendorSignature(t *testing.T) {
+	server := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	assert.Nil(t, err)
+
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	testDir := t.TempDir()
+
+	atmosConfig := schema.AtmosConfiguration{
+		BasePath: testDir,
+	}
+	atmosConfig.Logs.Level = "Info"
+
+	componentPath := filepath.Join(testDir, "components", "terraform", "upstream")
+	err = os.MkdirAll(componentPath, 0o755)
+	assert.Nil(t, err)
+	err = os.WriteFile(filepath.Join(componentPath, "main.tf"), []byte("locals {}\n"), 0o644)
+	assert.Nil(t, err)
+
+	ref := serverURL.Host + "/components/myapp:1.0.0"
+	digest, err := e.ExecuteVendorPushInternal(atmosConfig, "upstream", "terraform", componentPath, schema.VendorComponentSpec{}, ref, "")
+	assert.Nil(t, err)
+
+	// Sign the image the same way as `cosign sign --key`
+	signingKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+
+	payload := []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"%s"},"image":{"docker-manifest-digest":"%s"},"type":"cosign container image signature"},"optional":null}`,
+		strings.Split(ref, ":1.0.0")[0], digest))
+	payloadHash := sha256.Sum256(payload)
+	sig, err := ecdsa.SignASN1(rand.Reader, signingKey, payloadHash[:])
+	assert.Nil(t, err)
+
+	signatureImage, err := mutate.Append(empty.Image, mutate.Addendum{
+		Layer:       static.NewLayer(payload, "application/vnd.dev.cosign.simplesigning.v1+json"),
+		Annotations: map[string]string{"dev.cosignproject.cosign/signature": base64.StdEncoding.EncodeToString(sig)},
+	})
+	assert.Nil(t, err)
+
+	imageRef, err := name.ParseReference(ref)
+	assert.Nil(t, err)
+	err = remote.Write(imageRef.Context().Tag(strings.Replace(digest, ":", "-", 1)+".sig"), signatureImage)
+	assert.Nil(t, err)
+
+	writePublicKey := func(file string, key *ecdsa.PublicKey) {
+		der, err := x509.MarshalPKIXPublicKey(key)
+		assert.Nil(t, err)
+		err = os.WriteFile(filepath.Join(testDir, file), pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o644)
+		assert.Nil(t, err)
+	}
+
+	writePublicKey("cosign.pub", &signingKey.PublicKey)
+
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	writePublicKey("other.pub", &otherKey.PublicKey)
+
+	vendorConfigFile := filepath.Join(testDir, "vendor.yaml")
+	targetFile := filepath.Join(testDir, "components", "terraform", "myapp", "main.tf")
+
+	newSpec := func(key string) schema.AtmosVendorSpec {
+		return schema.AtmosVendorSpec{
+			Sources: []schema.AtmosVendorSource{
+				{
+					Component: "myapp",
+					Source:    "oci://" + ref,
+					Targets:   []string{"components/terraform/myapp"},
+					Signature: &schema.AtmosVendorSignature{Key: key},
+				},
+			},
+		}
+	}
+
+	t.Run("pull fails when the signature does not match the key", func(t *testing.T) {
+		err := e.ExecuteAtmosVendorInternal(atmosConfig, vendorConfigFile, newSpec("other.pub"), "", nil, false, false)
+		assert.Nil(t, err)
+		assert.NoFileExists(t, targetFile)
+	})
+
+	t.Run("pull verifies the signature", func(t *testing.T) {
+		err := e.ExecuteAtmosVendorInternal(atmosConfig, vendorConfigFile, newSpec("cosign.pub"), "", nil, false, false)
+		assert.Nil(t, err)
+		assert.FileExists(t, targetFile)
+	})
+
+	t.Run("signature is only supported for OCI sources", func(t *testing.T) {
+		spec := newSpec("cosign.pub")
+		spec.Sources[0].Source = "components/terraform/upstream"
+		err := e.ExecuteAtmosVendorInternal(atmosConfig, vendorConfigFile, spec, "", nil, false, false)
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,95 @@
+package vender
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	e "github.com/cloudposse/atmos/internal/exec"
+	cfg "github.com/cloudposse/atmos/pkg/config"
+	"github.com/cloudposse/atmos/pkg/schema"
+)
+
+func TestVendorLockFile(t *testing.T) {
+	testDir := t.TempDir()
+
+	atmosConfig := schema.AtmosConfiguration{
+		BasePath: testDir,
+	}
+	atmosConfig.Logs.Level = "Info"
+
+	sourcePath := filepath.Join(testDir, "upstream")
+	err := os.MkdirAll(sourcePath, 0o755)
+	assert.Nil(t, err)
+	err = os.WriteFile(filepath.Join(sourcePath, "main.tf"), []byte("resource \"null_resource\" \"this\" {}\n"), 0o644)
+	assert.Nil(t, err)
+	err = os.WriteFile(filepath.Join(sourcePath, "README.md"), []byte("# upstream\n"), 0o644)
+	assert.Nil(t, err)
+
+	vendorConfigFile := filepath.Join(testDir, "vendor.yaml")
+	lockFile := filepath.Join(testDir, cfg.AtmosVendorLockFileName)
+	targetFile := filepath.Join(testDir, "components", "terraform", "myapp", "main.tf")
+
+	spec := schema.AtmosVendorSpec{
+		Sources: []schema.AtmosVendorSource{
+			{
+				Component:     "myapp",
+				Source:        "upstream",
+				Targets:       []string{"components/terraform/myapp"},
+				IncludedPaths: []string{"**/*.tf"},
+			},
+		},
+	}
+
+	t.Run("pull writes the lock file", func(t *testing.T) {
+		err := e.ExecuteAtmosVendorInternal(atmosConfig, vendorConfigFile, spec, "", nil, false, false)
+		assert.Nil(t, err)
+		assert.FileExists(t, targetFile)
+
+		lock, exists, err := e.ReadVendorLockFile(lockFile)
+		assert.Nil(t, err)
+		assert.True(t, exists)
+		assert.Len(t, lock.Sources, 1)
+		assert.Equal(t, "myapp", lock.Sources[0].Component)
+		assert.Equal(t, "upstream", lock.Sources[0].Source)
+		assert.Equal(t, "components/terraform/myapp", lock.Sources[0].Target)
+		assert.Contains(t, lock.Sources[0].TreeHash, "sha256:")
+		assert.Contains(t, lock.Sources[0].Files, "components/terraform/myapp/main.tf")
+		assert.NotContains(t, lock.Sources[0].Files, "components/terraform/myapp/README.md")
+	})
+
+	t.Run("verify succeeds for unchanged files", func(t *testing.T) {
+		err := e.ExecuteVendorVerifyInternal(vendorConfigFile, "")
+		assert.Nil(t, err)
+	})
+
+	t.Run("verify fails for modified files", func(t *testing.T) {
+		err := os.WriteFile(targetFile, []byte("# edited\n"), 0o644)
+		assert.Nil(t, err)
+
+		err = e.ExecuteVendorVerifyInternal(vendorConfigFile, "myapp")
+		assert.NotNil(t, err)
+	})
+
+	t.Run("frozen pull restores the locked files", func(t *testing.T) {
+		err := e.ExecuteAtmosVendorInternal(atmosConfig, vendorConfigFile, spec, "", nil, false, true)
+		assert.Nil(t, err)
+
+		err = e.ExecuteVendorVerifyInternal(vendorConfigFile, "")
+		assert.Nil(t, err)
+	})
+
+	t.Run("frozen pull fails when upstream changed", func(t *testing.T) {
+		err := os.WriteFile(filepath.Join(sourcePath, "main.tf"), []byte("resource \"null_resource\" \"changed\" {}\n"), 0o644)
+		assert.Nil(t, err)
+
+		err = e.ExecuteAtmosVendorInternal(atmosConfig, vendorConfigFile, spec, "", nil, false, true)
+		assert.NotNil(t, err)
+
+		content, err := os.ReadFile(targetFile)
+		assert.Nil(t, err)
+		assert.Equal(t, "resource \"null_resource\" \"this\" {}\n", string(content))
+	})
+}
@@ -0,0 +1,94 @@
+package vender
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	e "github.com/cloudposse/atmos/internal/exec"
+	cfg "github.com/cloudposse/atmos/pkg/config"
+	"github.com/cloudposse/atmos/pkg/schema"
+)
+
+func TestVendorPatches(t *testing.T) {
+	testDir := t.TempDir()
+
+	atmosConfig := schema.AtmosConfiguration{
+		BasePath: testDir,
+	}
+	atmosConfig.Logs.Level = "Info"
+
+	sourcePath := filepath.Join(testDir, "upstream")
+	err := os.MkdirAll(sourcePath, 0o755)
+	assert.Nil(t, err)
+	err = os.WriteFile(filepath.Join(sourcePath, "main.tf"), []byte("resource \"null_resource\" \"this\" {\n  count = 1\n}\n"), 0o644)
+	assert.Nil(t, err)
+
+	patchesPath := filepath.Join(testDir, "patches")
+	err = os.MkdirAll(patchesPath, 0o755)
+	assert.Nil(t, err)
+	err = os.WriteFile(filepath.Join(patchesPath, "count.patch"), []byte(`--- a/main.tf
++++ b/main.tf
+@@ -1,3 +1,3 @@
+ resource "null_resource" "this" {
+-  count = 1
++  count = 2
+ }
+`), 0o644)
+	assert.Nil(t, err)
+
+	vendorConfigFile := filepath.Join(testDir, "vendor.yaml")
+	targetFile := filepath.Join(testDir, "components", "terraform", "myapp", "main.tf")
+
+	spec := schema.AtmosVendorSpec{
+		Sources: []schema.AtmosVendorSource{
+			{
+				Component: "myapp",
+				Source:    "upstream",
+				Targets:   []string{"components/terraform/myapp"},
+				Patches:   []string{"patches/count.patch"},
+			},
+		},
+	}
+
+	t.Run("pull applies the patches", func(t *testing.T) {
+		err := e.ExecuteAtmosVendorInternal(atmosConfig, vendorConfigFile, spec, "", nil, false, false)
+		assert.Nil(t, err)
+
+		content, err := os.ReadFile(targetFile)
+		assert.Nil(t, err)
+		assert.Contains(t, string(content), "count = 2")
+
+		lock, _, err := e.ReadVendorLockFile(filepath.Join(testDir, cfg.AtmosVendorLockFileName))
+		assert.Nil(t, err)
+		assert.Len(t, lock.Sources, 1)
+		assert.Contains(t, lock.Sources[0].Patches, "patches/count.patch")
+
+		err = e.ExecuteVendorVerifyInternal(vendorConfigFile, "")
+		assert.Nil(t, err)
+	})
+
+	t.Run("patched files are not drift", func(t *testing.T) {
+		drifted, err := e.ExecuteAtmosVendorDiffInternal(atmosConfig, vendorConfigFile, spec, "", nil, false)
+		assert.Nil(t, err)
+		assert.False(t, drifted)
+	})
+
+	t.Run("pull fails when the patch no longer applies", func(t *testing.T) {
+		err := os.WriteFile(filepath.Join(sourcePath, "main.tf"), []byte("resource \"null_resource\" \"this\" {\n  count = 3\n}\n"), 0o644)
+		assert.Nil(t, err)
+
+		// The target is not changed if a patch does not apply
+		err = e.ExecuteAtmosVendorInternal(atmosConfig, vendorConfigFile, spec, "", nil, false, false)
+		assert.Nil(t, err)
+
+		content, err := os.ReadFile(targetFile)
+		assert.Nil(t, err)
+		assert.Contains(t, string(content), "count = 2")
+
+		_, err = e.ExecuteAtmosVendorDiffInternal(atmosConfig, vendorConfigFile, spec, "", nil, false)
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,106 @@
+package vender
+
+import (
+	"io"
+	"log"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/stretchr/testify/assert"
+
+	e "github.com/cloudposse/atmos/internal/exec"
+	"github.com/cloudposse/atmos/pkg/schema"
+)
+
+func TestVendorPush(t *testing.T) {
+	server := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	assert.Nil(t, err)
+
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	testDir := t.TempDir()
+
+	atmosConfig := schema.AtmosConfiguration{
+		BasePath: testDir,
+	}
+	atmosConfig.Logs.Level = "Info"
+
+	componentPath := filepath.Join(testDir, "components", "terraform", "myapp")
+	files := map[string]string{
+		"main.tf":                     "locals {}\n",
+		"README.md":                   "# myapp\n",
+		"modules/label/main.tf":       "variable \"name\" {}\n",
+		"terraform.tfstate":           "{}\n",
+		".terraform/modules/x.json":   "{}\n",
+		"myapp.terraform.tfvars.json": "{}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(componentPath, name)
+		err := os.MkdirAll(filepath.Dir(path), 0o755)
+		assert.Nil(t, err)
+		err = os.WriteFile(path, []byte(content), 0o644)
+		assert.Nil(t, err)
+	}
+
+	ref := serverURL.Host + "/components/myapp:1.2.3"
+
+	vendorComponentSpec := schema.VendorComponentSpec{
+		Source: schema.VendorComponentSource{
+			ExcludedPaths: []string{"**/*.md"},
+		},
+	}
+
+	digest, err := e.ExecuteVendorPushInternal(atmosConfig, "myapp", "terraform", componentPath, vendorComponentSpec, ref, "")
+	assert.Nil(t, err)
+	assert.NotEmpty(t, digest)
+
+	imageRef, err := name.ParseReference(ref)
+	assert.Nil(t, err)
+
+	descriptor, err := remote.Get(imageRef)
+	assert.Nil(t, err)
+	assert.Equal(t, digest, descriptor.Digest.String())
+
+	image, err := descriptor.Image()
+	assert.Nil(t, err)
+
+	manifest, err := image.Manifest()
+	assert.Nil(t, err)
+	assert.Equal(t, "myapp", manifest.Annotations["org.opencontainers.image.title"])
+	assert.Equal(t, "1.2.3", manifest.Annotations["org.opencontainers.image.version"])
+
+	// Pushing the same files again produces the same digest
+	digest2, err := e.ExecuteVendorPushInternal(atmosConfig, "myapp", "terraform", componentPath, vendorComponentSpec, ref, "")
+	assert.Nil(t, err)
+	assert.Equal(t, digest, digest2)
+
+	// The pushed image can be vendored using the 'oci://' scheme
+	consumerPath := filepath.Join(testDir, "components", "terraform", "consumer")
+	err = os.MkdirAll(consumerPath, 0o755)
+	assert.Nil(t, err)
+
+	consumerSpec := schema.VendorComponentSpec{
+		Source: schema.VendorComponentSource{
+			Uri: "oci://" + ref,
+		},
+	}
+
+	err = e.ExecuteComponentVendorInternal(atmosConfig, consumerSpec, "consumer", consumerPath, false)
+	assert.Nil(t, err)
+
+	assert.FileExists(t, filepath.Join(consumerPath, "main.tf"))
+	assert.FileExists(t, filepath.Join(consumerPath, "modules", "label", "main.tf"))
+	assert.NoFileExists(t, filepath.Join(consumerPath, "README.md"))
+	assert.NoFileExists(t, filepath.Join(consumerPath, "terraform.tfstate"))
+	assert.NoFileExists(t, filepath.Join(consumerPath, "myapp.terraform.tfvars.json"))
+	assert.NoDirExists(t, filepath.Join(consumerPath, ".terraform"))
+}
@@ -0,0 +1,11 @@
+// https://cuelang.org/docs/tour
+// The CUE schema is unified with the component configuration.
+// All the conflicting values and the missing required fields are reported as validation errors
+
+vars: {
+	region: string
+
+	ipv4_primary_cidr_block: =~"^([0-9]{1,3}\\.){3}[0-9]{1,3}(/([0-9]|[1-2][0-9]|3[0-2]))?$"
+
+	map_public_ip_on_launch: bool
+}
@@ -0,0 +1,19 @@
+variable "stage" {
+  type = string
+}
+
+variable "cidr" {
+  type = string
+}
+
+variable "subnets" {
+  type = list(string)
+}
+
+variable "owner" {
+  type = string
+}
+
+variable "tags" {
+  type = map(string)
+}
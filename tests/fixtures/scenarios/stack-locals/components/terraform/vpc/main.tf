@@ -0,0 +1,31 @@
+variable "stage" {
+  type = string
+}
+
+variable "name" {
+  type = string
+}
+
+variable "cidr" {
+  type = string
+}
+
+variable "role_arn" {
+  type = string
+}
+
+variable "tags" {
+  type = map(string)
+}
+
+variable "labels" {
+  type = map(string)
+}
+
+variable "description" {
+  type = string
+}
+
+variable "label" {
+  type = string
+}